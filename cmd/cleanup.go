@@ -0,0 +1,78 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/facebookincubator/ttpforge/pkg/blocks"
+	"github.com/facebookincubator/ttpforge/pkg/logging"
+	"github.com/spf13/cobra"
+)
+
+func buildCleanupCommand(cfg *Config) *cobra.Command {
+	var ttpCfg blocks.TTPExecutionConfig
+	var last bool
+	cleanupCmd := &cobra.Command{
+		Use:   "cleanup [run-id|--last]",
+		Short: "Run the outstanding cleanup actions of a previous TTP run (such as one executed with --no-cleanup).",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if last == (len(args) == 1) {
+				return errors.New("specify either a run ID or --last")
+			}
+			// don't want confusing usage display for errors past this point
+			cmd.SilenceUsage = true
+
+			var runID string
+			if len(args) == 1 {
+				runID = args[0]
+			}
+			journal, err := cfg.openRunJournal(runID)
+			if err != nil {
+				return err
+			}
+			if !journal.State.CleanupPending {
+				logging.L().Infof("Run %v has no steps that need to be cleaned up", journal.ID())
+				return nil
+			}
+			logging.L().Infof("Cleaning up run %v of %v", journal.ID(), journal.State.TTPRef)
+
+			ttp, execCtx, err := cfg.loadJournaledTTP(journal, &ttpCfg)
+			if err != nil {
+				return err
+			}
+			if err := journal.Restore(ttp, execCtx); err != nil {
+				return err
+			}
+			if err := ttp.RunCleanup(*execCtx); err != nil {
+				return fmt.Errorf("failed to clean up run %v: %w", journal.ID(), err)
+			}
+			if journal.State.CleanupPending {
+				return fmt.Errorf("some steps of run %v could not be cleaned up - see the log above for details", journal.ID())
+			}
+			return nil
+		},
+	}
+	cleanupCmd.Flags().BoolVar(&last, "last", false, "Clean up the most recently started run")
+	cleanupCmd.Flags().UintVar(&ttpCfg.CleanupDelaySeconds, "cleanup-delay-seconds", 0, "Wait this long before starting cleanup")
+	return cleanupCmd
+}
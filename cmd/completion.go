@@ -22,6 +22,7 @@ package cmd
 import (
 	"strings"
 
+	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 )
 
@@ -119,3 +120,56 @@ func completeRepoName(cfg *Config, maxArgs int) func(cmd *cobra.Command, args []
 		return nil, cobra.ShellCompDirectiveDefault
 	}
 }
+
+// completeTTPArgs provides completion for `--arg name=value` flags using
+// the argument specifications of the TTP whose reference is the first
+// positional argument - names are completed first, then the allowed
+// values of enum, bool and choice arguments
+func completeTTPArgs(cfg *Config) func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 || !ensureConfigInitialized(cfg, cmd) {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		_, ttpAbsPath, err := cfg.repoCollection.ResolveTTPRef(args[0])
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		contents, err := afero.ReadFile(afero.NewOsFs(), ttpAbsPath)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		specs, err := parseArgSpecs(contents)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		name, value, hasValue := strings.Cut(toComplete, "=")
+		var completions []string
+		for _, spec := range specs {
+			if !hasValue {
+				// complete the argument name, described by its summary
+				if strings.HasPrefix(spec.Name, name) {
+					completions = append(completions, spec.Name+"=\t"+spec.Summary())
+				}
+				continue
+			}
+			if spec.Name != name {
+				continue
+			}
+			for _, suggestion := range spec.Suggestions() {
+				if !strings.HasPrefix(suggestion.Value, value) {
+					continue
+				}
+				completion := name + "=" + suggestion.Value
+				if suggestion.Description != "" {
+					completion += "\t" + suggestion.Description
+				}
+				completions = append(completions, completion)
+			}
+		}
+		if !hasValue {
+			return completions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+		}
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
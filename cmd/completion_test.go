@@ -0,0 +1,106 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompleteTTPArgs(t *testing.T) {
+	testConfigFilePath := filepath.Join(testResourcesDir, "test-config.yaml")
+	ttpRef := testRepoName + "//args/types/typed-args.yaml"
+
+	testCases := []struct {
+		name        string
+		toComplete  string
+		expected    []string
+		wantNoSpace bool
+	}{
+		{
+			name:       "all-names",
+			toComplete: "",
+			expected: []string{
+				"target=\tip, required",
+				"port=\tport, default: 8443, min: 1024",
+				"timeout=\tduration, default: 30s, max: 5m",
+				"mode=\tenum, default: fast",
+				"tags=\tlist, optional",
+			},
+			wantNoSpace: true,
+		},
+		{
+			name:        "name-prefix",
+			toComplete:  "ti",
+			expected:    []string{"timeout=\tduration, default: 30s, max: 5m"},
+			wantNoSpace: true,
+		},
+		{
+			name:       "enum-values",
+			toComplete: "mode=",
+			expected:   []string{"mode=fast\tSkip slow checks", "mode=full"},
+		},
+		{
+			name:       "enum-value-prefix",
+			toComplete: "mode=fu",
+			expected:   []string{"mode=full"},
+		},
+		{
+			name:       "no-suggestions",
+			toComplete: "target=",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var stdoutBuf, stderrBuf bytes.Buffer
+			rc := BuildRootCommand(&TestConfig{
+				Stdout: &stdoutBuf,
+				Stderr: &stderrBuf,
+			})
+			rc.SetOut(&stdoutBuf)
+			rc.SetArgs([]string{"__complete", "run", "-c", testConfigFilePath, ttpRef, "--arg", tc.toComplete})
+			logMutex.Lock()
+			err := rc.Execute()
+			logMutex.Unlock()
+			require.NoError(t, err)
+
+			// the last line of the output is the completion directive
+			lines := strings.Split(strings.TrimSpace(stdoutBuf.String()), "\n")
+			require.NotEmpty(t, lines)
+			directive := lines[len(lines)-1]
+			completions := lines[:len(lines)-1]
+			if len(tc.expected) == 0 {
+				assert.Empty(t, completions)
+			} else {
+				assert.Equal(t, tc.expected, completions)
+			}
+			if tc.wantNoSpace {
+				assert.Equal(t, ":6", directive)
+			} else {
+				assert.Equal(t, ":4", directive)
+			}
+		})
+	}
+}
@@ -29,8 +29,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/facebookincubator/ttpforge/pkg/logging"
+	"github.com/facebookincubator/ttpforge/pkg/mitre"
 	"github.com/facebookincubator/ttpforge/pkg/repos"
 	"github.com/spf13/afero"
 	"gopkg.in/yaml.v3"
@@ -45,6 +47,15 @@ type TestConfig struct {
 	// TTPExecutionContext
 	Stdout io.Writer
 	Stderr io.Writer
+	// RunsDir is where the state of TTP runs is journaled -
+	// runs are not journaled in tests unless it is set
+	RunsDir string
+	// SearchIndexPath is where `ttpforge search` caches its
+	// index - the index is not cached in tests unless it is set
+	SearchIndexPath string
+	// CacheDir is where `fetch_uri` steps cache downloaded
+	// files - files are not cached in tests unless it is set
+	CacheDir string
 }
 
 // Config stores the variables from the TTPForge global config file
@@ -52,6 +63,9 @@ type TestConfig struct {
 // should not touch it
 type Config struct {
 	RepoSpecs []repos.Spec `yaml:"repos"`
+	// RunRetention is how long the state of finished runs is kept
+	// - the default is defaultRunRetention
+	RunRetention time.Duration `yaml:"run_retention,omitempty"`
 
 	repoCollection repos.RepoCollection
 	cfgFile        string
@@ -63,6 +77,20 @@ var (
 	defaultConfigContents string
 	defaultConfigFileName = "config.yaml"
 	defaultResourceDir    = ".ttpforge"
+	defaultRunsDirName    = "runs"
+	// defaultRunRetention is how long the state of finished
+	// runs is kept unless the config file specifies otherwise
+	defaultRunRetention = 30 * 24 * time.Hour
+	// mitreCatalogueFileName is the MITRE ATT&CK catalogue
+	// written by `ttpforge mitre update`, which overrides
+	// the catalogue embedded in TTPForge
+	mitreCatalogueFileName = "mitre-attack.json"
+	// searchIndexFileName is where `ttpforge search` caches
+	// the information it extracts from installed TTPs
+	searchIndexFileName = "search-index.json"
+	// defaultCacheDirName is where `fetch_uri` steps
+	// cache downloaded files (by their checksum)
+	defaultCacheDirName = "cache"
 
 	logConfig logging.Config
 )
@@ -76,6 +104,93 @@ func getDefaultConfigFilePath() (string, error) {
 	return defaultConfigPath, nil
 }
 
+// runsDir returns the directory in which the state of
+// each TTP run is journaled, or an empty string if
+// runs should not be journaled (as in most unit tests)
+func (cfg *Config) runsDir() (string, error) {
+	if cfg.testCfg != nil {
+		return cfg.testCfg.RunsDir, nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, defaultResourceDir, defaultRunsDirName), nil
+}
+
+// runRetention returns how long the state of
+// finished runs is kept before it is deleted
+func (cfg *Config) runRetention() time.Duration {
+	if cfg.RunRetention > 0 {
+		return cfg.RunRetention
+	}
+	return defaultRunRetention
+}
+
+// searchIndexPath returns the path of the file in which
+// `ttpforge search` caches its index, or an empty string if
+// the index should not be cached (as in most unit tests)
+func (cfg *Config) searchIndexPath() (string, error) {
+	if cfg.testCfg != nil {
+		return cfg.testCfg.SearchIndexPath, nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, defaultResourceDir, searchIndexFileName), nil
+}
+
+// cacheDir returns the directory in which downloaded files
+// are cached, or an empty string if downloaded files should
+// not be cached (as in most unit tests)
+func (cfg *Config) cacheDir() (string, error) {
+	if cfg.testCfg != nil {
+		return cfg.testCfg.CacheDir, nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, defaultResourceDir, defaultCacheDirName), nil
+}
+
+// mitreCataloguePath returns the path of the MITRE ATT&CK
+// catalogue written by `ttpforge mitre update`
+func mitreCataloguePath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, defaultResourceDir, mitreCatalogueFileName), nil
+}
+
+// loadMitreCatalogue replaces the embedded MITRE ATT&CK
+// catalogue with the one written by `ttpforge mitre update`,
+// if any - unit tests always use the embedded catalogue
+func (cfg *Config) loadMitreCatalogue() error {
+	if cfg.testCfg != nil {
+		return nil
+	}
+	cataloguePath, err := mitreCataloguePath()
+	if err != nil {
+		return fmt.Errorf("could not lookup MITRE ATT&CK catalogue path: %w", err)
+	}
+	contents, err := os.ReadFile(cataloguePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	catalogue, err := mitre.Parse(contents)
+	if err != nil {
+		return fmt.Errorf("invalid MITRE ATT&CK catalogue %v (run `ttpforge mitre update` again?): %w", cataloguePath, err)
+	}
+	mitre.SetDefault(catalogue)
+	return nil
+}
+
 // loadRepoCollection verifies that all repositories specified
 // in the configuration file are present on the filesystem
 // and clones missing ones if needed
@@ -148,6 +263,10 @@ func (cfg *Config) init() error {
 		}
 	}
 
+	if err := cfg.loadMitreCatalogue(); err != nil {
+		return err
+	}
+
 	cfg.repoCollection, err = cfg.loadRepoCollection()
 	return err
 }
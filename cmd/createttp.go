@@ -40,12 +40,13 @@ requirements:
   platforms:
     - os: {{.Platform.OS}}
 mitre:
+  # {{.Placeholder}} replace these examples with the MITRE ATT&CK IDs of this TTP
   tactics:
-    - {{.Placeholder}}
+    - TA0002 Execution
   techniques:
-    - {{.Placeholder}}
+    - T1059 Command and Scripting Interpreter
   subtechniques:
-    - {{.Placeholder}}
+    - "T1059.004 Command and Scripting Interpreter: Unix Shell"
 args:
   - name: {{.Placeholder}}
     description: {{.Placeholder}}
@@ -52,6 +52,10 @@ func TestCreateTTP(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
+			// keep the config and run state of the commands
+			// out of the real home directory
+			t.Setenv("HOME", t.TempDir())
+
 			// make disposable temp dir for testing
 			tmpDir, err := os.MkdirTemp("", "ttpforge-testing")
 			require.NoError(t, err)
@@ -75,10 +79,18 @@ func TestCreateTTP(t *testing.T) {
 			require.NoError(t, err)
 
 			// confirm that it is a valid YAML that we can run
-			runCmd := BuildRootCommand(nil)
+			runsDir := filepath.Join(tmpDir, "runs")
+			runCmd := BuildRootCommand(&TestConfig{
+				RunsDir: runsDir,
+			})
 			runCmd.SetArgs([]string{"run", newFilePath})
 			err = runCmd.Execute()
 			require.NoError(t, err)
+
+			// the run is journaled in the test runs directory
+			runs, err := os.ReadDir(runsDir)
+			require.NoError(t, err)
+			require.Len(t, runs, 1)
 		})
 	}
 }
@@ -0,0 +1,154 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/facebookincubator/ttpforge/pkg/coverage"
+	"github.com/facebookincubator/ttpforge/pkg/logging"
+	"github.com/facebookincubator/ttpforge/pkg/mitre"
+	"github.com/facebookincubator/ttpforge/pkg/parseutils"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+// gatherCoverage aggregates the MITRE ATT&CK mappings of the provided
+// TTPs - TTPs that cannot be read or parsed are skipped with a warning
+func gatherCoverage(cfg *Config, ttpRefs []string) *coverage.Coverage {
+	cov := coverage.New(mitre.Default())
+	for _, ttpRef := range ttpRefs {
+		repo, path, err := cfg.repoCollection.ResolveTTPRef(ttpRef)
+		if err != nil {
+			logging.L().Warnf("Skipping TTP %v: %v", ttpRef, err)
+			continue
+		}
+		content, err := afero.ReadFile(repo.GetFs(), path)
+		if err != nil {
+			logging.L().Warnf("Skipping TTP %v: %v", ttpRef, err)
+			continue
+		}
+		ttp, err := parseutils.ParseTTP(content, path)
+		if err != nil {
+			logging.L().Warnf("Skipping TTP %v: %v", ttpRef, err)
+			continue
+		}
+		cov.Add(ttpRef, ttp)
+	}
+	return cov
+}
+
+func buildEnumCoverageCommand(cfg *Config) *cobra.Command {
+	var format string
+	var repoName string
+	var platform string
+	var outputPath string
+	enumCoverageCmd := &cobra.Command{
+		Use:   "coverage",
+		Short: "Report the MITRE ATT&CK techniques covered by TTPs",
+		Long: `
+Report which MITRE ATT&CK techniques and sub-techniques are covered
+by the TTPs in all installed repositories (or in the repository
+selected with --repo), based on the mitre: section of each TTP.
+
+The navigator format is a layer that can be opened in the ATT&CK
+Navigator (https://mitre-attack.github.io/attack-navigator/), in which
+each technique is scored by the number of TTPs that cover it - on the
+platform selected with --platform, if any. The csv and markdown
+formats are a matrix counting the TTPs that cover each technique
+on each platform.
+    `,
+		Example: `ttpforge enum coverage > layer.json
+ttpforge enum coverage --repo examples --platform linux -o linux-layer.json
+ttpforge enum coverage --format markdown`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if format != "navigator" && format != "csv" && format != "markdown" {
+				return fmt.Errorf("invalid format %q - must be navigator, csv or markdown", format)
+			}
+			if platform != "" && format != "navigator" {
+				return fmt.Errorf("--platform can only be used with the navigator format")
+			}
+			cmd.SilenceUsage = true
+
+			var ttpRefs []string
+			var err error
+			if repoName == "" {
+				ttpRefs, err = cfg.repoCollection.ListTTPs()
+				if err != nil {
+					return fmt.Errorf("failed to list TTPs: %w", err)
+				}
+			} else {
+				repo, err := cfg.repoCollection.GetRepo(repoName)
+				if err != nil {
+					return fmt.Errorf("failed to get repo %v: %w", repoName, err)
+				}
+				ttpRefs, err = repo.ListTTPs()
+				if err != nil {
+					return fmt.Errorf("failed to list TTPs in repo %v: %w", repoName, err)
+				}
+			}
+			cov := gatherCoverage(cfg, ttpRefs)
+
+			var output bytes.Buffer
+			switch format {
+			case "navigator":
+				name := "TTPForge coverage"
+				if repoName != "" {
+					name += " - " + repoName
+				}
+				layer, err := cov.NavigatorLayer(name, platform)
+				if err != nil {
+					return err
+				}
+				encoder := json.NewEncoder(&output)
+				encoder.SetIndent("", "  ")
+				if err := encoder.Encode(layer); err != nil {
+					return fmt.Errorf("failed to marshal layer: %w", err)
+				}
+			case "csv":
+				err = cov.WriteCSV(&output)
+			case "markdown":
+				err = cov.WriteMarkdown(&output)
+			}
+			if err != nil {
+				return err
+			}
+
+			if outputPath == "" {
+				_, err = cmd.OutOrStdout().Write(output.Bytes())
+				return err
+			}
+			if err := os.WriteFile(outputPath, output.Bytes(), 0644); err != nil {
+				return fmt.Errorf("failed to write coverage to %v: %w", outputPath, err)
+			}
+			return nil
+		},
+	}
+	enumCoverageCmd.Flags().StringVar(&format, "format", "navigator", "Output format (navigator, csv or markdown)")
+	enumCoverageCmd.Flags().StringVar(&repoName, "repo", "", "Only report the coverage of the TTPs in the specified repository")
+	enumCoverageCmd.Flags().StringVar(&platform, "platform", "", "Score techniques by the TTPs that run on this platform (linux, darwin or windows)")
+	enumCoverageCmd.Flags().StringVarP(&outputPath, "output", "o", "", "Write the coverage to this file instead of stdout")
+	enumCoverageCmd.RegisterFlagCompletionFunc("repo", completeRepoName(cfg, 0))
+	return enumCoverageCmd
+}
@@ -0,0 +1,132 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/facebookincubator/ttpforge/pkg/coverage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnumCoverage(t *testing.T) {
+	testConfigFilePath := filepath.Join("test-resources", "test-config.yaml")
+	testCases := []struct {
+		name           string
+		args           []string
+		expectedScores map[string]int
+		expectedOutput string
+		wantError      bool
+	}{
+		{
+			name:           "navigator",
+			args:           []string{"--repo", testRepoName},
+			expectedScores: map[string]int{"T1059": 1, "T1059.004": 1},
+		},
+		{
+			name:           "navigator-platform-without-coverage",
+			args:           []string{"--repo", testRepoName, "--platform", "windows"},
+			expectedScores: map[string]int{},
+		},
+		{
+			name: "csv",
+			args: []string{"--repo", testRepoName, "--format", "csv"},
+			expectedOutput: `technique,name,linux,darwin,ttps
+T1059,Command and Scripting Interpreter,1,1,1
+T1059.004,Command and Scripting Interpreter: Unix Shell,1,1,1
+`,
+		},
+		{
+			name:      "invalid-format",
+			args:      []string{"--format", "html"},
+			wantError: true,
+		},
+		{
+			name:      "platform-with-csv",
+			args:      []string{"--format", "csv", "--platform", "linux"},
+			wantError: true,
+		},
+		{
+			name:      "invalid-platform",
+			args:      []string{"--platform", "plan9"},
+			wantError: true,
+		},
+		{
+			name:      "repo-not-found",
+			args:      []string{"--repo", "does-not-exist"},
+			wantError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var stdoutBuf bytes.Buffer
+			rc := BuildRootCommand(&TestConfig{
+				Stdout: &stdoutBuf,
+			})
+			rc.SetOut(&stdoutBuf)
+			rc.SetArgs(append([]string{"enum", "coverage", "-c", testConfigFilePath}, tc.args...))
+			logMutex.Lock()
+			err := rc.Execute()
+			logMutex.Unlock()
+			if tc.wantError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			if tc.expectedScores == nil {
+				assert.Equal(t, tc.expectedOutput, stdoutBuf.String())
+				return
+			}
+			var layer coverage.Layer
+			require.NoError(t, json.Unmarshal(stdoutBuf.Bytes(), &layer))
+			scores := make(map[string]int)
+			for _, technique := range layer.Techniques {
+				scores[technique.TechniqueID] = technique.Score
+			}
+			assert.Equal(t, tc.expectedScores, scores)
+		})
+	}
+}
+
+func TestEnumCoverageOutputFile(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "coverage.md")
+	var stdoutBuf bytes.Buffer
+	rc := BuildRootCommand(&TestConfig{
+		Stdout: &stdoutBuf,
+	})
+	rc.SetOut(&stdoutBuf)
+	rc.SetArgs([]string{"enum", "coverage", "-c", filepath.Join("test-resources", "test-config.yaml"), "--format", "markdown", "-o", outputPath})
+	logMutex.Lock()
+	err := rc.Execute()
+	logMutex.Unlock()
+	require.NoError(t, err)
+	assert.Empty(t, stdoutBuf.String())
+
+	contents, err := os.ReadFile(outputPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(contents), "| T1059 | Command and Scripting Interpreter | 1 | 1 | 1 |\n")
+}
@@ -24,6 +24,7 @@ import (
 	"slices"
 	"strings"
 
+	"github.com/facebookincubator/ttpforge/pkg/mitre"
 	"github.com/facebookincubator/ttpforge/pkg/parseutils"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
@@ -67,47 +68,67 @@ func gatherTTPsFromRepo(cfg *Config, repo string) ([]string, error) {
 	return ttpRefs, nil
 }
 
-func matchMitreData(ttp parseutils.TTP, tactic string, technique string, subTech string) bool {
-	// Searching for the MITRE attack ID patterns in the parsed file content
-	dataMatching := false
-	if tactic != "" {
-		for _, ttpTactic := range ttp.Mitre.Tactics {
-			if strings.Contains(ttpTactic, tactic) {
-				dataMatching = true
-				break
-			}
-		}
-		if !dataMatching {
-			return false
-		}
-		dataMatching = false
+// validateMitreFilter checks that an ID passed to
+// filter TTPs by is a known tactic or technique ID
+func validateMitreFilter(id string, kind string) error {
+	if id == "" {
+		return nil
 	}
-	if technique != "" {
-		for _, ttpTechnique := range ttp.Mitre.Techniques {
-			if strings.Contains(ttpTechnique, technique) {
-				dataMatching = true
-				break
-			}
-		}
-		if !dataMatching {
-			return false
-		}
-		dataMatching = false
+	if parsedID, _ := mitre.ParseEntry(id); parsedID != strings.ToUpper(id) || mitre.Default().Kind(parsedID) != kind {
+		return fmt.Errorf("%q is not a known MITRE ATT&CK %v ID (MITRE ATT&CK version %v)", id, kind, mitre.Default().Version)
 	}
-	if subTech != "" {
-		for _, ttpSubTech := range ttp.Mitre.Subtechniques {
-			if strings.Contains(ttpSubTech, subTech) {
-				dataMatching = true
-				break
-			}
-		}
-		if !dataMatching {
-			return false
+	fmt.Printf("Filtering by %v: %s\n", kind, mitre.Default().Describe(id))
+	return nil
+}
+
+// containsMitreID returns true if one of the entries
+// of a MITRE ATT&CK mapping section has the given ID
+func containsMitreID(entries []string, id string) bool {
+	for _, entry := range entries {
+		if entryID, _ := mitre.ParseEntry(entry); strings.EqualFold(entryID, id) {
+			return true
 		}
 	}
+	return false
+}
+
+func matchMitreData(ttp parseutils.TTP, tactic string, technique string, subTech string) bool {
+	// Matching the MITRE ATT&CK IDs at the start of each entry,
+	// so that T1055 does not match T1055.012 for example
+	if tactic != "" && !containsMitreID(ttp.Mitre.Tactics, tactic) {
+		return false
+	}
+	if technique != "" && !containsMitreID(ttp.Mitre.Techniques, technique) {
+		return false
+	}
+	if subTech != "" && !containsMitreID(ttp.Mitre.Subtechniques, subTech) {
+		return false
+	}
 	return true
 }
 
+// printTTPMitreMapping prints the MITRE ATT&CK mapping of a TTP,
+// with the name of each tactic and technique, below its reference
+func printTTPMitreMapping(cfg *Config, ttpRef string) {
+	_, path, err := cfg.repoCollection.ResolveTTPRef(ttpRef)
+	if err != nil {
+		return
+	}
+	content, err := afero.ReadFile(afero.NewOsFs(), path)
+	if err != nil {
+		return
+	}
+	ttp, err := parseutils.ParseTTP(content, path)
+	if err != nil {
+		return
+	}
+	for _, entries := range [][]string{ttp.Mitre.Tactics, ttp.Mitre.Techniques, ttp.Mitre.Subtechniques} {
+		for _, entry := range entries {
+			fmt.Printf("    %s\n", mitre.Default().Describe(entry))
+		}
+	}
+}
+
 func filterTTPs(cfg *Config, platforms []string, tactic string, technique string, subTech string, ttpRefs []string, tally map[string]int, totalCount int) (int, []string) {
 	updatedTTPRefs := []string{}
 	filterPlatform := !slices.Contains(platforms, "any")
@@ -209,6 +230,17 @@ func buildEnumTTPsCommand(cfg *Config) *cobra.Command {
 				return err
 			}
 
+			// Validating MITRE ATT&CK IDs input
+			if err := validateMitreFilter(tactic, mitre.KindTactic); err != nil {
+				return err
+			}
+			if err := validateMitreFilter(technique, mitre.KindTechnique); err != nil {
+				return err
+			}
+			if err := validateMitreFilter(subTech, mitre.KindSubTechnique); err != nil {
+				return err
+			}
+
 			// Fetching all TTPs from the repo input by user
 			ttpRefs, err = gatherTTPsFromRepo(cfg, repo)
 			if err != nil {
@@ -235,6 +267,7 @@ func buildEnumTTPsCommand(cfg *Config) *cobra.Command {
 				// Printing filtered out TTPs
 				for _, ttpRef := range ttpRefs {
 					fmt.Println(ttpRef)
+					printTTPMitreMapping(cfg, ttpRef)
 				}
 			}
 			return nil
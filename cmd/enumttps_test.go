@@ -25,6 +25,8 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/facebookincubator/ttpforge/pkg/parseutils"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -88,6 +90,23 @@ func TestEnumTTPs(t *testing.T) {
 			subTech:       "",
 			errorExpected: true,
 		},
+		{
+			name:          "Unknown tactic",
+			description:   "Tactics that are not in the MITRE ATT&CK catalogue should throw an error",
+			platform:      "linux",
+			repo:          "enum-repo",
+			tactic:        "TA0099",
+			errorExpected: true,
+		},
+		{
+			name:          "Sub-technique as technique",
+			description:   "Sub-technique IDs passed as techniques should throw an error",
+			platform:      "linux",
+			repo:          "enum-repo",
+			tactic:        "TA0005",
+			technique:     "T1555.001",
+			errorExpected: true,
+		},
 		{
 			name:          "Invalid platform",
 			description:   "Invalid platform should throw an error",
@@ -106,6 +125,7 @@ func TestEnumTTPs(t *testing.T) {
 			if tc.platform == "" && tc.repo == "" {
 				args = []string{"enum", "ttps", "-c", testConfigFilePath}
 			}
+			t.Setenv("HOME", t.TempDir())
 			rc := BuildRootCommand(nil)
 			rc.SetArgs(args)
 			fmt.Println(strings.Join(args, " "))
@@ -118,3 +138,36 @@ func TestEnumTTPs(t *testing.T) {
 		})
 	}
 }
+
+func TestMatchMitreData(t *testing.T) {
+	ttp := parseutils.TTP{
+		Mitre: parseutils.Mitre{
+			Tactics:       []string{"TA0005 Defense Evasion", "TA0004"},
+			Techniques:    []string{"T1055 Process Injection"},
+			Subtechniques: []string{"T1055.012 Process Injection: Process Hollowing"},
+		},
+	}
+	testCases := []struct {
+		name      string
+		tactic    string
+		technique string
+		subTech   string
+		expected  bool
+	}{
+		{name: "No Filters", expected: true},
+		{name: "Tactic", tactic: "TA0004", expected: true},
+		{name: "Lowercase Tactic", tactic: "ta0005", expected: true},
+		{name: "Technique", tactic: "TA0005", technique: "T1055", expected: true},
+		{name: "Sub-technique", subTech: "T1055.012", expected: true},
+		{name: "Other Tactic", tactic: "TA0002", expected: false},
+		{name: "Technique Prefix", technique: "T105", expected: false},
+		{name: "Other Sub-technique", subTech: "T1055.001", expected: false},
+		{name: "Technique Is Not Sub-technique", subTech: "T1055", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, matchMitreData(ttp, tc.tactic, tc.technique, tc.subTech))
+		})
+	}
+}
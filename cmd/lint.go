@@ -0,0 +1,162 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/facebookincubator/ttpforge/pkg/lint"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+// loadLintFiles resolves TTP references and reads the
+// corresponding files so that they can be linted
+func loadLintFiles(cfg *Config, ttpRefs []string) ([]*lint.File, error) {
+	var files []*lint.File
+	for _, ttpRef := range ttpRefs {
+		repo, absPath, err := cfg.repoCollection.ResolveTTPRef(ttpRef)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve TTP reference %v: %w", ttpRef, err)
+		}
+		contents, err := afero.ReadFile(repo.GetFs(), absPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read TTP %v: %w", ttpRef, err)
+		}
+		ref, err := cfg.repoCollection.ConvertAbsPathToAbsRef(repo, absPath)
+		if err != nil {
+			ref = ttpRef
+		}
+		files = append(files, &lint.File{
+			Ref:      ref,
+			Path:     absPath,
+			Repo:     repo,
+			Contents: contents,
+		})
+	}
+	return files, nil
+}
+
+// printLintFindings writes the findings in the
+// requested format along with a summary
+func printLintFindings(w io.Writer, format string, findings []lint.Finding, numTTPs int) error {
+	if format == "json" {
+		if findings == nil {
+			findings = []lint.Finding{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(findings)
+	}
+	counts := make(map[lint.Severity]int)
+	for _, finding := range findings {
+		fmt.Fprintln(w, finding)
+		counts[finding.Severity]++
+	}
+	_, err := fmt.Fprintf(w, "Linted %d TTP(s): %d error(s), %d warning(s)\n",
+		numTTPs, counts[lint.SeverityError], counts[lint.SeverityWarning])
+	return err
+}
+
+func buildLintCommand(cfg *Config) *cobra.Command {
+	var repoName string
+	var format string
+	lintCmd := &cobra.Command{
+		Use:   "lint [repo_name//path/to/ttp ...]",
+		Short: "Check TTPs for common mistakes",
+		Long: `
+Check TTPs for common mistakes and departures from best practices,
+such as missing descriptions, sub-TTP references that cannot be
+resolved, unused arguments or UUIDs that are shared between TTPs.
+
+The specified TTPs are linted - or, if none are specified, every TTP in
+the repository selected with --repo or in all installed repositories.
+Findings can be suppressed with comments in the TTP file:
+
+    # ttpforge-lint-ignore missing-cleanup       (this or the next line)
+    # ttpforge-lint-ignore-file unused-arg       (the whole file)
+
+The command fails if any finding has the error severity.
+    `,
+		Example: `ttpforge lint
+ttpforge lint --repo examples --format json
+ttpforge lint examples//actions/create-file/basic.yaml`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "text" && format != "json" {
+				return fmt.Errorf("invalid format %q - must be text or json", format)
+			}
+			if repoName != "" && len(args) > 0 {
+				return fmt.Errorf("--repo cannot be used together with TTP references")
+			}
+			cmd.SilenceUsage = true
+
+			allRefs, err := cfg.repoCollection.ListTTPs()
+			if err != nil {
+				return fmt.Errorf("failed to list TTPs: %w", err)
+			}
+			corpus, err := loadLintFiles(cfg, allRefs)
+			if err != nil {
+				return err
+			}
+
+			var targets []*lint.File
+			switch {
+			case len(args) > 0:
+				targets, err = loadLintFiles(cfg, args)
+				if err != nil {
+					return err
+				}
+			case repoName != "":
+				repo, err := cfg.repoCollection.GetRepo(repoName)
+				if err != nil {
+					return fmt.Errorf("failed to get repo %v: %w", repoName, err)
+				}
+				for _, f := range corpus {
+					if f.Repo.GetName() == repo.GetName() {
+						targets = append(targets, f)
+					}
+				}
+			default:
+				targets = corpus
+			}
+
+			findings := lint.Run(targets, corpus)
+			if err := printLintFindings(cmd.OutOrStdout(), format, findings, len(targets)); err != nil {
+				return err
+			}
+			var numErrors int
+			for _, finding := range findings {
+				if finding.Severity == lint.SeverityError {
+					numErrors++
+				}
+			}
+			if numErrors > 0 {
+				return fmt.Errorf("lint found %d error(s)", numErrors)
+			}
+			return nil
+		},
+	}
+	lintCmd.Flags().StringVar(&repoName, "repo", "", "Lint all TTPs in the specified repository")
+	lintCmd.Flags().StringVar(&format, "format", "text", "Output format (text or json)")
+	lintCmd.RegisterFlagCompletionFunc("repo", completeRepoName(cfg, 0))
+	return lintCmd
+}
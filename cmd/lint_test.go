@@ -0,0 +1,128 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/facebookincubator/ttpforge/pkg/lint"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLintCommand(t *testing.T) {
+	testConfigFilePath := filepath.Join("test-resources", "test-config.yaml")
+	testCases := []struct {
+		name          string
+		args          []string
+		expectedRules []string
+		wantError     bool
+	}{
+		{
+			name: "clean-ttp",
+			args: []string{"test-repo//lint/clean.yaml"},
+		},
+		{
+			name: "problems",
+			args: []string{"test-repo//lint/problems.yaml"},
+			expectedRules: []string{
+				"duplicate-uuid",
+				"missing-description",
+				"missing-platforms",
+				"invalid-mitre",
+				"unused-arg",
+				"missing-cleanup",
+				"unresolved-ttp-ref",
+			},
+			wantError: true,
+		},
+		{
+			name:          "warnings-only",
+			args:          []string{"--repo", "another-repo"},
+			expectedRules: []string{"missing-description", "missing-platforms"},
+		},
+		{
+			name:          "clean-repo",
+			args:          []string{"--repo", "enum-repo"},
+			expectedRules: []string{},
+		},
+		{
+			name:      "repo-not-found",
+			args:      []string{"--repo", "does-not-exist"},
+			wantError: true,
+		},
+		{
+			name:      "repo-and-refs",
+			args:      []string{"--repo", "test-repo", "test-repo//lint/clean.yaml"},
+			wantError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var stdoutBuf bytes.Buffer
+			rc := BuildRootCommand(&TestConfig{
+				Stdout: &stdoutBuf,
+			})
+			rc.SetOut(&stdoutBuf)
+			rc.SetArgs(append([]string{"lint", "-c", testConfigFilePath, "--format", "json"}, tc.args...))
+			logMutex.Lock()
+			err := rc.Execute()
+			logMutex.Unlock()
+			if tc.wantError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			if tc.expectedRules == nil && err != nil {
+				return
+			}
+
+			var findings []lint.Finding
+			require.NoError(t, json.Unmarshal(stdoutBuf.Bytes(), &findings))
+			rules := make(map[string]bool)
+			for _, finding := range findings {
+				rules[finding.Rule] = true
+			}
+			assert.Len(t, rules, len(tc.expectedRules))
+			for _, rule := range tc.expectedRules {
+				assert.True(t, rules[rule], "expected a %v finding", rule)
+			}
+		})
+	}
+}
+
+func TestLintCommandText(t *testing.T) {
+	var stdoutBuf bytes.Buffer
+	rc := BuildRootCommand(&TestConfig{
+		Stdout: &stdoutBuf,
+	})
+	rc.SetOut(&stdoutBuf)
+	rc.SetArgs([]string{"lint", "-c", filepath.Join("test-resources", "test-config.yaml"), "test-repo//lint/problems.yaml"})
+	logMutex.Lock()
+	err := rc.Execute()
+	logMutex.Unlock()
+	require.EqualError(t, err, "lint found 3 error(s)")
+	assert.Contains(t, stdoutBuf.String(), "test-repo//lint/problems.yaml:9: warning: argument \"unused\" is never used (unused-arg)\n")
+	assert.Contains(t, stdoutBuf.String(), "Linted 1 TTP(s): 3 error(s), 4 warning(s)\n")
+}
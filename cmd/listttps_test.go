@@ -40,6 +40,7 @@ func TestListTTPs(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
 			rc := BuildRootCommand(nil)
 			rc.SetArgs([]string{"list", "ttps", "-c", testConfigFilePath})
 			err := rc.Execute()
@@ -0,0 +1,82 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/facebookincubator/ttpforge/pkg/mitre"
+	"github.com/spf13/cobra"
+)
+
+func buildMitreUpdateCommand() *cobra.Command {
+	var outputPath string
+	mitreUpdateCmd := &cobra.Command{
+		Use:   "update [path/to/enterprise-attack.json]",
+		Short: "Refresh the MITRE ATT&CK catalogue from a STIX bundle",
+		Long: `
+Refresh the MITRE ATT&CK catalogue from a local copy of the ATT&CK
+Enterprise STIX bundle (enterprise-attack.json), which can be downloaded
+from https://github.com/mitre-attack/attack-stix-data.
+
+The catalogue is written to ~/.ttpforge/mitre-attack.json, where it
+takes precedence over the catalogue embedded in TTPForge.
+    `,
+		Example: "ttpforge mitre update ~/Downloads/enterprise-attack.json",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			bundle, err := os.ReadFile(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to read STIX bundle: %w", err)
+			}
+			catalogue, err := mitre.ParseSTIXBundle(bundle)
+			if err != nil {
+				return err
+			}
+
+			if outputPath == "" {
+				outputPath, err = mitreCataloguePath()
+				if err != nil {
+					return fmt.Errorf("could not lookup MITRE ATT&CK catalogue path: %w", err)
+				}
+			}
+			var b bytes.Buffer
+			if err := catalogue.Write(&b); err != nil {
+				return fmt.Errorf("failed to encode MITRE ATT&CK catalogue: %w", err)
+			}
+			if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+				return err
+			}
+			if err := os.WriteFile(outputPath, b.Bytes(), 0644); err != nil {
+				return fmt.Errorf("failed to write MITRE ATT&CK catalogue to %v: %w", outputPath, err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Wrote MITRE ATT&CK catalogue version %v (%d tactics, %d techniques) to %v\n",
+				catalogue.Version, len(catalogue.Tactics), len(catalogue.Techniques), outputPath)
+			return nil
+		},
+	}
+	mitreUpdateCmd.Flags().StringVarP(&outputPath, "output", "o", "", "Write the catalogue to this file instead of ~/.ttpforge/mitre-attack.json")
+	return mitreUpdateCmd
+}
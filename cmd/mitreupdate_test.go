@@ -0,0 +1,100 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/facebookincubator/ttpforge/pkg/mitre"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSTIXBundle = `{
+  "type": "bundle",
+  "objects": [
+    {"type": "x-mitre-collection", "id": "x-mitre-collection--1", "x_mitre_version": "99.0"},
+    {"type": "x-mitre-tactic", "id": "x-mitre-tactic--1", "name": "Execution", "x_mitre_shortname": "execution",
+     "external_references": [{"source_name": "mitre-attack", "external_id": "TA0002"}]},
+    {"type": "attack-pattern", "id": "attack-pattern--1", "name": "Command and Scripting Interpreter",
+     "external_references": [{"source_name": "mitre-attack", "external_id": "T1059"}],
+     "kill_chain_phases": [{"kill_chain_name": "mitre-attack", "phase_name": "execution"}]}
+  ]
+}`
+
+func TestMitreUpdate(t *testing.T) {
+	tmpDir := t.TempDir()
+	bundlePath := filepath.Join(tmpDir, "enterprise-attack.json")
+	require.NoError(t, os.WriteFile(bundlePath, []byte(testSTIXBundle), 0644))
+	invalidBundlePath := filepath.Join(tmpDir, "invalid.json")
+	require.NoError(t, os.WriteFile(invalidBundlePath, []byte(`{"type": "bundle", "objects": []}`), 0644))
+
+	testCases := []struct {
+		name       string
+		bundlePath string
+		wantError  bool
+	}{
+		{
+			name:       "valid-bundle",
+			bundlePath: bundlePath,
+		},
+		{
+			name:       "invalid-bundle",
+			bundlePath: invalidBundlePath,
+			wantError:  true,
+		},
+		{
+			name:       "missing-bundle",
+			bundlePath: filepath.Join(tmpDir, "does-not-exist.json"),
+			wantError:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			outputPath := filepath.Join(t.TempDir(), "mitre-attack.json")
+			var stdoutBuf bytes.Buffer
+			rc := BuildRootCommand(&TestConfig{
+				Stdout: &stdoutBuf,
+			})
+			rc.SetOut(&stdoutBuf)
+			rc.SetArgs([]string{"mitre", "update", tc.bundlePath, "--output", outputPath})
+			logMutex.Lock()
+			err := rc.Execute()
+			logMutex.Unlock()
+			if tc.wantError {
+				require.Error(t, err)
+				assert.NoFileExists(t, outputPath)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "Wrote MITRE ATT&CK catalogue version 99.0 (1 tactics, 1 techniques) to "+outputPath+"\n", stdoutBuf.String())
+
+			contents, err := os.ReadFile(outputPath)
+			require.NoError(t, err)
+			catalogue, err := mitre.Parse(contents)
+			require.NoError(t, err)
+			assert.Equal(t, "Command and Scripting Interpreter", catalogue.Name("T1059"))
+		})
+	}
+}
@@ -0,0 +1,97 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/facebookincubator/ttpforge/pkg/args"
+	"golang.org/x/term"
+)
+
+// stdinIsTerminal returns true if ttpforge can prompt the user for input
+func stdinIsTerminal() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+// promptForSecret reads the value of a secret argument
+// from the terminal without echoing it
+func promptForSecret(name string) (string, error) {
+	fmt.Fprintf(os.Stderr, "Enter value for secret argument %v: ", name)
+	value, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
+
+// promptForArgs asks the user for the value of each required argument
+// that was not provided, describing the argument first. Each value is
+// validated as soon as it is entered and the user is asked again if it
+// is invalid. The missing arguments are determined again after every
+// answer, since an answer can make other arguments required (through
+// their `required_if:` conditions).
+//
+// **Parameters:**
+//
+// in: where the values are read from
+// out: where the prompts are written to
+// specs: the argument specifications of the TTP
+// argsKvStrs: the values that were provided in "ARG_NAME=ARG_VALUE" format
+//
+// **Returns:**
+//
+// []string: the values entered by the user in "ARG_NAME=ARG_VALUE" format
+// error: an error if the input ends before all values were provided
+func promptForArgs(in io.Reader, out io.Writer, specs []args.Spec, argsKvStrs []string) ([]string, error) {
+	scanner := bufio.NewScanner(in)
+	var kvStrs []string
+	for {
+		missing := args.Missing(specs, append(append([]string{}, argsKvStrs...), kvStrs...))
+		if len(missing) == 0 {
+			return kvStrs, nil
+		}
+		spec := missing[0]
+		fmt.Fprintln(out, "Missing value for argument:")
+		printArgSpec(out, spec)
+		for {
+			fmt.Fprintf(out, "%v: ", spec.Name)
+			if !scanner.Scan() {
+				if err := scanner.Err(); err != nil {
+					return nil, err
+				}
+				return nil, errors.New("input ended before all argument values were provided")
+			}
+			val := strings.TrimSpace(scanner.Text())
+			if err := spec.ValidateValue(val); err != nil {
+				fmt.Fprintf(out, "Invalid value: %v\n", err)
+				continue
+			}
+			kvStrs = append(kvStrs, spec.Name+"="+val)
+			break
+		}
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/facebookincubator/ttpforge/pkg/args"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromptForArgs(t *testing.T) {
+	specs := []args.Spec{
+		{
+			Name:        "mode",
+			Type:        "enum",
+			Description: "how thorough to be",
+			Values:      []args.EnumValue{{Value: "fast", Description: "skip slow checks"}, {Value: "full"}},
+		},
+		{
+			Name:   "count",
+			Type:   "int",
+			Format: "^[0-9]$",
+		},
+	}
+
+	var out bytes.Buffer
+	kvStrs, err := promptForArgs(strings.NewReader("slow\nfull\n12\n 7 \n"), &out, specs, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"mode=full", "count=7"}, kvStrs)
+
+	expected := `Missing value for argument:
+  mode (enum, required)
+      how thorough to be
+      - fast: skip slow checks
+      - full
+mode: Invalid value: received unexpected value: slow, allowed values: fast, full 
+mode: Missing value for argument:
+  count (int, required, regexp: ^[0-9]$)
+count: Invalid value: invalid value format: 12, expected regex format: ^[0-9]$ 
+count: `
+	assert.Equal(t, expected, out.String())
+}
+
+func TestPromptForArgsRequiredIf(t *testing.T) {
+	specs := []args.Spec{
+		{
+			Name:   "mode",
+			Type:   "enum",
+			Values: []args.EnumValue{{Value: "fast"}, {Value: "full"}},
+		},
+		{
+			Name:       "report_path",
+			RequiredIf: map[string]string{"mode": "full"},
+		},
+	}
+
+	testCases := []struct {
+		name           string
+		argsKvStrs     []string
+		input          string
+		expectedKvStrs []string
+	}{
+		{
+			name:           "answer makes another argument required",
+			input:          "full\n/tmp/report\n",
+			expectedKvStrs: []string{"mode=full", "report_path=/tmp/report"},
+		},
+		{
+			name:           "answer leaves other argument optional",
+			input:          "fast\n",
+			expectedKvStrs: []string{"mode=fast"},
+		},
+		{
+			name:       "provided value makes another argument required",
+			argsKvStrs: []string{"mode=full"},
+			input:      "/tmp/report\n",
+			expectedKvStrs: []string{
+				"report_path=/tmp/report",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			kvStrs, err := promptForArgs(strings.NewReader(tc.input), &out, specs, tc.argsKvStrs)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedKvStrs, kvStrs)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/facebookincubator/ttpforge/pkg/blocks"
+	"github.com/facebookincubator/ttpforge/pkg/logging"
+	"github.com/facebookincubator/ttpforge/pkg/report"
+	"github.com/spf13/cobra"
+)
+
+func buildResumeCommand(cfg *Config) *cobra.Command {
+	var ttpCfg blocks.TTPExecutionConfig
+	var reportPath, reportFormat string
+	resumeCmd := &cobra.Command{
+		Use:   "resume [run-id]",
+		Short: "Resume an interrupted TTP run from the first step that did not complete.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// don't want confusing usage display for errors past this point
+			cmd.SilenceUsage = true
+
+			if reportPath != "" {
+				if err := report.ValidateFormat(reportFormat); err != nil {
+					return err
+				}
+			}
+
+			journal, err := cfg.openRunJournal(args[0])
+			if err != nil {
+				return err
+			}
+			ttp, execCtx, err := cfg.loadJournaledTTP(journal, &ttpCfg)
+			if err != nil {
+				return err
+			}
+			completed, err := journal.Resume(ttp, execCtx)
+			if err != nil {
+				return err
+			}
+			logging.L().Infof("Resuming run %v of %v at step #%d", journal.ID(), journal.State.TTPRef, completed+1)
+
+			runErr := executeAndCleanup(journal.State.TTPRef, ttp, execCtx, reportPath, reportFormat)
+			if runErr != nil {
+				return fmt.Errorf("failed to resume run %v: %w", journal.ID(), runErr)
+			}
+			return nil
+		},
+	}
+	resumeCmd.PersistentFlags().BoolVar(&ttpCfg.NoCleanup, "no-cleanup", false, "Disable cleanup (useful for debugging and daisy-chaining TTPs)")
+	resumeCmd.PersistentFlags().UintVar(&ttpCfg.CleanupDelaySeconds, "cleanup-delay-seconds", 0, "Wait this long after TTP execution before starting cleanup")
+	resumeCmd.Flags().StringVar(&reportPath, "report", "", "Write a machine-readable report of the TTP execution to this file")
+	resumeCmd.Flags().StringVar(&reportFormat, "report-format", report.FormatJSON, fmt.Sprintf("Format of the file written by --report (one of %v)", report.Formats))
+	return resumeCmd
+}
+
+// openRunJournal opens the journal of a previous run -
+// or of the most recent run if runID is empty
+func (cfg *Config) openRunJournal(runID string) (*blocks.RunJournal, error) {
+	runsDir, err := cfg.runsDir()
+	if err != nil {
+		return nil, fmt.Errorf("could not determine run state directory: %w", err)
+	}
+	if runsDir == "" {
+		return nil, errors.New("run journaling is disabled")
+	}
+	if runID == "" {
+		return blocks.LatestRunJournal(runsDir)
+	}
+	return blocks.OpenRunJournal(runsDir, runID)
+}
+
+// loadJournaledTTP reloads the TTP that was executed by a previous run
+func (cfg *Config) loadJournaledTTP(journal *blocks.RunJournal, ttpCfg *blocks.TTPExecutionConfig) (*blocks.TTP, *blocks.TTPExecutionContext, error) {
+	// capture output for tests if needed
+	if cfg.testCfg != nil {
+		ttpCfg.Stdout, ttpCfg.Stderr = cfg.testCfg.Stdout, cfg.testCfg.Stderr
+	}
+
+	// the repository is needed to locate any sub-TTPs
+	ttpRef := journal.State.TTPRef
+	foundRepo, _, err := cfg.repoCollection.ResolveTTPRef(ttpRef)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve TTP reference %v of run %v: %v", ttpRef, journal.ID(), err)
+	}
+	ttpCfg.Repo = foundRepo
+
+	// downloads are still possible without the cache
+	if ttpCfg.CacheDir, err = cfg.cacheDir(); err != nil {
+		logging.L().Warnf("Could not determine download cache directory - downloaded files will not be cached: %v", err)
+	}
+
+	ttp, execCtx, err := journal.LoadTTP(foundRepo.GetFs(), ttpCfg)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not load TTP of run %v:\n\t%v", journal.ID(), err)
+	}
+	return ttp, execCtx, nil
+}
@@ -0,0 +1,124 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func executeJournaledCommand(t *testing.T, runsDir string, args ...string) (string, error) {
+	var stdoutBuf, stderrBuf bytes.Buffer
+	rc := BuildRootCommand(&TestConfig{
+		Stdout:  &stdoutBuf,
+		Stderr:  &stderrBuf,
+		RunsDir: runsDir,
+	})
+	rc.SetArgs(append(args, "-c", filepath.Join(testResourcesDir, "test-config.yaml")))
+	logMutex.Lock()
+	err := rc.Execute()
+	logMutex.Unlock()
+	return stdoutBuf.String(), err
+}
+
+func onlyRunID(t *testing.T, runsDir string) string {
+	entries, err := os.ReadDir(runsDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	return entries[0].Name()
+}
+
+func TestResume(t *testing.T) {
+	runsDir := t.TempDir()
+	markerPath := filepath.Join(t.TempDir(), "marker")
+	ttpRef := testRepoName + "//resume/resumable.yaml"
+
+	_, err := executeJournaledCommand(t, runsDir, "run", ttpRef, "--arg", "marker="+markerPath, "--no-cleanup")
+	require.Error(t, err)
+	runID := onlyRunID(t, runsDir)
+
+	require.NoError(t, os.WriteFile(markerPath, []byte{}, 0644))
+	stdout, err := executeJournaledCommand(t, runsDir, "resume", runID)
+	require.NoError(t, err)
+	assert.Equal(t, "second first\ncleanup second\ncleanup first\n", stdout)
+
+	// the run has completed so it cannot be resumed again
+	_, err = executeJournaledCommand(t, runsDir, "resume", runID)
+	require.Error(t, err)
+
+	_, err = executeJournaledCommand(t, runsDir, "resume", "no-such-run")
+	require.Error(t, err)
+}
+
+func TestCleanup(t *testing.T) {
+	markerPath := filepath.Join(t.TempDir(), "marker")
+	require.NoError(t, os.WriteFile(markerPath, []byte{}, 0644))
+	ttpRef := testRepoName + "//resume/resumable.yaml"
+
+	testCases := []struct {
+		name string
+		last bool
+	}{
+		{
+			name: "by-run-id",
+		},
+		{
+			name: "last",
+			last: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			runsDir := t.TempDir()
+			stdout, err := executeJournaledCommand(t, runsDir, "run", ttpRef, "--arg", "marker="+markerPath, "--no-cleanup")
+			require.NoError(t, err)
+			assert.Equal(t, "first\nsecond first\n", stdout)
+
+			cleanupArgs := []string{"cleanup", onlyRunID(t, runsDir)}
+			if tc.last {
+				cleanupArgs = []string{"cleanup", "--last"}
+			}
+			stdout, err = executeJournaledCommand(t, runsDir, cleanupArgs...)
+			require.NoError(t, err)
+			assert.Equal(t, "cleanup second\ncleanup first\n", stdout)
+
+			// everything has been cleaned up already
+			stdout, err = executeJournaledCommand(t, runsDir, cleanupArgs...)
+			require.NoError(t, err)
+			assert.Empty(t, stdout)
+		})
+	}
+
+	t.Run("no-run-specified", func(t *testing.T) {
+		_, err := executeJournaledCommand(t, t.TempDir(), "cleanup")
+		require.Error(t, err)
+	})
+
+	t.Run("no-runs", func(t *testing.T) {
+		_, err := executeJournaledCommand(t, t.TempDir(), "cleanup", "--last")
+		require.Error(t, err)
+	})
+}
@@ -22,6 +22,7 @@ package cmd
 import (
 	"fmt"
 
+	ttpargs "github.com/facebookincubator/ttpforge/pkg/args"
 	"github.com/spf13/cobra"
 )
 
@@ -56,12 +57,18 @@ TTPForge is a Purple Team engagement tool to execute Tactics, Techniques, and Pr
     `,
 		TraverseChildren: true,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-			if cmd.CalledAs() != "init" {
+			// completion functions initialize the configuration themselves
+			// once the --config flag of the completed command line is known
+			if cmd.CalledAs() != "init" && cmd.Name() != cobra.ShellCompRequestCmd {
 				err := cfg.init()
 				if err != nil {
 					return fmt.Errorf("failed to load TTPForge configuration file: %w", err)
 				}
 			}
+			// secret arguments can only be prompted for interactively
+			if testCfg == nil && stdinIsTerminal() {
+				ttpargs.PromptForSecret = promptForSecret
+			}
 			return nil
 		},
 		// we will print our own errors with pretty formatting
@@ -79,11 +86,17 @@ TTPForge is a Purple Team engagement tool to execute Tactics, Techniques, and Pr
 	rootCmd.AddCommand(buildCreateCommand())
 	rootCmd.AddCommand(buildListCommand(cfg))
 	rootCmd.AddCommand(buildEnumCommand(cfg))
+	rootCmd.AddCommand(buildSearchCommand(cfg))
 	rootCmd.AddCommand(buildShowCommand(cfg))
 	rootCmd.AddCommand(buildRunCommand(cfg))
+	rootCmd.AddCommand(buildResumeCommand(cfg))
+	rootCmd.AddCommand(buildCleanupCommand(cfg))
 	rootCmd.AddCommand(buildTestCommand(cfg))
 	rootCmd.AddCommand(buildInstallCommand(cfg))
 	rootCmd.AddCommand(buildRemoveCommand(cfg))
 	rootCmd.AddCommand(buildMoveCommand(cfg))
+	rootCmd.AddCommand(buildSchemaCommand())
+	rootCmd.AddCommand(buildLintCommand(cfg))
+	rootCmd.AddCommand(buildMitreCommand())
 	return rootCmd
 }
@@ -20,42 +20,74 @@ THE SOFTWARE.
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"time"
 
+	"github.com/facebookincubator/ttpforge/pkg/args"
 	"github.com/facebookincubator/ttpforge/pkg/blocks"
 	"github.com/facebookincubator/ttpforge/pkg/logging"
+	"github.com/facebookincubator/ttpforge/pkg/report"
+	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
 )
 
 func buildRunCommand(cfg *Config) *cobra.Command {
 	var argsList []string
+	var argsFile string
+	var interactive bool
 	var ttpCfg blocks.TTPExecutionConfig
+	var reportPath, reportFormat string
 	runCmd := &cobra.Command{
 		Use:               "run [repo_name//path/to/ttp]",
 		Short:             "Run the TTP found in the specified YAML file.",
 		Args:              cobra.ExactArgs(1),
 		ValidArgsFunction: completeTTPRef(cfg, 1),
-		RunE: func(cmd *cobra.Command, args []string) error {
+		RunE: func(cmd *cobra.Command, cmdArgs []string) error {
 			// don't want confusing usage display for errors past this point
 			cmd.SilenceUsage = true
 
+			if reportPath != "" {
+				if err := report.ValidateFormat(reportFormat); err != nil {
+					return err
+				}
+			}
+
 			// capture output for tests if needed
 			if cfg.testCfg != nil {
 				ttpCfg.Stdout, ttpCfg.Stderr = cfg.testCfg.Stdout, cfg.testCfg.Stderr
 			}
 
 			// find the TTP file
-			ttpRef := args[0]
+			ttpRef := cmdArgs[0]
 			foundRepo, ttpAbsPath, err := cfg.repoCollection.ResolveTTPRef(ttpRef)
 			if err != nil {
 				return fmt.Errorf("failed to resolve TTP reference %v: %v", ttpRef, err)
 			}
 
+			// downloads are still possible without the cache
+			if ttpCfg.CacheDir, err = cfg.cacheDir(); err != nil {
+				logging.L().Warnf("Could not determine download cache directory - downloaded files will not be cached: %v", err)
+			}
+
 			// load TTP and process argument values
 			// based on the TTPs argument value specifications
 			ttpCfg.Repo = foundRepo
 
-			ttp, execCtx, err := blocks.LoadTTP(ttpAbsPath, foundRepo.GetFs(), &ttpCfg, map[string]string{}, argsList)
+			specs, argKvStrs, err := collectArgs(foundRepo.GetFs(), ttpAbsPath, argsList, argsFile)
+			if err != nil {
+				return fmt.Errorf("failed to process arguments of TTP %v: %w", ttpRef, err)
+			}
+			if interactive {
+				prompted, err := promptForArgs(cmd.InOrStdin(), cmd.ErrOrStderr(), specs, argKvStrs)
+				if err != nil {
+					return fmt.Errorf("failed to read arguments of TTP %v: %w", ttpRef, err)
+				}
+				argKvStrs = append(argKvStrs, prompted...)
+			}
+
+			ttp, execCtx, err := blocks.LoadTTP(ttpAbsPath, foundRepo.GetFs(), &ttpCfg, map[string]interface{}{}, argKvStrs)
 			if err != nil {
 				return fmt.Errorf("could not load TTP at %v:\n\t%v", ttpAbsPath, err)
 			}
@@ -65,14 +97,28 @@ func buildRunCommand(cfg *Config) *cobra.Command {
 				return nil
 			}
 
-			runErr := ttp.Execute(*execCtx)
-			// Run clean up always
-			cleanupErr := ttp.RunCleanup(*execCtx)
-
-			if cleanupErr != nil {
-				logging.L().Warnf("Failed to run cleanup: %v", cleanupErr)
+			// journal the run so that it can be resumed
+			// or cleaned up later if it is interrupted
+			runsDir, err := cfg.runsDir()
+			if err != nil {
+				logging.L().Warnf("Could not determine run state directory - this run cannot be resumed: %v", err)
+			} else if runsDir != "" {
+				pruned, err := blocks.PruneRunJournals(runsDir, cfg.runRetention())
+				if err != nil {
+					logging.L().Warnf("Failed to delete the state of old runs: %v", err)
+				}
+				if pruned > 0 {
+					logging.L().Debugf("Deleted the state of %d runs that finished more than %v ago", pruned, cfg.runRetention())
+				}
+				journal, err := blocks.StartRunJournal(runsDir, ttpRef, ttpAbsPath, ttp, execCtx, argKvStrs)
+				if err != nil {
+					logging.L().Warnf("Failed to journal run - this run cannot be resumed: %v", err)
+				} else {
+					logging.L().Infof("Run ID: %v", journal.ID())
+				}
 			}
 
+			runErr := executeAndCleanup(ttpRef, ttp, execCtx, reportPath, reportFormat)
 			if runErr != nil {
 				return fmt.Errorf("failed to run TTP at %v: %w", ttpAbsPath, runErr)
 			}
@@ -83,7 +129,100 @@ func buildRunCommand(cfg *Config) *cobra.Command {
 	runCmd.PersistentFlags().BoolVar(&ttpCfg.NoCleanup, "no-cleanup", false, "Disable cleanup (useful for debugging and daisy-chaining TTPs)")
 	runCmd.PersistentFlags().BoolVar(&ttpCfg.NoChecks, "no-checks", false, "Skip/ignore checks")
 	runCmd.PersistentFlags().UintVar(&ttpCfg.CleanupDelaySeconds, "cleanup-delay-seconds", 0, "Wait this long after TTP execution before starting cleanup")
+	runCmd.PersistentFlags().BoolVar(&ttpCfg.Strict, "strict", false, "Reject TTPs that contain keys that are not part of the TTP format (such as misspelled field names)")
+	runCmd.PersistentFlags().DurationVar(&ttpCfg.StepTimeout, "step-timeout", 0, "Default timeout for steps that do not specify their own timeout (e.g. 30s or 5m) - 0 means no timeout")
+	runCmd.Flags().StringVar(&reportPath, "report", "", "Write a machine-readable report of the TTP execution to this file")
+	runCmd.Flags().StringVar(&reportFormat, "report-format", report.FormatJSON, fmt.Sprintf("Format of the file written by --report (one of %v)", report.Formats))
 	runCmd.Flags().StringArrayVarP(&argsList, "arg", "a", []string{}, "variable input mapping for args to be used in place of inputs defined in each ttp file")
+	runCmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "Prompt for the values of required arguments that were not provided")
+	runCmd.Flags().StringVar(&argsFile, "args-file", "", "YAML or JSON file mapping argument names to values - values passed with --arg take precedence")
+	runCmd.RegisterFlagCompletionFunc("arg", completeTTPArgs(cfg))
 
 	return runCmd
 }
+
+// collectArgs merges the argument values passed with --arg, those
+// read from the --args-file (if any) and those set through
+// TTPFORGE_ARG_<NAME> environment variables, in that order of precedence.
+// Arguments provided by none of these sources take their default value.
+//
+// **Parameters:**
+//
+// fsys: the file system containing the TTP
+// ttpAbsPath: the absolute path of the TTP file
+// argsList: the arguments passed with --arg
+// argsFile: the path of the arguments file, or "" if there is none
+//
+// **Returns:**
+//
+// []args.Spec: the argument specifications of the TTP
+// []string: the merged arguments in "ARG_NAME=ARG_VALUE" format
+// error: an error if an argument source is invalid
+func collectArgs(fsys afero.Fs, ttpAbsPath string, argsList []string, argsFile string) ([]args.Spec, []string, error) {
+	contents, err := afero.ReadFile(fsys, ttpAbsPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	specs, err := parseArgSpecs(contents)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cliValues, err := args.ParseKvStrs(argsList)
+	if err != nil {
+		return nil, nil, err
+	}
+	fileValues := args.Values{}
+	if argsFile != "" {
+		// the arguments file is on the local file system, not in the repo
+		fileValues, err = args.LoadFile(afero.NewOsFs(), argsFile)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	envValues := args.FromEnv(specs, os.Environ())
+	argKvStrs, err := args.Merge(specs, cliValues, fileValues, envValues)
+	if err != nil {
+		return nil, nil, err
+	}
+	return specs, argKvStrs, nil
+}
+
+// executeAndCleanup executes the steps of a loaded TTP and then
+// cleans it up, records the outcome in the journal of the run (if any)
+// and writes an execution report if one was requested
+func executeAndCleanup(ttpRef string, ttp *blocks.TTP, execCtx *blocks.TTPExecutionContext, reportPath, reportFormat string) error {
+	startTime := time.Now()
+	runErr := ttp.Execute(*execCtx)
+	if execCtx.Journal != nil {
+		if err := execCtx.Journal.Finish(runErr); err != nil {
+			logging.L().Warnf("Failed to save state of run %v: %v", execCtx.Journal.ID(), err)
+		}
+	}
+	// Run clean up always
+	cleanupErr := ttp.RunCleanup(*execCtx)
+
+	if cleanupErr != nil {
+		logging.L().Warnf("Failed to run cleanup: %v", cleanupErr)
+	}
+	if execCtx.Journal != nil && execCtx.Journal.State.CleanupPending {
+		logging.L().Infof("Run `ttpforge cleanup %v` to clean up the remaining steps", execCtx.Journal.ID())
+	}
+
+	if reportPath != "" {
+		r := report.New(report.Run{
+			TTPRef:    ttpRef,
+			TTP:       ttp,
+			Results:   execCtx.StepResults,
+			StartTime: startTime,
+			EndTime:   time.Now(),
+			Err:       runErr,
+		})
+		if err := r.WriteFile(reportPath, reportFormat); err != nil {
+			// the failure of the TTP itself must not be hidden
+			return errors.Join(runErr, fmt.Errorf("failed to write report to %v: %w", reportPath, err))
+		}
+		logging.L().Infof("Wrote %v report to %v", reportFormat, reportPath)
+	}
+	return runErr
+}
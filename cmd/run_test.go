@@ -21,8 +21,11 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 
@@ -40,6 +43,7 @@ type runCmdTestCase struct {
 	name           string
 	description    string
 	args           []string
+	stdin          string
 	expectedStdout string
 	wantError      bool
 }
@@ -53,6 +57,8 @@ func checkRunCmdTestCase(t *testing.T, tc runCmdTestCase) {
 		Stderr: &stderrBuf,
 	})
 	rc.SetArgs(append([]string{"run"}, tc.args...))
+	rc.SetIn(strings.NewReader(tc.stdin))
+	rc.SetErr(io.Discard)
 	logMutex.Lock()
 	err := rc.Execute()
 	logMutex.Unlock()
@@ -238,3 +244,346 @@ func TestRunPathArguments(t *testing.T) {
 		})
 	}
 }
+
+// TestRunReport checks that `ttpforge run --report` writes
+// a report describing the executed steps
+func TestRunReport(t *testing.T) {
+	testConfigFilePath := filepath.Join(testResourcesDir, "test-config.yaml")
+	reportDir := t.TempDir()
+
+	testCases := []struct {
+		name       string
+		ttpRef     string
+		format     string
+		wantStatus string
+		wantError  bool
+	}{
+		{
+			name:       "json-success",
+			ttpRef:     "another-repo//simple-inline.yaml",
+			format:     "json",
+			wantStatus: "succeeded",
+		},
+		{
+			name:       "json-failure",
+			ttpRef:     "another-repo//sub-ttp-example/ttp.yaml",
+			format:     "json",
+			wantStatus: "failed",
+			wantError:  true,
+		},
+		{
+			name:      "invalid-format",
+			ttpRef:    "another-repo//simple-inline.yaml",
+			format:    "yaml",
+			wantError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reportPath := filepath.Join(reportDir, tc.name+".json")
+			var stdoutBuf, stderrBuf bytes.Buffer
+			rc := BuildRootCommand(&TestConfig{
+				Stdout: &stdoutBuf,
+				Stderr: &stderrBuf,
+			})
+			rc.SetArgs([]string{
+				"run",
+				"-c",
+				testConfigFilePath,
+				"--report",
+				reportPath,
+				"--report-format",
+				tc.format,
+				tc.ttpRef,
+			})
+			logMutex.Lock()
+			err := rc.Execute()
+			logMutex.Unlock()
+			if tc.wantError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			if tc.wantStatus == "" {
+				assert.NoFileExists(t, reportPath)
+				return
+			}
+
+			contents, err := os.ReadFile(reportPath)
+			require.NoError(t, err)
+			var r struct {
+				Status string `json:"status"`
+				TTP    struct {
+					Ref string `json:"ref"`
+				} `json:"ttp"`
+				Steps []struct {
+					Name       string `json:"name"`
+					ActionType string `json:"action_type"`
+					Status     string `json:"status"`
+				} `json:"steps"`
+			}
+			require.NoError(t, json.Unmarshal(contents, &r))
+			assert.Equal(t, tc.wantStatus, r.Status)
+			assert.Equal(t, tc.ttpRef, r.TTP.Ref)
+			require.NotEmpty(t, r.Steps)
+			assert.NotEmpty(t, r.Steps[0].Name)
+			assert.NotEmpty(t, r.Steps[0].ActionType)
+		})
+	}
+}
+
+// TestRunReportWriteFailure checks that the failure of a TTP is
+// still reported when its report cannot be written either
+func TestRunReportWriteFailure(t *testing.T) {
+	testConfigFilePath := filepath.Join(testResourcesDir, "test-config.yaml")
+	reportPath := filepath.Join(t.TempDir(), "missing", "report.json")
+
+	var stdoutBuf, stderrBuf bytes.Buffer
+	rc := BuildRootCommand(&TestConfig{
+		Stdout: &stdoutBuf,
+		Stderr: &stderrBuf,
+	})
+	rc.SetArgs([]string{
+		"run",
+		"-c",
+		testConfigFilePath,
+		"--report",
+		reportPath,
+		"another-repo//sub-ttp-example/ttp.yaml",
+	})
+	logMutex.Lock()
+	err := rc.Execute()
+	logMutex.Unlock()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "exit status 127")
+	assert.Contains(t, err.Error(), "failed to write report to "+reportPath)
+}
+
+func TestRunTypedArgs(t *testing.T) {
+	testConfigFilePath := filepath.Join(testResourcesDir, "test-config.yaml")
+	ttpRef := testRepoName + "//args/types/typed-args.yaml"
+
+	testCases := []runCmdTestCase{
+		{
+			name:        "defaults",
+			description: "only the required argument is provided",
+			args: []string{
+				"-c", testConfigFilePath, ttpRef,
+				"--arg", "target=10.0.0.1",
+			},
+			expectedStdout: "10.0.0.1:8443 30s fast\n",
+		},
+		{
+			name:        "all-arguments",
+			description: "list arguments may be comma separated or repeated",
+			args: []string{
+				"-c", testConfigFilePath, ttpRef,
+				"--arg", "target=10.0.0.1",
+				"--arg", "port=9000",
+				"--arg", "timeout=1m30s",
+				"--arg", "mode=full",
+				"--arg", "tags=a,b",
+				"--arg", "tags=c",
+			},
+			expectedStdout: "10.0.0.1:9000 90s full\ntags: a+b+c\n",
+		},
+		{
+			name:        "missing-required-argument",
+			description: "target has no default and is not optional",
+			args: []string{
+				"-c", testConfigFilePath, ttpRef,
+			},
+			wantError: true,
+		},
+		{
+			name:        "invalid-ip",
+			description: "target must be an IP address",
+			args: []string{
+				"-c", testConfigFilePath, ttpRef,
+				"--arg", "target=not-an-ip",
+			},
+			wantError: true,
+		},
+		{
+			name:        "port-below-min",
+			description: "port must be at least 1024",
+			args: []string{
+				"-c", testConfigFilePath, ttpRef,
+				"--arg", "target=10.0.0.1",
+				"--arg", "port=80",
+			},
+			wantError: true,
+		},
+		{
+			name:        "invalid-enum-value",
+			description: "mode must be one of the enum values",
+			args: []string{
+				"-c", testConfigFilePath, ttpRef,
+				"--arg", "target=10.0.0.1",
+				"--arg", "mode=slow",
+			},
+			wantError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			checkRunCmdTestCase(t, tc)
+		})
+	}
+}
+
+func TestRunArgSources(t *testing.T) {
+	testConfigFilePath := filepath.Join(testResourcesDir, "test-config.yaml")
+	ttpRef := testRepoName + "//args/types/typed-args.yaml"
+
+	argsFile := filepath.Join(t.TempDir(), "args.yaml")
+	err := os.WriteFile(argsFile, []byte("target: 10.0.0.2\nport: 9000\ntags: [a, b]\n"), 0644)
+	require.NoError(t, err)
+	t.Setenv("TTPFORGE_ARG_PORT", "9999")
+	t.Setenv("TTPFORGE_ARG_MODE", "full")
+
+	testCases := []runCmdTestCase{
+		{
+			name:        "file-and-env",
+			description: "the arguments file takes precedence over the environment",
+			args: []string{
+				"-c", testConfigFilePath, ttpRef,
+				"--args-file", argsFile,
+			},
+			expectedStdout: "10.0.0.2:9000 30s full\ntags: a+b\n",
+		},
+		{
+			name:        "cli-overrides-file",
+			description: "values passed with --arg take precedence over the arguments file",
+			args: []string{
+				"-c", testConfigFilePath, ttpRef,
+				"--args-file", argsFile,
+				"--arg", "target=10.0.0.3",
+				"--arg", "tags=c",
+			},
+			expectedStdout: "10.0.0.3:9000 30s full\ntags: c\n",
+		},
+		{
+			name:        "env-only",
+			description: "environment variables are used without an arguments file",
+			args: []string{
+				"-c", testConfigFilePath, ttpRef,
+				"--arg", "target=10.0.0.1",
+			},
+			expectedStdout: "10.0.0.1:9999 30s full\n",
+		},
+		{
+			name:        "missing-args-file",
+			description: "a missing arguments file is an error",
+			args: []string{
+				"-c", testConfigFilePath, ttpRef,
+				"--args-file", filepath.Join(t.TempDir(), "missing.yaml"),
+			},
+			wantError: true,
+		},
+		{
+			name:        "unknown-argument",
+			description: "arguments that the TTP does not accept are an error",
+			args: []string{
+				"-c", testConfigFilePath, ttpRef,
+				"--arg", "target=10.0.0.1",
+				"--arg", "prot=80",
+			},
+			wantError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			checkRunCmdTestCase(t, tc)
+		})
+	}
+}
+
+func TestRunInteractive(t *testing.T) {
+	testConfigFilePath := filepath.Join(testResourcesDir, "test-config.yaml")
+	ttpRef := testRepoName + "//args/types/typed-args.yaml"
+
+	testCases := []runCmdTestCase{
+		{
+			name:        "prompt-for-missing-argument",
+			description: "the required target argument is read from stdin",
+			args: []string{
+				"-c", testConfigFilePath, ttpRef,
+				"--interactive",
+			},
+			stdin:          "10.0.0.1\n",
+			expectedStdout: "10.0.0.1:8443 30s fast\n",
+		},
+		{
+			name:        "re-prompt-on-invalid-value",
+			description: "invalid values are rejected and the user is asked again",
+			args: []string{
+				"-c", testConfigFilePath, ttpRef,
+				"--interactive",
+			},
+			stdin:          "not-an-ip\n\n10.0.0.1\n",
+			expectedStdout: "10.0.0.1:8443 30s fast\n",
+		},
+		{
+			name:        "no-prompt-if-provided",
+			description: "arguments passed with --arg are not prompted for",
+			args: []string{
+				"-c", testConfigFilePath, ttpRef,
+				"--interactive",
+				"--arg", "target=10.0.0.2",
+			},
+			expectedStdout: "10.0.0.2:8443 30s fast\n",
+		},
+		{
+			name:        "input-ends",
+			description: "running out of input before a valid value is provided is an error",
+			args: []string{
+				"-c", testConfigFilePath, ttpRef,
+				"--interactive",
+			},
+			stdin:     "not-an-ip\n",
+			wantError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			checkRunCmdTestCase(t, tc)
+		})
+	}
+}
+
+func TestRunStrict(t *testing.T) {
+	testConfigFilePath := filepath.Join(testResourcesDir, "test-config.yaml")
+	ttpRef := testRepoName + "//strict/unknown-key.yaml"
+
+	testCases := []runCmdTestCase{
+		{
+			name:           "unknown-key-ignored",
+			description:    "unknown keys are ignored by default",
+			args:           []string{"-c", testConfigFilePath, ttpRef},
+			expectedStdout: "hello\n",
+		},
+		{
+			name:        "unknown-key-rejected",
+			description: "unknown keys are rejected in strict mode",
+			args:        []string{"-c", testConfigFilePath, ttpRef, "--strict"},
+			wantError:   true,
+		},
+		{
+			name:           "strict-valid-ttp",
+			description:    "strict mode accepts TTPs without unknown keys",
+			args:           []string{"-c", testConfigFilePath, testRepoName + "//steps/file-step-demo.yaml", "--strict"},
+			expectedStdout: "Hello World\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			checkRunCmdTestCase(t, tc)
+		})
+	}
+}
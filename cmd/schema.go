@@ -0,0 +1,62 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/facebookincubator/ttpforge/pkg/blocks"
+	"github.com/spf13/cobra"
+)
+
+func buildSchemaCommand() *cobra.Command {
+	var outputPath string
+	schemaCmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Print the JSON Schema of the TTP file format",
+		Long: `
+Print a JSON Schema describing TTP files - including the preamble,
+every type of step action, checks, outputs and cleanup actions.
+Editors that support JSON Schema can use it to validate and
+auto-complete TTPs as you write them.
+    `,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.SilenceUsage = true
+
+			schemaJSON, err := json.MarshalIndent(blocks.Schema(), "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal schema: %w", err)
+			}
+			schemaJSON = append(schemaJSON, '\n')
+			if outputPath == "" {
+				_, err = cmd.OutOrStdout().Write(schemaJSON)
+				return err
+			}
+			if err := os.WriteFile(outputPath, schemaJSON, 0644); err != nil {
+				return fmt.Errorf("failed to write schema to %v: %w", outputPath, err)
+			}
+			return nil
+		},
+	}
+	schemaCmd.Flags().StringVarP(&outputPath, "output", "o", "", "Write the schema to this file instead of stdout")
+	return schemaCmd
+}
@@ -0,0 +1,75 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaCommand(t *testing.T) {
+	outputPath := filepath.Join(t.TempDir(), "ttp.schema.json")
+	testCases := []struct {
+		name       string
+		args       []string
+		outputPath string
+	}{
+		{
+			name: "stdout",
+			args: []string{"schema"},
+		},
+		{
+			name:       "output-file",
+			args:       []string{"schema", "--output", outputPath},
+			outputPath: outputPath,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var stdoutBuf bytes.Buffer
+			rc := BuildRootCommand(&TestConfig{
+				Stdout: &stdoutBuf,
+			})
+			rc.SetOut(&stdoutBuf)
+			rc.SetArgs(tc.args)
+			logMutex.Lock()
+			err := rc.Execute()
+			logMutex.Unlock()
+			require.NoError(t, err)
+
+			schemaJSON := stdoutBuf.Bytes()
+			if tc.outputPath != "" {
+				assert.Empty(t, stdoutBuf.String())
+				schemaJSON, err = os.ReadFile(tc.outputPath)
+				require.NoError(t, err)
+			}
+			var decoded map[string]interface{}
+			require.NoError(t, json.Unmarshal(schemaJSON, &decoded))
+			assert.Equal(t, "TTPForge TTP", decoded["title"])
+			assert.Contains(t, decoded["properties"], "steps")
+		})
+	}
+}
@@ -0,0 +1,160 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/facebookincubator/ttpforge/pkg/logging"
+	"github.com/facebookincubator/ttpforge/pkg/search"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+)
+
+// loadSearchIndex brings the search index in line with the
+// installed TTPs, reusing the cached index unless reindex is set
+func loadSearchIndex(cfg *Config, fsys afero.Fs, reindex bool) (*search.Index, error) {
+	ttpRefs, err := cfg.repoCollection.ListTTPs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list TTPs: %w", err)
+	}
+	var files []search.File
+	for _, ttpRef := range ttpRefs {
+		repo, path, err := cfg.repoCollection.ResolveTTPRef(ttpRef)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve TTP reference %v: %w", ttpRef, err)
+		}
+		files = append(files, search.File{Ref: ttpRef, Repo: repo.GetName(), Path: path})
+	}
+
+	indexPath, err := cfg.searchIndexPath()
+	if err != nil {
+		return nil, fmt.Errorf("could not lookup search index path: %w", err)
+	}
+	idx := search.NewIndex()
+	if indexPath != "" && !reindex {
+		idx, err = search.LoadIndex(fsys, indexPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+	changed, err := idx.Update(fsys, files)
+	if err != nil {
+		return nil, err
+	}
+	if indexPath != "" && (changed || reindex) {
+		// failing to cache the index only makes the next search slower
+		if err := idx.Save(fsys, indexPath); err != nil {
+			logging.L().Warnf("Could not cache search index: %v", err)
+		}
+	}
+	return idx, nil
+}
+
+// printSearchResults writes the entries matching
+// a search in the requested format
+func printSearchResults(w io.Writer, format string, entries []*search.Entry) error {
+	if format == "json" {
+		if entries == nil {
+			entries = []*search.Entry{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(entries)
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "REF\tNAME\tPLATFORMS\tACTIONS")
+	for _, e := range entries {
+		platforms := "any"
+		if len(e.Platforms) > 0 {
+			platforms = strings.Join(e.Platforms, ",")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", e.Ref, e.Name, platforms, strings.Join(e.Actions, ","))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "%d TTP(s) found\n", len(entries))
+	return err
+}
+
+func buildSearchCommand(cfg *Config) *cobra.Command {
+	var format string
+	var reindex bool
+	searchCmd := &cobra.Command{
+		Use:   "search [query]",
+		Short: "Search the TTPs of all installed repositories",
+		Long: `
+Search the TTPs of all installed repositories. The query is a list of
+terms, all of which must match. Terms of the form field:value match
+one of these fields:
+
+    name:<text>          the name contains the text
+    description:<text>   the description contains the text (or desc:)
+    uuid:<prefix>        the UUID starts with the prefix
+    arg:<name>           the TTP has an argument with this name
+    uses:<action>        a step uses this action type (such as http_request)
+    executor:<name>      an inline, file or expect step uses this executor
+    superuser:<bool>     the TTP requires superuser privileges (true or false)
+    repo:<name>          the TTP is in this repository
+    platform:<os>        the TTP supports this platform (any: all platforms)
+    mitre:<id>           the TTP is mapped to this MITRE ATT&CK ID
+
+Other terms match the name, description, reference, UUID or argument
+names of TTPs. Prefix a term with - to exclude the TTPs that it matches
+and use double quotes to search for several words, as in:
+
+    ttpforge search 'name:"dump credentials" -platform:windows'
+
+A query that starts with an excluded term must follow -- so
+that it is not mistaken for a flag: ttpforge search -- -uses:inline
+
+The information extracted from TTPs is cached in
+~/.ttpforge/search-index.json - TTP files that have changed
+since the last search are indexed again automatically.
+    `,
+		Example: `ttpforge search uses:http_request
+ttpforge search credentials platform:linux superuser:false
+ttpforge search repo:examples executor:python3 --format json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "table" && format != "json" {
+				return fmt.Errorf("invalid format %q - must be table or json", format)
+			}
+			query, err := search.ParseQuery(strings.Join(args, " "))
+			if err != nil {
+				return err
+			}
+			cmd.SilenceUsage = true
+
+			idx, err := loadSearchIndex(cfg, afero.NewOsFs(), reindex)
+			if err != nil {
+				return err
+			}
+			return printSearchResults(cmd.OutOrStdout(), format, idx.Search(query))
+		},
+	}
+	searchCmd.Flags().StringVar(&format, "format", "table", "Output format (table or json)")
+	searchCmd.Flags().BoolVar(&reindex, "reindex", false, "Rebuild the search index from scratch")
+	return searchCmd
+}
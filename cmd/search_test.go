@@ -0,0 +1,132 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/facebookincubator/ttpforge/pkg/search"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runSearchCommand(t *testing.T, testCfg *TestConfig, args ...string) (string, error) {
+	var stdoutBuf bytes.Buffer
+	testCfg.Stdout = &stdoutBuf
+	rc := BuildRootCommand(testCfg)
+	rc.SetOut(&stdoutBuf)
+	rc.SetArgs(append([]string{"search", "-c", filepath.Join("test-resources", "test-config.yaml")}, args...))
+	logMutex.Lock()
+	err := rc.Execute()
+	logMutex.Unlock()
+	return stdoutBuf.String(), err
+}
+
+func TestSearchCommand(t *testing.T) {
+	testCases := []struct {
+		name         string
+		args         []string
+		expectedRefs []string
+		wantError    bool
+	}{
+		{
+			name:         "uses",
+			args:         []string{"uses:file"},
+			expectedRefs: []string{"another-repo//cleanup-tests/stress-tests.yaml", "test-repo//steps/file-step-demo.yaml"},
+		},
+		{
+			name:         "several-terms",
+			args:         []string{"repo:enum-repo -platform:windows"},
+			expectedRefs: []string{"enum-repo//platform-darwin.yaml", "enum-repo//platform-linux.yaml"},
+		},
+		{
+			name:         "quoted-query",
+			args:         []string{`name:"only run on" platform:darwin`},
+			expectedRefs: []string{"test-repo//requirements/darwin-only.yaml"},
+		},
+		{
+			name:         "no-results",
+			args:         []string{"does-not-match-anything"},
+			expectedRefs: []string{},
+		},
+		{
+			name:      "invalid-query",
+			args:      []string{"owner:me"},
+			wantError: true,
+		},
+		{
+			name:      "invalid-format",
+			args:      []string{"--format", "yaml"},
+			wantError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output, err := runSearchCommand(t, &TestConfig{}, append([]string{"--format", "json"}, tc.args...)...)
+			if tc.wantError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			var entries []*search.Entry
+			require.NoError(t, json.Unmarshal([]byte(output), &entries))
+			refs := []string{}
+			for _, e := range entries {
+				refs = append(refs, e.Ref)
+			}
+			assert.Equal(t, tc.expectedRefs, refs)
+		})
+	}
+}
+
+func TestSearchCommandTable(t *testing.T) {
+	output, err := runSearchCommand(t, &TestConfig{}, "repo:test-repo", "uses:create_file")
+	require.NoError(t, err)
+	assert.Regexp(t, `^REF +NAME +PLATFORMS +ACTIONS\n`, output)
+	assert.Regexp(t, `\ntest-repo//lint/clean.yaml +Clean TTP +linux,darwin +create_file,ttp\n`, output)
+	assert.Contains(t, output, "2 TTP(s) found\n")
+}
+
+func TestSearchCommandIndexCache(t *testing.T) {
+	indexPath := filepath.Join(t.TempDir(), "search-index.json")
+	testCfg := &TestConfig{SearchIndexPath: indexPath}
+
+	_, err := runSearchCommand(t, testCfg, "uses:inline")
+	require.NoError(t, err)
+	contents, err := os.ReadFile(indexPath)
+	require.NoError(t, err)
+	var idx search.Index
+	require.NoError(t, json.Unmarshal(contents, &idx))
+	assert.NotEmpty(t, idx.Records)
+
+	// the cached index is used for subsequent searches
+	// and rebuilt from scratch with --reindex
+	for _, args := range [][]string{{"uses:inline"}, {"uses:inline", "--reindex"}} {
+		output, err := runSearchCommand(t, testCfg, args...)
+		require.NoError(t, err)
+		assert.Contains(t, output, "another-repo//simple-inline.yaml")
+	}
+}
@@ -16,24 +16,31 @@ LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
 OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
 THE SOFTWARE.
 */
-
 package cmd
 
 import (
 	"fmt"
+	"io"
 
+	"github.com/facebookincubator/ttpforge/pkg/args"
+	"github.com/facebookincubator/ttpforge/pkg/mitre"
+	"github.com/facebookincubator/ttpforge/pkg/parseutils"
+	"github.com/facebookincubator/ttpforge/pkg/preprocess"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
 )
 
 func buildShowTTPCommand(cfg *Config) *cobra.Command {
+	var argsOnly bool
+	var mitreOnly bool
 	showTTPCmd := &cobra.Command{
 		Use:               "ttp",
 		Short:             "display info for a particular TTP",
 		Args:              cobra.ExactArgs(1),
 		ValidArgsFunction: completeTTPRef(cfg, 1),
-		RunE: func(cmd *cobra.Command, args []string) error {
-			ttpRef := args[0]
+		RunE: func(cmd *cobra.Command, cmdArgs []string) error {
+			ttpRef := cmdArgs[0]
 			_, ttpAbsPath, err := cfg.repoCollection.ResolveTTPRef(ttpRef)
 			if err != nil {
 				return fmt.Errorf("failed to resolve TTP reference %v: %v", ttpRef, err)
@@ -42,9 +49,107 @@ func buildShowTTPCommand(cfg *Config) *cobra.Command {
 			if err != nil {
 				return fmt.Errorf("failed to read file %v: %v", ttpAbsPath, err)
 			}
+			if argsOnly {
+				specs, err := parseArgSpecs(contents)
+				if err != nil {
+					return fmt.Errorf("failed to parse arguments of %v: %w", ttpRef, err)
+				}
+				printArgSpecs(cmd.OutOrStdout(), specs)
+				return nil
+			}
+			if mitreOnly {
+				ttp, err := parseutils.ParseTTP(contents, ttpAbsPath)
+				if err != nil {
+					return err
+				}
+				printMitreMapping(cmd.OutOrStdout(), ttp.Mitre)
+				return nil
+			}
 			fmt.Print(string(contents))
 			return nil
 		},
 	}
+	showTTPCmd.Flags().BoolVar(&argsOnly, "args", false, "Only describe the arguments of the TTP (types, defaults and constraints)")
+	showTTPCmd.Flags().BoolVar(&mitreOnly, "mitre", false, "Only describe the MITRE ATT&CK mapping of the TTP, with the names of its tactics and techniques")
+	showTTPCmd.MarkFlagsMutuallyExclusive("args", "mitre")
 	return showTTPCmd
 }
+
+// parseArgSpecs extracts the argument specifications
+// from the preamble of the provided TTP file contents
+func parseArgSpecs(contents []byte) ([]args.Spec, error) {
+	result, err := preprocess.Parse(contents)
+	if err != nil {
+		return nil, err
+	}
+	var preamble struct {
+		ArgSpecs []args.Spec `yaml:"args"`
+	}
+	if err := yaml.Unmarshal(result.PreambleBytes, &preamble); err != nil {
+		return nil, err
+	}
+	return preamble.ArgSpecs, nil
+}
+
+// printArgSpecs prints a human-readable description of each argument
+func printArgSpecs(w io.Writer, specs []args.Spec) {
+	if len(specs) == 0 {
+		fmt.Fprintln(w, "This TTP does not accept any arguments")
+		return
+	}
+	fmt.Fprintln(w, "Arguments:")
+	for _, spec := range specs {
+		printArgSpec(w, spec)
+	}
+}
+
+// printArgSpec prints a human-readable description of an argument
+func printArgSpec(w io.Writer, spec args.Spec) {
+	fmt.Fprintf(w, "  %v (%v)\n", spec.Name, spec.Summary())
+	if spec.Description != "" {
+		fmt.Fprintf(w, "      %v\n", spec.Description)
+	}
+	if spec.Type != "enum" {
+		return
+	}
+	for _, v := range spec.Values {
+		if v.Description != "" {
+			fmt.Fprintf(w, "      - %v: %v\n", v.Value, v.Description)
+		} else {
+			fmt.Fprintf(w, "      - %v\n", v.Value)
+		}
+	}
+}
+
+// printMitreMapping prints the MITRE ATT&CK mapping of a TTP,
+// looking up the name of each tactic and technique
+func printMitreMapping(w io.Writer, mapping parseutils.Mitre) {
+	if len(mapping.Tactics) == 0 && len(mapping.Techniques) == 0 && len(mapping.Subtechniques) == 0 {
+		fmt.Fprintln(w, "This TTP does not have a MITRE ATT&CK mapping")
+		return
+	}
+	catalogue := mitre.Default()
+	fmt.Fprintf(w, "MITRE ATT&CK (version %v):\n", catalogue.Version)
+	sections := []struct {
+		title   string
+		entries []string
+	}{
+		{"Tactics", mapping.Tactics},
+		{"Techniques", mapping.Techniques},
+		{"Sub-techniques", mapping.Subtechniques},
+	}
+	for _, section := range sections {
+		if len(section.entries) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "  %v:\n", section.title)
+		for _, entry := range section.entries {
+			id, _ := mitre.ParseEntry(entry)
+			if catalogue.Name(id) == "" {
+				fmt.Fprintf(w, "    %v (unknown)\n", entry)
+				continue
+			}
+			fmt.Fprintf(w, "    %v\n", catalogue.Describe(entry))
+		}
+	}
+}
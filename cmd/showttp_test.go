@@ -0,0 +1,107 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShowTTPArgs(t *testing.T) {
+	testConfigFilePath := filepath.Join(testResourcesDir, "test-config.yaml")
+
+	var stdoutBuf, stderrBuf bytes.Buffer
+	rc := BuildRootCommand(&TestConfig{
+		Stdout: &stdoutBuf,
+		Stderr: &stderrBuf,
+	})
+	rc.SetOut(&stdoutBuf)
+	rc.SetArgs([]string{"show", "ttp", "-c", testConfigFilePath, testRepoName + "//args/types/typed-args.yaml", "--args"})
+	logMutex.Lock()
+	err := rc.Execute()
+	logMutex.Unlock()
+	require.NoError(t, err)
+
+	expected := `Arguments:
+  target (ip, required)
+      The host to connect to
+  port (port, default: 8443, min: 1024)
+  timeout (duration, default: 30s, max: 5m)
+  mode (enum, default: fast)
+      - fast: Skip slow checks
+      - full
+  tags (list, optional)
+`
+	assert.Equal(t, expected, stdoutBuf.String())
+}
+
+func TestShowTTPMitre(t *testing.T) {
+	testConfigFilePath := filepath.Join(testResourcesDir, "test-config.yaml")
+	testCases := []struct {
+		name     string
+		ttpRef   string
+		expected string
+	}{
+		{
+			name:   "known-ids",
+			ttpRef: testRepoName + "//lint/clean.yaml",
+			expected: `MITRE ATT&CK (version 15.1):
+  Tactics:
+    TA0002 Execution
+  Techniques:
+    T1059 Command and Scripting Interpreter
+  Sub-techniques:
+    T1059.004 Command and Scripting Interpreter: Unix Shell
+`,
+		},
+		{
+			name:   "unknown-ids",
+			ttpRef: testRepoName + "//lint/problems.yaml",
+			expected: `MITRE ATT&CK (version 15.1):
+  Tactics:
+    Execution (unknown)
+`,
+		},
+		{
+			name:     "no-mapping",
+			ttpRef:   testRepoName + "//args/types/typed-args.yaml",
+			expected: "This TTP does not have a MITRE ATT&CK mapping\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var stdoutBuf bytes.Buffer
+			rc := BuildRootCommand(&TestConfig{
+				Stdout: &stdoutBuf,
+			})
+			rc.SetOut(&stdoutBuf)
+			rc.SetArgs([]string{"show", "ttp", "-c", testConfigFilePath, tc.ttpRef, "--mitre"})
+			logMutex.Lock()
+			err := rc.Execute()
+			logMutex.Unlock()
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, stdoutBuf.String())
+		})
+	}
+}
@@ -36,10 +36,10 @@ import (
 )
 
 type testCase struct {
-	Name        string            `yaml:"name"`
-	Description string            `yaml:"description"`
-	Args        map[string]string `yaml:"args"`
-	DryRun      bool              `yaml:"dry_run"`
+	Name        string                 `yaml:"name"`
+	Description string                 `yaml:"description"`
+	Args        map[string]interface{} `yaml:"args"`
+	DryRun      bool                   `yaml:"dry_run"`
 }
 
 // We want to verify everything but the steps themselves against
@@ -56,6 +56,7 @@ type ttpNonStepFields struct {
 // as a subprocess
 func buildTestCommand(cfg *Config) *cobra.Command {
 	var timeoutSeconds int
+	var strict bool
 	runCmd := &cobra.Command{
 		Use:               "test [repo_name//path/to/ttp]",
 		Short:             "Test the TTP found in the specified YAML file.",
@@ -71,7 +72,7 @@ func buildTestCommand(cfg *Config) *cobra.Command {
 				if err != nil {
 					return fmt.Errorf("failed to resolve TTP reference %v: %w", ttpRef, err)
 				}
-				if err := runTestsForTTP(ttpAbsPath, timeoutSeconds); err != nil {
+				if err := runTestsForTTP(ttpAbsPath, timeoutSeconds, strict); err != nil {
 					return fmt.Errorf("test(s) for TTP %v failed: %w", ttpRef, err)
 				}
 			}
@@ -79,11 +80,42 @@ func buildTestCommand(cfg *Config) *cobra.Command {
 		},
 	}
 	runCmd.PersistentFlags().IntVar(&timeoutSeconds, "time-out-seconds", 10, "Timeout allowed for each test case")
+	runCmd.PersistentFlags().BoolVar(&strict, "strict", false, "Reject TTPs that contain keys that are not part of the TTP format (such as misspelled field names)")
 
 	return runCmd
 }
 
-func runTestsForTTP(ttpAbsPath string, timeoutSeconds int) error {
+// runTestCase runs a single test case of a TTP with `ttpforge run`,
+// removing its temporary arguments file (if any) once it finishes
+func runTestCase(selfPath, ttpAbsPath string, tc testCase, timeoutSeconds int, strict bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeoutSeconds)*time.Second)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, selfPath)
+	cmd.Args = append(cmd.Args, "run", ttpAbsPath)
+	if len(tc.Args) > 0 {
+		argsFile, err := writeArgsFile(tc.Args)
+		if err != nil {
+			return fmt.Errorf("test case %q: %w", tc.Name, err)
+		}
+		defer os.Remove(argsFile)
+		cmd.Args = append(cmd.Args, "--args-file", argsFile)
+	}
+	if tc.DryRun {
+		cmd.Args = append(cmd.Args, "--dry-run")
+	}
+	if strict {
+		cmd.Args = append(cmd.Args, "--strict")
+	}
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("test case %q failed: %w", tc.Name, err)
+	}
+	return nil
+}
+
+func runTestsForTTP(ttpAbsPath string, timeoutSeconds int, strict bool) error {
 	logging.DividerThick()
 	logging.L().Infof("TESTING TTP FILE:")
 	logging.L().Info(ttpAbsPath)
@@ -144,26 +176,33 @@ func runTestsForTTP(ttpAbsPath string, timeoutSeconds int) error {
 		logging.DividerThin()
 		logging.L().Infof("RUNNING TEST CASE #%d: %q", tcIdx+1, tc.Name)
 		logging.DividerThin()
-		ctx, cancel := context.WithTimeout(context.Background(), time.Duration(timeoutSeconds)*time.Second)
-		defer cancel()
-		cmd := exec.CommandContext(ctx, selfPath)
-		cmd.Args = append(cmd.Args, "run", ttpAbsPath)
-		for argName, argVal := range tc.Args {
-			cmd.Args = append(cmd.Args, "--arg")
-			cmd.Args = append(cmd.Args, argName+"="+argVal)
-		}
-		if tc.DryRun {
-			cmd.Args = append(cmd.Args, "--dry-run")
-		}
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
-
-		err = cmd.Run()
-		if err != nil {
-			return fmt.Errorf("test case %q failed: %w", tc.Name, err)
+		if err := runTestCase(selfPath, ttpAbsPath, tc, timeoutSeconds, strict); err != nil {
+			return err
 		}
 	}
 	logging.DividerThin()
 	logging.L().Info("ALL TESTS COMPLETED SUCCESSFULLY!")
 	return nil
 }
+
+// writeArgsFile writes the arguments of a test case to a temporary
+// arguments file to be passed to `ttpforge run --args-file`
+func writeArgsFile(tcArgs map[string]interface{}) (string, error) {
+	contents, err := yaml.Marshal(tcArgs)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal test case arguments: %w", err)
+	}
+	f, err := os.CreateTemp("", "ttpforge-test-args-*.yaml")
+	if err != nil {
+		return "", fmt.Errorf("failed to create arguments file: %w", err)
+	}
+	_, err = f.Write(contents)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", fmt.Errorf("failed to write arguments file: %w", err)
+	}
+	return f.Name(), nil
+}
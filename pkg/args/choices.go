@@ -34,14 +34,40 @@ func (spec Spec) validateChoiceTypes() error {
 }
 
 func (spec Spec) isValidChoice(value string) bool {
-	if len(spec.Choices) == 0 {
+	allowed := spec.allowedValues()
+	if len(allowed) == 0 {
 		return true
 	}
 
-	for _, choice := range spec.Choices {
-		if choice == value {
-			return true
+	// every item of a list must be one of the choices
+	values := []string{value}
+	if spec.Type == "list" {
+		values = splitList(value)
+	}
+	for _, v := range values {
+		found := false
+		for _, choice := range allowed {
+			if choice == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
 		}
 	}
-	return false
+	return true
+}
+
+// allowedValues returns the values that the argument may take,
+// or nil if it is not restricted to a predefined set of values
+func (spec Spec) allowedValues() []string {
+	if spec.Type != "enum" {
+		return spec.Choices
+	}
+	allowed := make([]string, len(spec.Values))
+	for idx, v := range spec.Values {
+		allowed[idx] = v.Value
+	}
+	return allowed
 }
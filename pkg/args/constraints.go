@@ -0,0 +1,166 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package args
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnumValue is one of the allowed values of an `enum` argument
+type EnumValue struct {
+	Value       string `yaml:"value"`
+	Description string `yaml:"description,omitempty"`
+}
+
+// UnmarshalYAML allows enum values without
+// a description to be specified as plain strings
+func (v *EnumValue) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&v.Value)
+	}
+	type rawEnumValue EnumValue
+	return node.Decode((*rawEnumValue)(v))
+}
+
+// splitList splits the value of a list argument into its
+// comma-separated items, ignoring surrounding whitespace
+// and empty items
+func splitList(val string) []string {
+	items := []string{}
+	for _, item := range strings.Split(val, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// validateConstraints checks that the enum values and the
+// constraints of the spec are valid for the type of the argument
+func (spec Spec) validateConstraints() error {
+	if spec.Type == "enum" {
+		if len(spec.Values) == 0 {
+			return fmt.Errorf("enum argument %v must specify its allowed `values:`", spec.Name)
+		}
+		if len(spec.Choices) > 0 {
+			return fmt.Errorf("enum argument %v must use `values:` rather than `choices:`", spec.Name)
+		}
+	} else if len(spec.Values) > 0 {
+		return fmt.Errorf("argument %v: `values:` can only be used with enum arguments", spec.Name)
+	}
+
+	var bounds []float64
+	for _, bound := range []string{spec.Min, spec.Max} {
+		if bound == "" {
+			continue
+		}
+		parsed, err := spec.parseBound(bound)
+		if err != nil {
+			return fmt.Errorf("argument %v: %w", spec.Name, err)
+		}
+		bounds = append(bounds, parsed)
+	}
+	if len(bounds) == 2 && bounds[0] > bounds[1] {
+		return fmt.Errorf("argument %v: `min:` must not be greater than `max:`", spec.Name)
+	}
+
+	if spec.MinLength != nil {
+		switch spec.Type {
+		case "", "string", "secret", "list":
+		default:
+			return fmt.Errorf("argument %v: `min_length:` can only be used with string, secret and list arguments", spec.Name)
+		}
+		if *spec.MinLength < 0 {
+			return fmt.Errorf("argument %v: `min_length:` must not be negative", spec.Name)
+		}
+	}
+	return nil
+}
+
+// parseBound parses a `min:` or `max:` bound according to
+// the type of the argument - durations are converted to
+// nanoseconds so that all bounds can be compared as numbers
+func (spec Spec) parseBound(bound string) (float64, error) {
+	switch spec.Type {
+	case "int", "float", "port":
+		parsed, err := strconv.ParseFloat(bound, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid bound %q: must be a number", bound)
+		}
+		return parsed, nil
+	case "duration":
+		parsed, err := time.ParseDuration(bound)
+		if err != nil {
+			return 0, fmt.Errorf("invalid bound %q: must be a duration", bound)
+		}
+		return float64(parsed), nil
+	default:
+		return 0, errors.New("`min:` and `max:` can only be used with int, float, port and duration arguments")
+	}
+}
+
+// checkConstraints checks that a value that was already
+// converted to the type of the argument satisfies its constraints
+func (spec Spec) checkConstraints(val any) error {
+	if val == nil {
+		return nil
+	}
+	if spec.Min != "" || spec.Max != "" {
+		var num float64
+		switch v := val.(type) {
+		case int:
+			num = float64(v)
+		case float64:
+			num = v
+		case time.Duration:
+			num = float64(v)
+		}
+		if spec.Min != "" {
+			if min, _ := spec.parseBound(spec.Min); num < min {
+				return fmt.Errorf("value must be at least %v", spec.Min)
+			}
+		}
+		if spec.Max != "" {
+			if max, _ := spec.parseBound(spec.Max); num > max {
+				return fmt.Errorf("value must be at most %v", spec.Max)
+			}
+		}
+	}
+	if spec.MinLength != nil {
+		switch v := val.(type) {
+		case string:
+			if utf8.RuneCountInString(v) < *spec.MinLength {
+				return fmt.Errorf("value must be at least %d characters long", *spec.MinLength)
+			}
+		case []string:
+			if len(v) < *spec.MinLength {
+				return fmt.Errorf("value must have at least %d items", *spec.MinLength)
+			}
+		}
+	}
+	return nil
+}
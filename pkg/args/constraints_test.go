@@ -0,0 +1,311 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package args
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func intPtr(i int) *int {
+	return &i
+}
+
+func TestArgTypes(t *testing.T) {
+	testCases := []validateTestCase{
+		{
+			name: "Float, Duration, URL, IP, CIDR and Port",
+			specs: []Spec{
+				{Name: "ratio", Type: "float"},
+				{Name: "timeout", Type: "duration"},
+				{Name: "endpoint", Type: "url"},
+				{Name: "host", Type: "ip"},
+				{Name: "subnet", Type: "cidr"},
+				{Name: "port", Type: "port"},
+			},
+			argKvStrs: []string{
+				"ratio=0.5",
+				"timeout=1m30s",
+				"endpoint=https://example.com/api",
+				"host=::1",
+				"subnet=10.0.0.0/8",
+				"port=8080",
+			},
+			expectedResult: map[string]any{
+				"ratio":    0.5,
+				"timeout":  90 * time.Second,
+				"endpoint": "https://example.com/api",
+				"host":     "::1",
+				"subnet":   "10.0.0.0/8",
+				"port":     8080,
+			},
+		},
+		{
+			name:      "Invalid Float",
+			specs:     []Spec{{Name: "ratio", Type: "float"}},
+			argKvStrs: []string{"ratio=half"},
+			wantError: true,
+		},
+		{
+			name:      "Invalid Duration",
+			specs:     []Spec{{Name: "timeout", Type: "duration"}},
+			argKvStrs: []string{"timeout=10"},
+			wantError: true,
+		},
+		{
+			name:      "URL Without Host",
+			specs:     []Spec{{Name: "endpoint", Type: "url"}},
+			argKvStrs: []string{"endpoint=example.com/api"},
+			wantError: true,
+		},
+		{
+			name:      "Invalid IP",
+			specs:     []Spec{{Name: "host", Type: "ip"}},
+			argKvStrs: []string{"host=10.0.0.256"},
+			wantError: true,
+		},
+		{
+			name:      "Invalid CIDR",
+			specs:     []Spec{{Name: "subnet", Type: "cidr"}},
+			argKvStrs: []string{"subnet=10.0.0.0"},
+			wantError: true,
+		},
+		{
+			name:      "Port Out Of Range",
+			specs:     []Spec{{Name: "port", Type: "port"}},
+			argKvStrs: []string{"port=70000"},
+			wantError: true,
+		},
+		{
+			name:           "List From Commas And Repeated Args",
+			specs:          []Spec{{Name: "hosts", Type: "list", Default: "default"}},
+			argKvStrs:      []string{"hosts=a, b", "hosts=c"},
+			expectedResult: map[string]any{"hosts": []string{"a", "b", "c"}},
+		},
+		{
+			name:           "List Default",
+			specs:          []Spec{{Name: "hosts", Type: "list", Default: "a,b"}},
+			expectedResult: map[string]any{"hosts": []string{"a", "b"}},
+		},
+		{
+			name:      "List Items Must Be Choices",
+			specs:     []Spec{{Name: "hosts", Type: "list", Choices: []string{"a", "b"}}},
+			argKvStrs: []string{"hosts=a,c"},
+			wantError: true,
+		},
+		{
+			name: "Enum",
+			specs: []Spec{{Name: "mode", Type: "enum", Default: "fast", Values: []EnumValue{
+				{Value: "fast", Description: "skip slow checks"},
+				{Value: "full"},
+			}}},
+			argKvStrs:      []string{"mode=full"},
+			expectedResult: map[string]any{"mode": "full"},
+		},
+		{
+			name:      "Invalid Enum Value",
+			specs:     []Spec{{Name: "mode", Type: "enum", Values: []EnumValue{{Value: "fast"}}}},
+			argKvStrs: []string{"mode=slow"},
+			wantError: true,
+		},
+		{
+			name:      "Enum Without Values",
+			specs:     []Spec{{Name: "mode", Type: "enum"}},
+			argKvStrs: []string{"mode=slow"},
+			wantError: true,
+		},
+		{
+			name:      "Values On Non-Enum",
+			specs:     []Spec{{Name: "mode", Values: []EnumValue{{Value: "fast"}}}},
+			argKvStrs: []string{"mode=fast"},
+			wantError: true,
+		},
+		{
+			name:           "Optional Without Value",
+			specs:          []Spec{{Name: "proxy", Type: "url", Optional: true}},
+			expectedResult: map[string]any{"proxy": nil},
+		},
+		{
+			name:           "Optional With Value",
+			specs:          []Spec{{Name: "proxy", Type: "url", Optional: true}},
+			argKvStrs:      []string{"proxy=http://localhost:8080"},
+			expectedResult: map[string]any{"proxy": "http://localhost:8080"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			checkValidateTestCase(t, tc)
+		})
+	}
+}
+
+func TestArgConstraints(t *testing.T) {
+	testCases := []validateTestCase{
+		{
+			name:           "Int Within Bounds",
+			specs:          []Spec{{Name: "count", Type: "int", Min: "1", Max: "10"}},
+			argKvStrs:      []string{"count=10"},
+			expectedResult: map[string]any{"count": 10},
+		},
+		{
+			name:      "Int Below Min",
+			specs:     []Spec{{Name: "count", Type: "int", Min: "1"}},
+			argKvStrs: []string{"count=0"},
+			wantError: true,
+		},
+		{
+			name:      "Float Above Max",
+			specs:     []Spec{{Name: "ratio", Type: "float", Max: "1"}},
+			argKvStrs: []string{"ratio=1.5"},
+			wantError: true,
+		},
+		{
+			name:           "Duration Bounds",
+			specs:          []Spec{{Name: "timeout", Type: "duration", Min: "1s", Max: "1h"}},
+			argKvStrs:      []string{"timeout=30m"},
+			expectedResult: map[string]any{"timeout": 30 * time.Minute},
+		},
+		{
+			name:      "Duration Above Max",
+			specs:     []Spec{{Name: "timeout", Type: "duration", Max: "1h"}},
+			argKvStrs: []string{"timeout=2h"},
+			wantError: true,
+		},
+		{
+			name:      "Default Violates Bounds",
+			specs:     []Spec{{Name: "port", Type: "port", Min: "1024", Default: "80"}},
+			argKvStrs: []string{"port=8080"},
+			wantError: true,
+		},
+		{
+			name:      "Min Greater Than Max",
+			specs:     []Spec{{Name: "count", Type: "int", Min: "5", Max: "1"}},
+			argKvStrs: []string{"count=3"},
+			wantError: true,
+		},
+		{
+			name:      "Bounds On String",
+			specs:     []Spec{{Name: "name", Min: "1"}},
+			argKvStrs: []string{"name=x"},
+			wantError: true,
+		},
+		{
+			name:      "Invalid Duration Bound",
+			specs:     []Spec{{Name: "timeout", Type: "duration", Min: "5"}},
+			argKvStrs: []string{"timeout=5s"},
+			wantError: true,
+		},
+		{
+			name:      "String Too Short",
+			specs:     []Spec{{Name: "password", Type: "secret", MinLength: intPtr(8)}},
+			argKvStrs: []string{"password=short"},
+			wantError: true,
+		},
+		{
+			name:           "List Long Enough",
+			specs:          []Spec{{Name: "hosts", Type: "list", MinLength: intPtr(2)}},
+			argKvStrs:      []string{"hosts=a", "hosts=b"},
+			expectedResult: map[string]any{"hosts": []string{"a", "b"}},
+		},
+		{
+			name:      "List Too Short",
+			specs:     []Spec{{Name: "hosts", Type: "list", MinLength: intPtr(2)}},
+			argKvStrs: []string{"hosts=a"},
+			wantError: true,
+		},
+		{
+			name:      "Min Length On Int",
+			specs:     []Spec{{Name: "count", Type: "int", MinLength: intPtr(2)}},
+			argKvStrs: []string{"count=1"},
+			wantError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			checkValidateTestCase(t, tc)
+		})
+	}
+}
+
+func TestEnumValueUnmarshal(t *testing.T) {
+	var spec Spec
+	err := yaml.Unmarshal([]byte(`name: mode
+type: enum
+values:
+  - fast
+  - value: full
+    description: run every check`), &spec)
+	require.NoError(t, err)
+	assert.Equal(t, []EnumValue{
+		{Value: "fast"},
+		{Value: "full", Description: "run every check"},
+	}, spec.Values)
+}
+
+func TestSpecSummary(t *testing.T) {
+	testCases := []struct {
+		name     string
+		spec     Spec
+		expected string
+	}{
+		{
+			name:     "Required String",
+			spec:     Spec{Name: "target"},
+			expected: "string, required",
+		},
+		{
+			name:     "Bounded Int With Default",
+			spec:     Spec{Name: "count", Type: "int", Default: "3", Min: "1", Max: "10"},
+			expected: "int, default: 3, min: 1, max: 10",
+		},
+		{
+			name:     "Optional List",
+			spec:     Spec{Name: "hosts", Type: "list", Optional: true, MinLength: intPtr(1), Choices: []string{"a", "b"}},
+			expected: "list, optional, min_length: 1, choices: a, b",
+		},
+		{
+			name:     "Secret",
+			spec:     Spec{Name: "token", Type: "secret", Env: "TOKEN"},
+			expected: "secret, required, env: TOKEN",
+		},
+		{
+			name: "Rules",
+			spec: Spec{
+				Name:          "proxy_url",
+				RequiredIf:    map[string]string{"use_proxy": "true"},
+				ConflictsWith: []string{"direct"},
+				DependsOn:     []string{"proxy_user"},
+			},
+			expected: "string, required if use_proxy=true, conflicts with: direct, depends on: proxy_user",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.spec.Summary())
+		})
+	}
+}
@@ -0,0 +1,96 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package args
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Summary describes the type, default value and constraints of the
+// argument on a single line, such as `int, default: 3, min: 1`
+func (spec Spec) Summary() string {
+	typ := spec.Type
+	if typ == "" {
+		typ = "string"
+	}
+	parts := []string{typ}
+	switch {
+	case spec.Default != "":
+		parts = append(parts, "default: "+spec.Default)
+	case len(spec.RequiredIf) > 0:
+		var conds []string
+		for _, name := range spec.requiredIfNames() {
+			conds = append(conds, name+"="+spec.RequiredIf[name])
+		}
+		parts = append(parts, "required if "+strings.Join(conds, " and "))
+	case spec.Optional:
+		parts = append(parts, "optional")
+	default:
+		parts = append(parts, "required")
+	}
+	if spec.Min != "" {
+		parts = append(parts, "min: "+spec.Min)
+	}
+	if spec.Max != "" {
+		parts = append(parts, "max: "+spec.Max)
+	}
+	if spec.MinLength != nil {
+		parts = append(parts, fmt.Sprintf("min_length: %d", *spec.MinLength))
+	}
+	if len(spec.Choices) > 0 {
+		parts = append(parts, "choices: "+strings.Join(spec.Choices, ", "))
+	}
+	if spec.Format != "" {
+		parts = append(parts, "regexp: "+spec.Format)
+	}
+	if len(spec.ConflictsWith) > 0 {
+		parts = append(parts, "conflicts with: "+strings.Join(spec.ConflictsWith, ", "))
+	}
+	if len(spec.DependsOn) > 0 {
+		parts = append(parts, "depends on: "+strings.Join(spec.DependsOn, ", "))
+	}
+	if spec.Env != "" {
+		parts = append(parts, "env: "+spec.Env)
+	}
+	if spec.File != "" {
+		parts = append(parts, "file: "+spec.File)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Suggestions returns the values of the argument that can be
+// offered by shell completion, or nil if any value is allowed
+func (spec Spec) Suggestions() []EnumValue {
+	switch {
+	case spec.Type == "enum":
+		return spec.Values
+	case spec.Type == "bool":
+		return []EnumValue{{Value: "true"}, {Value: "false"}}
+	case len(spec.Choices) > 0:
+		suggestions := make([]EnumValue, len(spec.Choices))
+		for idx, choice := range spec.Choices {
+			suggestions[idx] = EnumValue{Value: choice}
+		}
+		return suggestions
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,131 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package args
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// validateRules checks that the required_if, conflicts_with and
+// depends_on rules of the spec refer to other arguments of the TTP
+// and that the values in its required_if conditions are valid
+func (spec Spec) validateRules(specsByName map[string]Spec) error {
+	checkRef := func(field, name string) (Spec, error) {
+		if name == spec.Name {
+			return Spec{}, fmt.Errorf("argument %v cannot refer to itself in `%v:`", spec.Name, field)
+		}
+		other, ok := specsByName[name]
+		if !ok {
+			return Spec{}, fmt.Errorf("argument %v refers to unknown argument %v in `%v:`", spec.Name, name, field)
+		}
+		return other, nil
+	}
+
+	if len(spec.RequiredIf) > 0 && spec.Default != "" {
+		return fmt.Errorf("argument %v cannot have both a default value and `required_if:`", spec.Name)
+	}
+	for _, name := range spec.requiredIfNames() {
+		other, err := checkRef("required_if", name)
+		if err != nil {
+			return err
+		}
+		if _, err := other.convertArgToType(spec.RequiredIf[name]); err != nil {
+			return fmt.Errorf("argument %v: invalid value %v for argument %v in `required_if:`: %w", spec.Name, spec.RequiredIf[name], name, err)
+		}
+	}
+	for _, name := range spec.ConflictsWith {
+		if _, err := checkRef("conflicts_with", name); err != nil {
+			return err
+		}
+	}
+	for _, name := range spec.DependsOn {
+		if _, err := checkRef("depends_on", name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// requiredIfNames returns the names of the arguments
+// in the required_if conditions of the spec, sorted
+// so that error messages are deterministic
+func (spec Spec) requiredIfNames() []string {
+	names := make([]string, 0, len(spec.RequiredIf))
+	for name := range spec.RequiredIf {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// requiredIfHolds returns true if every required_if
+// condition of the spec holds for the provided values
+func (spec Spec) requiredIfHolds(values map[string]any, specsByName map[string]Spec) bool {
+	for name, condVal := range spec.RequiredIf {
+		typedCondVal, err := specsByName[name].convertArgToType(condVal)
+		if err != nil || !reflect.DeepEqual(values[name], typedCondVal) {
+			return false
+		}
+	}
+	return true
+}
+
+// describeRequiredIf formats the required_if
+// conditions of the spec for error messages
+func (spec Spec) describeRequiredIf() string {
+	var conds []string
+	for _, name := range spec.requiredIfNames() {
+		conds = append(conds, fmt.Sprintf("argument '%v' is '%v'", name, spec.RequiredIf[name]))
+	}
+	return strings.Join(conds, " and ")
+}
+
+// checkRules enforces the conflicts_with and depends_on
+// rules of every spec once all argument values are known
+//
+// **Parameters:**
+//
+// specs: the argument specifications of the TTP
+// values: the value of each argument (nil if it has no value)
+// provided: whether each argument was provided by the user
+//
+// **Returns:**
+//
+// error: an error naming both arguments of the first violated rule
+func checkRules(specs []Spec, values map[string]any, provided map[string]bool) error {
+	for _, spec := range specs {
+		if !provided[spec.Name] {
+			continue
+		}
+		for _, name := range spec.ConflictsWith {
+			if provided[name] {
+				return fmt.Errorf("arguments '%v' and '%v' cannot be used together", spec.Name, name)
+			}
+		}
+		for _, name := range spec.DependsOn {
+			if values[name] == nil {
+				return fmt.Errorf("argument '%v' requires argument '%v' to be set", spec.Name, name)
+			}
+		}
+	}
+	return nil
+}
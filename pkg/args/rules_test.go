@@ -0,0 +1,156 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package args
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArgRules(t *testing.T) {
+	proxySpecs := []Spec{
+		{Name: "use_proxy", Type: "bool", Default: "false"},
+		{Name: "proxy_url", Type: "url", RequiredIf: map[string]string{"use_proxy": "true"}},
+	}
+	exclusiveSpecs := []Spec{
+		{Name: "password", Type: "string", Optional: true, ConflictsWith: []string{"key_file"}},
+		{Name: "key_file", Type: "string", Optional: true},
+	}
+	dependentSpecs := []Spec{
+		{Name: "username", Optional: true},
+		{Name: "password", Optional: true, DependsOn: []string{"username"}},
+	}
+
+	testCases := []struct {
+		name           string
+		specs          []Spec
+		argKvStrs      []string
+		expectedResult map[string]any
+		wantError      string
+	}{
+		{
+			name:  "required-if-condition-false",
+			specs: proxySpecs,
+			expectedResult: map[string]any{
+				"use_proxy": false,
+				"proxy_url": nil,
+			},
+		},
+		{
+			name:      "required-if-condition-true",
+			specs:     proxySpecs,
+			argKvStrs: []string{"use_proxy=true"},
+			wantError: "argument 'proxy_url' is required when argument 'use_proxy' is 'true'",
+		},
+		{
+			name:      "required-if-satisfied",
+			specs:     proxySpecs,
+			argKvStrs: []string{"use_proxy=true", "proxy_url=http://proxy:3128"},
+			expectedResult: map[string]any{
+				"use_proxy": true,
+				"proxy_url": "http://proxy:3128",
+			},
+		},
+		{
+			name:      "conflicting-arguments",
+			specs:     exclusiveSpecs,
+			argKvStrs: []string{"password=x", "key_file=id_rsa"},
+			wantError: "arguments 'password' and 'key_file' cannot be used together",
+		},
+		{
+			name:      "one-of-conflicting-arguments",
+			specs:     exclusiveSpecs,
+			argKvStrs: []string{"key_file=id_rsa"},
+			expectedResult: map[string]any{
+				"password": nil,
+				"key_file": "id_rsa",
+			},
+		},
+		{
+			name:      "dependency-missing",
+			specs:     dependentSpecs,
+			argKvStrs: []string{"password=x"},
+			wantError: "argument 'password' requires argument 'username' to be set",
+		},
+		{
+			name:      "dependency-satisfied",
+			specs:     dependentSpecs,
+			argKvStrs: []string{"password=x", "username=admin"},
+			expectedResult: map[string]any{
+				"username": "admin",
+				"password": "x",
+			},
+		},
+		{
+			name: "unknown-argument-in-rule",
+			specs: []Spec{
+				{Name: "a", Optional: true, DependsOn: []string{"b"}},
+			},
+			wantError: "argument a refers to unknown argument b in `depends_on:`",
+		},
+		{
+			name: "self-reference",
+			specs: []Spec{
+				{Name: "a", Optional: true, ConflictsWith: []string{"a"}},
+			},
+			wantError: "argument a cannot refer to itself in `conflicts_with:`",
+		},
+		{
+			name: "invalid-required-if-value",
+			specs: []Spec{
+				{Name: "use_proxy", Type: "bool", Default: "false"},
+				{Name: "proxy_url", RequiredIf: map[string]string{"use_proxy": "maybe"}},
+			},
+			wantError: "argument proxy_url: invalid value maybe for argument use_proxy in `required_if:`",
+		},
+		{
+			name: "required-if-with-default",
+			specs: []Spec{
+				{Name: "use_proxy", Type: "bool", Default: "false"},
+				{Name: "proxy_url", Default: "http://proxy", RequiredIf: map[string]string{"use_proxy": "true"}},
+			},
+			wantError: "argument proxy_url cannot have both a default value and `required_if:`",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := ParseAndValidate(tc.specs, tc.argKvStrs, "", "")
+			if tc.wantError != "" {
+				require.ErrorContains(t, err, tc.wantError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedResult, result)
+		})
+	}
+}
+
+func TestMissingRequiredIf(t *testing.T) {
+	specs := []Spec{
+		{Name: "use_proxy", Type: "bool", Default: "false"},
+		{Name: "proxy_url", RequiredIf: map[string]string{"use_proxy": "true"}},
+	}
+	assert.Empty(t, Missing(specs, nil))
+	missing := Missing(specs, []string{"use_proxy=true"})
+	require.Len(t, missing, 1)
+	assert.Equal(t, "proxy_url", missing[0].Name)
+}
@@ -0,0 +1,104 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package args
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/facebookincubator/ttpforge/pkg/fileutils"
+)
+
+// PromptForSecret reads the value of a secret argument that was not
+// provided by any other source. It is nil unless ttpforge is running
+// in an interactive terminal, in which case the CLI sets it.
+var PromptForSecret func(name string) (string, error)
+
+// IsSecret returns true if the values of this argument
+// must be masked in logs, reports and run journals
+func (spec Spec) IsSecret() bool {
+	return spec.Type == "secret"
+}
+
+// validateSecretFields checks that the secret source fields
+// are only used with secret arguments and that secret
+// arguments do not embed their value in the TTP
+func (spec Spec) validateSecretFields() error {
+	if !spec.IsSecret() {
+		if spec.Env != "" || spec.File != "" {
+			return fmt.Errorf("argument %v: `env:` and `file:` can only be used with secret arguments", spec.Name)
+		}
+		return nil
+	}
+	if spec.Default != "" {
+		return fmt.Errorf("secret argument %v cannot have a default value", spec.Name)
+	}
+	if len(spec.Choices) > 0 {
+		return fmt.Errorf("secret argument %v cannot have choices", spec.Name)
+	}
+	return nil
+}
+
+// resolveSecret reads the value of a secret argument from its
+// environment variable, then its file and finally an interactive prompt
+//
+// **Parameters:**
+//
+// baseDir: the directory to resolve a relative `file:` path relative to
+//
+// **Returns:**
+//
+// string: the secret value
+// bool: whether a value was found
+// error: an error if a source exists but could not be read
+func (spec Spec) resolveSecret(baseDir string) (string, bool, error) {
+	if spec.Env != "" {
+		if val, ok := os.LookupEnv(spec.Env); ok {
+			return val, true, nil
+		}
+	}
+	if spec.File != "" {
+		path, err := fileutils.ExpandPath(spec.File)
+		if err != nil {
+			return "", false, err
+		}
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(baseDir, path)
+		}
+		contents, err := os.ReadFile(path)
+		if err == nil {
+			return strings.TrimRight(string(contents), "\r\n"), true, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", false, fmt.Errorf("failed to read secret argument %v from file: %w", spec.Name, err)
+		}
+	}
+	if PromptForSecret != nil {
+		val, err := PromptForSecret(spec.Name)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to read secret argument %v: %w", spec.Name, err)
+		}
+		return val, true, nil
+	}
+	return "", false, nil
+}
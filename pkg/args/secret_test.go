@@ -0,0 +1,109 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package args
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretArgs(t *testing.T) {
+	secretDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(secretDir, "token.txt"), []byte("from-file\n"), 0600))
+	t.Setenv("TTPFORGE_TEST_SECRET", "from-env")
+
+	testCases := []struct {
+		name           string
+		spec           Spec
+		argKvStrs      []string
+		prompt         func(name string) (string, error)
+		expectedResult map[string]any
+		wantError      bool
+	}{
+		{
+			name:           "Command Line Takes Precedence",
+			spec:           Spec{Name: "token", Type: "secret", Env: "TTPFORGE_TEST_SECRET"},
+			argKvStrs:      []string{"token=from-cli"},
+			expectedResult: map[string]any{"token": "from-cli"},
+		},
+		{
+			name:           "Environment Variable",
+			spec:           Spec{Name: "token", Type: "secret", Env: "TTPFORGE_TEST_SECRET", File: "token.txt"},
+			expectedResult: map[string]any{"token": "from-env"},
+		},
+		{
+			name:           "File Relative To Base Dir",
+			spec:           Spec{Name: "token", Type: "secret", Env: "TTPFORGE_TEST_UNSET", File: "token.txt"},
+			expectedResult: map[string]any{"token": "from-file"},
+		},
+		{
+			name: "Prompt When No Other Source",
+			spec: Spec{Name: "token", Type: "secret", File: "missing.txt"},
+			prompt: func(name string) (string, error) {
+				return "from-prompt-" + name, nil
+			},
+			expectedResult: map[string]any{"token": "from-prompt-token"},
+		},
+		{
+			name: "Prompt Failure",
+			spec: Spec{Name: "token", Type: "secret"},
+			prompt: func(name string) (string, error) {
+				return "", errors.New("no terminal")
+			},
+			wantError: true,
+		},
+		{
+			name:      "Missing Secret",
+			spec:      Spec{Name: "token", Type: "secret", Env: "TTPFORGE_TEST_UNSET"},
+			wantError: true,
+		},
+		{
+			name:      "Secret With Default",
+			spec:      Spec{Name: "token", Type: "secret", Default: "oops"},
+			wantError: true,
+		},
+		{
+			name:      "Env Source On Non-Secret",
+			spec:      Spec{Name: "token", Env: "TTPFORGE_TEST_SECRET"},
+			argKvStrs: []string{"token=x"},
+			wantError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			PromptForSecret = tc.prompt
+			t.Cleanup(func() { PromptForSecret = nil })
+
+			args, err := ParseAndValidate([]Spec{tc.spec}, tc.argKvStrs, "", secretDir)
+			if tc.wantError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedResult, args)
+		})
+	}
+}
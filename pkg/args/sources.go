@@ -0,0 +1,265 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package args
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvVarPrefix is the prefix of the environment variables that
+// provide argument values - the value of the argument `target_host`
+// is read from TTPFORGE_ARG_TARGET_HOST
+const EnvVarPrefix = "TTPFORGE_ARG_"
+
+// Values maps argument names to the values that a single
+// source provides for them - list arguments may have several values
+type Values map[string][]string
+
+// EnvVarName returns the name of the environment
+// variable that provides the value of this argument
+func (spec Spec) EnvVarName() string {
+	return EnvVarPrefix + strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, spec.Name)
+}
+
+// ParseKvStrs collects the values of arguments
+// passed in "ARG_NAME=ARG_VALUE" format
+//
+// **Parameters:**
+//
+// argsKvStrs: slice of arguments in "ARG_NAME=ARG_VALUE" format
+//
+// **Returns:**
+//
+// Values: the values of each argument, in the order they were passed
+// error: an error if a string is not in "ARG_NAME=ARG_VALUE" format
+func ParseKvStrs(argsKvStrs []string) (Values, error) {
+	values := Values{}
+	for _, argKvStr := range argsKvStrs {
+		name, val, ok := strings.Cut(argKvStr, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid argument specification string: %v", argKvStr)
+		}
+		values[name] = append(values[name], val)
+	}
+	return values, nil
+}
+
+// LoadFile reads argument values from a YAML or JSON file
+// that maps argument names to scalar values or (for list
+// arguments) lists of scalar values
+//
+// **Parameters:**
+//
+// fsys: the file system to read the file from
+// path: the path of the file
+//
+// **Returns:**
+//
+// Values: the values of each argument in the file
+// error: an error if the file cannot be read or has an invalid structure
+func LoadFile(fsys afero.Fs, path string) (Values, error) {
+	contents, err := afero.ReadFile(fsys, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read arguments file: %w", err)
+	}
+	// YAML is a superset of JSON, so this handles both formats
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(contents, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse arguments file %v: %w", path, err)
+	}
+	values := Values{}
+	for name, val := range raw {
+		switch v := val.(type) {
+		case []interface{}:
+			items := []string{}
+			for _, item := range v {
+				s, err := scalarString(item)
+				if err != nil {
+					return nil, fmt.Errorf("invalid value for argument %v in arguments file %v: %w", name, path, err)
+				}
+				items = append(items, s)
+			}
+			values[name] = items
+		default:
+			s, err := scalarString(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for argument %v in arguments file %v: %w", name, path, err)
+			}
+			values[name] = []string{s}
+		}
+	}
+	return values, nil
+}
+
+// scalarString formats a scalar value read from an arguments file
+func scalarString(val interface{}) (string, error) {
+	switch v := val.(type) {
+	case nil:
+		return "", fmt.Errorf("value must not be null")
+	case map[string]interface{}, []interface{}:
+		return "", fmt.Errorf("value must be a scalar or a list of scalars")
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
+
+// FromEnv reads the values of the specified arguments from their
+// TTPFORGE_ARG_<NAME> environment variables
+//
+// **Parameters:**
+//
+// specs: the argument specifications of the TTP
+// environ: the environment in "KEY=value" format (usually os.Environ())
+//
+// **Returns:**
+//
+// Values: the value of each argument whose environment variable is set
+func FromEnv(specs []Spec, environ []string) Values {
+	env := make(map[string]string)
+	for _, kv := range environ {
+		if key, val, ok := strings.Cut(kv, "="); ok && strings.HasPrefix(key, EnvVarPrefix) {
+			env[key] = val
+		}
+	}
+	values := Values{}
+	for _, spec := range specs {
+		if val, ok := env[spec.EnvVarName()]; ok {
+			values[spec.Name] = []string{val}
+		}
+	}
+	return values
+}
+
+// Merge combines the argument values provided by several sources
+// into the "ARG_NAME=ARG_VALUE" strings accepted by ParseAndValidate.
+// Sources are listed from highest to lowest precedence: the value(s)
+// of an argument are taken from the first source that provides it,
+// so for example a list passed on the command line replaces
+// (rather than extends) the list from an arguments file.
+//
+// **Parameters:**
+//
+// specs: the argument specifications of the TTP
+// sources: the argument values of each source, highest precedence first
+//
+// **Returns:**
+//
+// []string: the merged arguments in "ARG_NAME=ARG_VALUE" format
+// error: an error listing any arguments that the TTP does not accept
+func Merge(specs []Spec, sources ...Values) ([]string, error) {
+	known := make(map[string]bool, len(specs))
+	for _, spec := range specs {
+		known[spec.Name] = true
+	}
+	var unknown []string
+	seen := make(map[string]bool)
+	for _, source := range sources {
+		for name := range source {
+			if !known[name] && !seen[name] {
+				unknown = append(unknown, name)
+				seen[name] = true
+			}
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("received unexpected argument(s): %v", strings.Join(unknown, ", "))
+	}
+
+	merged := []string{}
+	for _, spec := range specs {
+		for _, source := range sources {
+			vals, ok := source[spec.Name]
+			if !ok {
+				continue
+			}
+			for _, val := range vals {
+				merged = append(merged, spec.Name+"="+val)
+			}
+			break
+		}
+	}
+	return merged, nil
+}
+
+// Missing returns the specifications of the required arguments
+// that are not provided by the specified arguments and have no
+// default value, including arguments whose `required_if:` conditions
+// hold. Secret arguments are not included because they are read
+// from their own sources.
+//
+// **Parameters:**
+//
+// specs: the argument specifications of the TTP
+// argsKvStrs: slice of arguments in "ARG_NAME=ARG_VALUE" format
+//
+// **Returns:**
+//
+// []Spec: the specifications of the missing arguments, in declaration order
+func Missing(specs []Spec, argsKvStrs []string) []Spec {
+	provided := make(map[string]bool)
+	specsByName := make(map[string]Spec)
+	values := make(map[string]any)
+	for _, spec := range specs {
+		specsByName[spec.Name] = spec
+		if val, err := spec.convertArgToType(spec.Default); spec.Default != "" && err == nil {
+			values[spec.Name] = val
+		}
+	}
+	for _, argKvStr := range argsKvStrs {
+		name, argVal, ok := strings.Cut(argKvStr, "=")
+		if !ok {
+			continue
+		}
+		provided[name] = true
+		if val, err := specsByName[name].convertArgToType(argVal); err == nil {
+			values[name] = val
+		}
+	}
+
+	var missing []Spec
+	for _, spec := range specs {
+		if provided[spec.Name] || spec.Default != "" || spec.IsSecret() {
+			continue
+		}
+		if len(spec.RequiredIf) > 0 {
+			if !spec.requiredIfHolds(values, specsByName) {
+				continue
+			}
+		} else if spec.Optional {
+			continue
+		}
+		missing = append(missing, spec)
+	}
+	return missing
+}
@@ -0,0 +1,202 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package args
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvVarName(t *testing.T) {
+	assert.Equal(t, "TTPFORGE_ARG_TARGET_HOST", Spec{Name: "target_host"}.EnvVarName())
+	assert.Equal(t, "TTPFORGE_ARG_OUT_DIR2", Spec{Name: "out-dir2"}.EnvVarName())
+}
+
+func TestLoadFile(t *testing.T) {
+	testCases := []struct {
+		name           string
+		path           string
+		contents       string
+		expectedValues Values
+		wantError      bool
+	}{
+		{
+			name: "yaml",
+			path: "args.yaml",
+			contents: `host: example.com
+port: 8080
+verbose: true
+tags:
+  - a
+  - b
+`,
+			expectedValues: Values{
+				"host":    {"example.com"},
+				"port":    {"8080"},
+				"verbose": {"true"},
+				"tags":    {"a", "b"},
+			},
+		},
+		{
+			name:     "json",
+			path:     "args.json",
+			contents: `{"host": "example.com", "ratio": 0.5, "tags": ["a"]}`,
+			expectedValues: Values{
+				"host":  {"example.com"},
+				"ratio": {"0.5"},
+				"tags":  {"a"},
+			},
+		},
+		{
+			name:      "nested-map",
+			path:      "args.yaml",
+			contents:  "host:\n  name: example.com\n",
+			wantError: true,
+		},
+		{
+			name:      "null-value",
+			path:      "args.yaml",
+			contents:  "host: null\n",
+			wantError: true,
+		},
+		{
+			name:      "not-a-map",
+			path:      "args.yaml",
+			contents:  "- a\n- b\n",
+			wantError: true,
+		},
+		{
+			name:      "missing-file",
+			path:      "missing.yaml",
+			wantError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fsys := afero.NewMemMapFs()
+			if tc.contents != "" {
+				require.NoError(t, afero.WriteFile(fsys, tc.path, []byte(tc.contents), 0644))
+			}
+			values, err := LoadFile(fsys, tc.path)
+			if tc.wantError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedValues, values)
+		})
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	specs := []Spec{
+		{Name: "host"},
+		{Name: "port", Type: "int"},
+	}
+	environ := []string{
+		"TTPFORGE_ARG_HOST=example.com",
+		"TTPFORGE_ARG_OTHER=ignored",
+		"PORT=80",
+	}
+	assert.Equal(t, Values{"host": {"example.com"}}, FromEnv(specs, environ))
+}
+
+func TestMerge(t *testing.T) {
+	specs := []Spec{
+		{Name: "host"},
+		{Name: "port", Type: "int", Default: "80"},
+		{Name: "tags", Type: "list"},
+		{Name: "mode", Default: "fast"},
+	}
+
+	testCases := []struct {
+		name           string
+		sources        []Values
+		expectedKvStrs []string
+		wantError      string
+	}{
+		{
+			name: "precedence",
+			sources: []Values{
+				{"host": {"cli.example.com"}},
+				{"host": {"file.example.com"}, "port": {"8080"}},
+				{"host": {"env.example.com"}, "port": {"9090"}, "mode": {"full"}},
+			},
+			expectedKvStrs: []string{
+				"host=cli.example.com",
+				"port=8080",
+				"mode=full",
+			},
+		},
+		{
+			name: "lists-are-replaced",
+			sources: []Values{
+				{"tags": {"c"}},
+				{"tags": {"a", "b"}},
+			},
+			expectedKvStrs: []string{"tags=c"},
+		},
+		{
+			name:           "no-values",
+			sources:        []Values{{}, {}},
+			expectedKvStrs: []string{},
+		},
+		{
+			name: "unknown-arguments",
+			sources: []Values{
+				{"host": {"example.com"}, "prot": {"80"}},
+				{"tag": {"a"}},
+			},
+			wantError: "received unexpected argument(s): prot, tag",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			kvStrs, err := Merge(specs, tc.sources...)
+			if tc.wantError != "" {
+				require.EqualError(t, err, tc.wantError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedKvStrs, kvStrs)
+		})
+	}
+}
+
+func TestMissing(t *testing.T) {
+	specs := []Spec{
+		{Name: "host"},
+		{Name: "port", Type: "int", Default: "80"},
+		{Name: "tags", Type: "list", Optional: true},
+		{Name: "token", Type: "secret"},
+		{Name: "mode"},
+		{Name: "user"},
+	}
+	missing := Missing(specs, []string{"host=example.com", "invalid"})
+	var names []string
+	for _, spec := range missing {
+		names = append(names, spec.Name)
+	}
+	assert.Equal(t, []string{"mode", "user"}, names)
+}
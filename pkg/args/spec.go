@@ -22,21 +22,50 @@ package args
 import (
 	"errors"
 	"fmt"
+	"net"
+	"net/url"
 	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/facebookincubator/ttpforge/pkg/fileutils"
 )
 
 // Spec defines a CLI argument for the TTP
 type Spec struct {
-	Name    string   `yaml:"name"`
-	Type    string   `yaml:"type,omitempty"`
-	Default string   `yaml:"default,omitempty"`
-	Choices []string `yaml:"choices,omitempty"`
-	Format  string   `yaml:"regexp,omitempty"`
+	Name        string   `yaml:"name"`
+	Description string   `yaml:"description,omitempty"`
+	Type        string   `yaml:"type,omitempty"`
+	Default     string   `yaml:"default,omitempty"`
+	Choices     []string `yaml:"choices,omitempty"`
+	Format      string   `yaml:"regexp,omitempty"`
+	// Optional arguments do not need a value or a default -
+	// if no value is provided, the argument value is nil
+	Optional bool `yaml:"optional,omitempty"`
+	// Values lists the allowed values of an `enum` argument
+	Values []EnumValue `yaml:"values,omitempty"`
+	// Min and Max bound the values of numeric and
+	// duration arguments, and MinLength bounds the length
+	// of string arguments and the number of list items
+	Min       string `yaml:"min,omitempty"`
+	Max       string `yaml:"max,omitempty"`
+	MinLength *int   `yaml:"min_length,omitempty"`
+	// Env and File are the environment variable and file from
+	// which the value of a `secret` argument is read if it
+	// is not provided on the command line
+	Env  string `yaml:"env,omitempty"`
+	File string `yaml:"file,omitempty"`
+	// RequiredIf makes an argument without a default required
+	// only when each listed argument has the listed value
+	RequiredIf map[string]string `yaml:"required_if,omitempty"`
+	// ConflictsWith lists the arguments that cannot be
+	// provided together with this one
+	ConflictsWith []string `yaml:"conflicts_with,omitempty"`
+	// DependsOn lists the arguments that must have a value
+	// when this one is provided
+	DependsOn []string `yaml:"depends_on,omitempty"`
 
 	formatReg *regexp.Regexp
 }
@@ -69,11 +98,19 @@ func ParseAndValidate(specs []Spec, argsKvStrs []string, cliBaseDir string, defa
 			return nil, fmt.Errorf("failed to validate types of choice values: %w", err)
 		}
 
+		if err := spec.validateSecretFields(); err != nil {
+			return nil, err
+		}
+
+		if err := spec.validateConstraints(); err != nil {
+			return nil, err
+		}
+
 		// set the default value, will be overwritten by passed value
 		// Path defaults are resolved relative to defaultBaseDir (typically the YAML directory)
 		if spec.Default != "" {
 			if !spec.isValidChoice(spec.Default) {
-				return nil, fmt.Errorf("invalid default value: %v, allowed values: %v ", spec.Default, strings.Join(spec.Choices, ", "))
+				return nil, fmt.Errorf("invalid default value: %v, allowed values: %v ", spec.Default, strings.Join(spec.allowedValues(), ", "))
 			}
 
 			// For path types, resolve relative paths relative to defaultBaseDir
@@ -87,6 +124,9 @@ func ParseAndValidate(specs []Spec, argsKvStrs []string, cliBaseDir string, defa
 			if err != nil {
 				return nil, fmt.Errorf("default value type does not match spec: %w", err)
 			}
+			if err := spec.checkConstraints(defaultVal); err != nil {
+				return nil, fmt.Errorf("default value of argument %v is invalid: %w", spec.Name, err)
+			}
 			processedArgs[spec.Name] = defaultVal
 		}
 
@@ -109,8 +149,14 @@ func ParseAndValidate(specs []Spec, argsKvStrs []string, cliBaseDir string, defa
 		}
 		specsByName[spec.Name] = spec
 	}
+	for _, spec := range specs {
+		if err := spec.validateRules(specsByName); err != nil {
+			return nil, err
+		}
+	}
 
 	// validate the inputs
+	provided := make(map[string]bool)
 	for _, argKvStr := range argsKvStrs {
 		argKv := strings.SplitN(argKvStr, "=", 2)
 		if len(argKv) != 2 {
@@ -125,47 +171,122 @@ func ParseAndValidate(specs []Spec, argsKvStrs []string, cliBaseDir string, defa
 			return nil, fmt.Errorf("received unexpected argument: %v ", argName)
 		}
 
-		if !spec.isValidChoice(argVal) {
-			return nil, fmt.Errorf("received unexpected value: %v, allowed values: %v ", argVal, strings.Join(spec.Choices, ", "))
+		typedVal, err := spec.parseValue(argVal, cliBaseDir)
+		if err != nil {
+			return nil, err
 		}
 
-		if spec.formatReg != nil && !spec.formatReg.MatchString(argVal) {
-			return nil, fmt.Errorf("invalid value format: %v, expected regex format: %v ", argVal, spec.Format)
+		// list arguments can be specified more than once, in which
+		// case the items of every occurrence are combined
+		if items, ok := processedArgs[argName].([]string); ok && provided[argName] {
+			typedVal = append(items, typedVal.([]string)...)
 		}
 
-		// For path types, resolve relative paths relative to cliBaseDir
-		// Absolute paths and paths with shell variables are left as-is
-		argValue := argVal
-		if spec.Type == "path" && !filepath.IsAbs(argVal) && !fileutils.ContainsShellVariable(argVal) {
-			argValue = filepath.Join(cliBaseDir, argVal)
-		}
+		// valid arg value - save
+		processedArgs[argName] = typedVal
+		provided[argName] = true
+	}
 
-		typedVal, err := spec.convertArgToType(argValue)
+	// secret arguments that were not provided on the command line
+	// are read from their environment variable, file or a prompt
+	for _, spec := range specs {
+		if _, ok := processedArgs[spec.Name]; ok || !spec.IsSecret() {
+			continue
+		}
+		secretVal, found, err := spec.resolveSecret(defaultBaseDir)
 		if err != nil {
-			return nil, fmt.Errorf(
-				"failed to process value '%v' specified for argument '%v': %v",
-				argVal,
-				argName,
-				err,
-			)
+			return nil, err
+		}
+		if found {
+			processedArgs[spec.Name] = secretVal
+			provided[spec.Name] = true
 		}
-
-		// valid arg value - save
-		processedArgs[argName] = typedVal
 	}
 
 	// error if argument was not provided and no default value was specified
 	for _, spec := range specs {
-		if _, ok := processedArgs[spec.Name]; !ok {
+		val, ok := processedArgs[spec.Name]
+		if !ok {
+			if len(spec.RequiredIf) > 0 && spec.requiredIfHolds(processedArgs, specsByName) {
+				return nil, fmt.Errorf("argument '%v' is required when %v", spec.Name, spec.describeRequiredIf())
+			}
+			if spec.Optional || len(spec.RequiredIf) > 0 {
+				processedArgs[spec.Name] = nil
+				continue
+			}
 			return nil, fmt.Errorf("value for required argument '%v' was not provided and no default value was specified", spec.Name)
 		}
+		if err := spec.checkConstraints(val); err != nil {
+			return nil, fmt.Errorf("invalid value for argument '%v': %w", spec.Name, err)
+		}
+	}
+	if err := checkRules(specs, processedArgs, provided); err != nil {
+		return nil, err
 	}
 	return processedArgs, nil
 }
 
+// parseValue checks that a value provided for the argument is
+// an allowed choice that matches its regexp, and converts it to
+// the type of the argument. Relative path values are resolved
+// relative to cliBaseDir.
+func (spec Spec) parseValue(argVal string, cliBaseDir string) (any, error) {
+	if !spec.isValidChoice(argVal) {
+		return nil, fmt.Errorf("received unexpected value: %v, allowed values: %v ", argVal, strings.Join(spec.allowedValues(), ", "))
+	}
+
+	if spec.formatReg != nil && !spec.formatReg.MatchString(argVal) {
+		return nil, fmt.Errorf("invalid value format: %v, expected regex format: %v ", argVal, spec.Format)
+	}
+
+	// For path types, resolve relative paths relative to cliBaseDir
+	// Absolute paths and paths with shell variables are left as-is
+	argValue := argVal
+	if spec.Type == "path" && !filepath.IsAbs(argVal) && !fileutils.ContainsShellVariable(argVal) {
+		argValue = filepath.Join(cliBaseDir, argVal)
+	}
+
+	typedVal, err := spec.convertArgToType(argValue)
+	if err != nil {
+		return nil, fmt.Errorf(
+			"failed to process value '%v' specified for argument '%v': %v",
+			argVal,
+			spec.Name,
+			err,
+		)
+	}
+	return typedVal, nil
+}
+
+// ValidateValue checks a single value provided for the argument
+// in the same way as ParseAndValidate does, including its choices,
+// regexp, type and constraints
+//
+// **Parameters:**
+//
+// val: the value to check
+//
+// **Returns:**
+//
+// error: an error describing why the value is invalid, or nil
+func (spec Spec) ValidateValue(val string) error {
+	if spec.Format != "" && spec.formatReg == nil {
+		formatReg, err := regexp.Compile(spec.Format)
+		if err != nil {
+			return fmt.Errorf("invalid regular expression supplied to arg spec format: %w", err)
+		}
+		spec.formatReg = formatReg
+	}
+	typedVal, err := spec.parseValue(val, "")
+	if err != nil {
+		return err
+	}
+	return spec.checkConstraints(typedVal)
+}
+
 func (spec Spec) convertArgToType(val string) (any, error) {
 	switch spec.Type {
-	case "", "string":
+	case "", "string", "secret":
 		// string is the default - any string is valid
 		return val, nil
 	case "int":
@@ -180,6 +301,46 @@ func (spec Spec) convertArgToType(val string) (any, error) {
 			return nil, errors.New("no-boolean value provided")
 		}
 		return asBool, nil
+	case "float":
+		asFloat, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, errors.New("non-numeric value provided")
+		}
+		return asFloat, nil
+	case "duration":
+		asDuration, err := time.ParseDuration(val)
+		if err != nil {
+			return nil, errors.New("invalid duration provided (expected a value such as 30s or 5m)")
+		}
+		return asDuration, nil
+	case "url":
+		parsed, err := url.Parse(val)
+		if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			return nil, errors.New("invalid URL provided (expected a value such as https://example.com/path)")
+		}
+		return val, nil
+	case "ip":
+		if net.ParseIP(val) == nil {
+			return nil, errors.New("invalid IP address provided")
+		}
+		return val, nil
+	case "cidr":
+		if _, _, err := net.ParseCIDR(val); err != nil {
+			return nil, errors.New("invalid CIDR range provided (expected a value such as 10.0.0.0/8)")
+		}
+		return val, nil
+	case "port":
+		asPort, err := strconv.Atoi(val)
+		if err != nil || asPort < 1 || asPort > 65535 {
+			return nil, errors.New("invalid port provided (expected an integer between 1 and 65535)")
+		}
+		return asPort, nil
+	case "list":
+		return splitList(val), nil
+	case "enum":
+		// membership is checked against the
+		// allowed values by isValidChoice
+		return val, nil
 	case "path":
 		// Path has already been resolved relative to appropriate baseDir
 		// Just convert to absolute and resolve symlinks
@@ -571,3 +571,58 @@ func TestPathArgHandling(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateValue(t *testing.T) {
+	testCases := []struct {
+		name      string
+		spec      Spec
+		val       string
+		wantError bool
+	}{
+		{
+			name: "valid-int",
+			spec: Spec{Name: "count", Type: "int", Min: "1"},
+			val:  "3",
+		},
+		{
+			name:      "int-below-min",
+			spec:      Spec{Name: "count", Type: "int", Min: "1"},
+			val:       "0",
+			wantError: true,
+		},
+		{
+			name:      "wrong-type",
+			spec:      Spec{Name: "count", Type: "int"},
+			val:       "three",
+			wantError: true,
+		},
+		{
+			name:      "invalid-choice",
+			spec:      Spec{Name: "letter", Choices: []string{"a", "b"}},
+			val:       "c",
+			wantError: true,
+		},
+		{
+			name:      "regexp-mismatch",
+			spec:      Spec{Name: "id", Format: "^[0-9]+$"},
+			val:       "abc",
+			wantError: true,
+		},
+		{
+			name: "regexp-match",
+			spec: Spec{Name: "id", Format: "^[0-9]+$"},
+			val:  "123",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.spec.ValidateValue(tc.val)
+			if tc.wantError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -69,3 +69,51 @@ func (ad *actionDefaults) GetDefaultCleanupAction() Action {
 func (ad *actionDefaults) CanBeUsedInCompositeAction() bool {
 	return false
 }
+
+// ActionTypeName returns the YAML key that identifies
+// the type of the provided action (such as `inline` or `create_file`).
+// It is used to describe actions in logs and execution reports.
+func ActionTypeName(action Action) string {
+	switch action.(type) {
+	case *BasicStep:
+		return "inline"
+	case *FileStep:
+		return "file"
+	case *SubTTPStep, *subTTPCleanupAction:
+		return "ttp"
+	case *EditStep:
+		return "edit_file"
+	case *FetchURIStep:
+		return "fetch_uri"
+	case *CreateFileStep:
+		return "create_file"
+	case *RenderTemplateStep:
+		return "render_template"
+	case *CopyPathStep:
+		return "copy_path"
+	case *RemovePathAction:
+		return "remove_path"
+	case *PrintStrAction:
+		return "print_str"
+	case *ExpectStep:
+		return "expect"
+	case *HTTPRequestStep:
+		return "http_request"
+	case *KillProcessStep:
+		return "kill_process"
+	case *StartProcessStep, *stopProcessAction:
+		return "start_process"
+	case *WaitForStep:
+		return "wait_for"
+	case *ChangeDirectoryStep:
+		return "cd"
+	case *CompositeAction:
+		return "composite"
+	case *ParallelStep, *parallelCleanupAction:
+		return "parallel"
+	case *loopAction, *loopCleanupAction:
+		return "loop"
+	default:
+		return "unknown"
+	}
+}
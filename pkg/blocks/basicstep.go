@@ -32,9 +32,19 @@ import (
 	"go.uber.org/zap"
 )
 
-// DefaultExecutionTimeout is the default timeout for step execution.
+// DefaultExecutionTimeout is the default timeout for
+// commands run by steps that have no timeout of their own.
 const DefaultExecutionTimeout = 100 * time.Minute
 
+// contextWithDefaultTimeout applies DefaultExecutionTimeout
+// to ctx unless it already has a deadline
+func contextWithDefaultTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, DefaultExecutionTimeout)
+}
+
 // BasicStep is a type that represents a basic execution step.
 type BasicStep struct {
 	actionDefaults `yaml:",inline"`
@@ -103,7 +113,7 @@ func (b *BasicStep) Template(execCtx TTPExecutionContext) error {
 
 // Execute runs the step and returns an error if one occurs.
 func (b *BasicStep) Execute(execCtx TTPExecutionContext) (*ActResult, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), DefaultExecutionTimeout)
+	ctx, cancel := contextWithDefaultTimeout(execCtx.Context())
 	defer cancel()
 
 	if b.Inline == "" {
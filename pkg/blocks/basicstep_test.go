@@ -102,7 +102,7 @@ func TestBasicStepExecuteWithTemplate(t *testing.T) {
 inline: echo "this is {[{.StepVars.foo}]}"`
 	var s BasicStep
 	execCtx := NewTTPExecutionContext()
-	execCtx.Vars.StepVars = map[string]string{
+	execCtx.Vars.StepVars = map[string]interface{}{
 		"foo": "successfully templated",
 	}
 	err := yaml.Unmarshal([]byte(content), &s)
@@ -169,7 +169,7 @@ inline: echo "bar"
 outputvar: foo`
 	var s BasicStep
 	execCtx := NewTTPExecutionContext()
-	execCtx.Vars.StepVars = map[string]string{
+	execCtx.Vars.StepVars = map[string]interface{}{
 		"foo": "overwrite me",
 	}
 	err := yaml.Unmarshal([]byte(content), &s)
@@ -34,7 +34,7 @@ func TestChangeDirectoryExecute(t *testing.T) {
 		description            string
 		step                   *ChangeDirectoryStep
 		fsysContents           map[string][]byte
-		stepVars               map[string]string
+		stepVars               map[string]interface{}
 		expectTemplateError    bool
 		expectedExecutionError bool
 		startingDir            string
@@ -49,7 +49,7 @@ func TestChangeDirectoryExecute(t *testing.T) {
 				"/home/testuser/test": []byte("test"),
 				"/tmp/test":           []byte("test"),
 			},
-			stepVars:               map[string]string{},
+			stepVars:               map[string]interface{}{},
 			expectTemplateError:    false,
 			expectedExecutionError: false,
 			startingDir:            "/home/testuser/",
@@ -60,7 +60,7 @@ func TestChangeDirectoryExecute(t *testing.T) {
 			step: &ChangeDirectoryStep{
 				Cd: "/doesntexist",
 			},
-			stepVars: map[string]string{},
+			stepVars: map[string]interface{}{},
 			fsysContents: map[string][]byte{
 				"/home/testuser/test": []byte("test"),
 				"/tmp/test":           []byte("test"),
@@ -75,7 +75,7 @@ func TestChangeDirectoryExecute(t *testing.T) {
 			step: &ChangeDirectoryStep{
 				Cd: "",
 			},
-			stepVars: map[string]string{},
+			stepVars: map[string]interface{}{},
 			fsysContents: map[string][]byte{
 				"/home/testuser/test": []byte("test"),
 				"/tmp/test":           []byte("test"),
@@ -90,7 +90,7 @@ func TestChangeDirectoryExecute(t *testing.T) {
 			step: &ChangeDirectoryStep{
 				Cd: "/tmp/{[{ .StepVars.foo }]}",
 			},
-			stepVars: map[string]string{
+			stepVars: map[string]interface{}{
 				"foo": "bar",
 			},
 			fsysContents: map[string][]byte{
@@ -107,7 +107,7 @@ func TestChangeDirectoryExecute(t *testing.T) {
 			step: &ChangeDirectoryStep{
 				Cd: "/tmp/{[{ .StepVars.foo }]}",
 			},
-			stepVars: map[string]string{},
+			stepVars: map[string]interface{}{},
 			fsysContents: map[string][]byte{
 				"/home/testuser/test": []byte("test"),
 				"/tmp/bar/test":       []byte("test"),
@@ -21,14 +21,19 @@ package blocks
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/Masterminds/sprig/v3"
+	"github.com/facebookincubator/ttpforge/pkg/platforms"
 	"github.com/facebookincubator/ttpforge/pkg/repos"
 	"io"
 	"regexp"
+	"strconv"
 	"strings"
 	"text/template"
+	"time"
 )
 
 const contextVariablePrefix = "$forge."
@@ -41,25 +46,64 @@ type TTPExecutionConfig struct {
 	NoCleanup           bool
 	NoChecks            bool
 	CleanupDelaySeconds uint
-	Repo                repos.Repo
-	Stdout              io.Writer
-	Stderr              io.Writer
+	// StepTimeout is the default timeout for steps
+	// that do not specify their own - zero means no timeout
+	StepTimeout time.Duration
+	// Strict rejects TTPs that contain keys
+	// that are not part of the TTP format
+	Strict bool
+	// Environment is the environment of the steps of the TTP
+	// (before their own env is applied), which sub-TTPs inherit -
+	// nil means the environment of TTPForge
+	Environment map[string]string
+	// CacheDir is where downloaded files are cached
+	// - an empty string disables caching
+	CacheDir string
+	Repo     repos.Repo
+	Stdout   io.Writer
+	Stderr   io.Writer
 }
 
-// TTPExecutionVars - mutable store to carry variables between steps
+// TTPExecutionVars - mutable store to carry variables between steps.
+// Args and Platform are also exposed so that step templates
+// and `if:` conditions can reference them at run time, as is
+// the current iteration (if any) of a step with a loop.
+// StepVars may hold values of any type, such as the lists
+// and maps produced by typed step outputs.
 type TTPExecutionVars struct {
 	WorkDir  string
-	StepVars map[string]string
+	StepVars map[string]interface{}
+	Args     map[string]interface{}
+	Platform platforms.Spec
+	Loop     *LoopVars
+}
+
+// copy returns a copy of the variables that can be
+// modified without affecting the original
+func (v *TTPExecutionVars) copy() *TTPExecutionVars {
+	stepVars := make(map[string]interface{}, len(v.StepVars))
+	for k, val := range v.StepVars {
+		stepVars[k] = val
+	}
+	return &TTPExecutionVars{
+		WorkDir:  v.WorkDir,
+		StepVars: stepVars,
+		Args:     v.Args,
+		Platform: v.Platform,
+		Loop:     v.Loop,
+	}
 }
 
 // TTPExecutionContext - holds config and context for the currently executing TTP
 type TTPExecutionContext struct {
-	Cfg               TTPExecutionConfig
-	Vars              *TTPExecutionVars
-	StepResults       *StepResultsRecord
-	actionResultsChan chan *ActResult
-	errorsChan        chan error
-	shutdownChan      chan bool
+	Cfg         TTPExecutionConfig
+	Vars        *TTPExecutionVars
+	StepResults *StepResultsRecord
+	// Journal (if set) persists the state of the run as it progresses
+	Journal      *RunJournal
+	shutdownChan chan bool
+	// ctx carries the deadline of the currently executing step
+	ctx context.Context
 }
 
 // NewTTPExecutionContext creates a new TTPExecutionContext with empty config and created channels
@@ -67,13 +111,30 @@ func NewTTPExecutionContext() TTPExecutionContext {
 	return TTPExecutionContext{
 		Vars: &TTPExecutionVars{
 			WorkDir:  "/",
-			StepVars: make(map[string]string),
+			StepVars: make(map[string]interface{}),
+			Args:     make(map[string]interface{}),
+			Platform: platforms.GetCurrentPlatformSpec(),
 		},
-		StepResults:       NewStepResultsRecord(),
-		actionResultsChan: make(chan *ActResult, 1),
-		errorsChan:        make(chan error, 1),
-		shutdownChan:      SetupSignalHandler(),
+		StepResults:  NewStepResultsRecord(),
+		shutdownChan: SetupSignalHandler(),
+	}
+}
+
+// Context returns the context that actions should use to
+// bound their execution (for example with exec.CommandContext).
+// It is cancelled when the currently executing step times out.
+func (c TTPExecutionContext) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
 	}
+	return c.ctx
+}
+
+// withContext returns a copy of the execution
+// context that uses the provided context
+func (c TTPExecutionContext) withContext(ctx context.Context) TTPExecutionContext {
+	c.ctx = ctx
+	return c
 }
 
 // ExpandVariables takes a string containing the following types of variables
@@ -81,6 +142,10 @@ func NewTTPExecutionContext() TTPExecutionContext {
 //
 // * Step outputs: ($forge.steps.bar.outputs.baz)
 //
+// Outputs that hold lists or maps can be indexed further
+// ($forge.steps.bar.outputs.hosts.0.ip) - lists and maps
+// that are referenced as a whole expand to JSON.
+//
 // **Parameters:**
 //
 // inStrs: the list of strings that have variables expanded
@@ -93,7 +158,7 @@ func (c TTPExecutionContext) ExpandVariables(inStrs []string) ([]string, error)
 	re := regexp.MustCompile(
 		`\$*` + regexp.QuoteMeta(contextVariablePrefix) + `[\w\.]*`,
 	)
-	var expandedStrs []string
+	expandedStrs := make([]string, 0, len(inStrs))
 	for _, inStr := range inStrs {
 		var failedMatch string
 		var failedMatchError error
@@ -113,6 +178,15 @@ func (c TTPExecutionContext) ExpandVariables(inStrs []string) ([]string, error)
 	return expandedStrs, nil
 }
 
+// stepTemplateData is the data available to step templates
+// and `if:` conditions. In addition to the execution variables,
+// it exposes the results of the steps executed so far by name
+// (e.g. `{[{ range .Steps.scan.Outputs.hosts }]}`).
+type stepTemplateData struct {
+	*TTPExecutionVars
+	Steps map[string]*ExecutionResult
+}
+
 // templateStep takes a string and templates it with variables from the context at this point in the TTP
 //
 // **Parameters:**
@@ -128,14 +202,203 @@ func (c TTPExecutionContext) templateStep(input string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	var output bytes.Buffer
-	err = tmpl.Execute(&output, c.Vars)
+	return c.executeTemplate(tmpl)
+}
+
+// renderTemplate renders the contents of a template file with the
+// same data and functions as step templates - but since template
+// files are not part of the TTP, they use the regular `{{ }}` delimiters
+//
+// **Parameters:**
+//
+// name: the name of the template, used in error messages
+// contents: the contents of the template
+//
+// **Returns:**
+//
+// string: the rendered template
+// error: an error if there is a problem
+func (c TTPExecutionContext) renderTemplate(name, contents string) (string, error) {
+	tmpl, err := template.New(name).Funcs(sprig.TxtFuncMap()).Option("missingkey=error").Parse(contents)
 	if err != nil {
 		return "", err
 	}
+	return c.executeTemplate(tmpl)
+}
+
+// executeTemplate executes a template with the
+// variables from the context at this point in the TTP
+func (c TTPExecutionContext) executeTemplate(tmpl *template.Template) (string, error) {
+	return executeTemplateWithData(tmpl, c.templateData())
+}
+
+// templateData returns the data available to step
+// templates at this point in the TTP
+func (c TTPExecutionContext) templateData() stepTemplateData {
+	data := stepTemplateData{TTPExecutionVars: c.Vars}
+	if c.StepResults != nil {
+		data.Steps = c.StepResults.ByName
+	}
+	return data
+}
+
+func executeTemplateWithData(tmpl *template.Template, data interface{}) (string, error) {
+	var output bytes.Buffer
+	if err := tmpl.Execute(&output, data); err != nil {
+		return "", err
+	}
 	return output.String(), nil
 }
 
+// conditionData is the data available to `if:` conditions - the
+// values of the step result references in the condition are passed
+// in Refs rather than pasted into it, so that they cannot break
+// (or change the meaning of) the expression
+type conditionData struct {
+	stepTemplateData
+	Refs []interface{}
+}
+
+// conditionTokenRegex matches the parts of a condition that may
+// contain step result references: string literals and bare references
+var conditionTokenRegex = regexp.MustCompile(
+	`"(?:[^"\\]|\\.)*"|` + "`[^`]*`" + `|\$*` + regexp.QuoteMeta(contextVariablePrefix) + `[\w\.]*`,
+)
+
+// conditionRefRegex matches string literal
+// contents that consist of a single reference
+var conditionRefRegex = regexp.MustCompile(`^\$*` + regexp.QuoteMeta(contextVariablePrefix) + `[\w\.]*$`)
+
+// evaluateCondition evaluates a step `if:` expression as the condition
+// of a template `if` action (e.g. `eq .Args.mode "full"`) using the same
+// data and functions as step templates. Step result references such as
+// $forge.steps.foo.stdout are replaced by their values: a reference that
+// makes up a whole string literal (or stands on its own) becomes the
+// value itself, while references inside longer string literals are
+// expanded within the (re-quoted) literal.
+//
+// **Parameters:**
+//
+// expr: the condition to evaluate
+//
+// **Returns:**
+//
+// bool: whether the condition holds
+// error: an error if the expression could not be evaluated
+func (c TTPExecutionContext) evaluateCondition(expr string) (bool, error) {
+	data := conditionData{stepTemplateData: c.templateData()}
+	var refErr error
+	rewritten := conditionTokenRegex.ReplaceAllStringFunc(expr, func(token string) string {
+		if refErr != nil {
+			return token
+		}
+		val, replacement, err := c.rewriteConditionToken(token)
+		if err != nil {
+			refErr = err
+			return token
+		}
+		if replacement != "" {
+			return replacement
+		}
+		data.Refs = append(data.Refs, val)
+		return fmt.Sprintf("(index .Refs %d)", len(data.Refs)-1)
+	})
+	if refErr != nil {
+		return false, refErr
+	}
+
+	tmplStr := fmt.Sprintf("%s if %s %strue%s end %s",
+		stepTemplateLeftDelim, rewritten, stepTemplateRightDelim,
+		stepTemplateLeftDelim, stepTemplateRightDelim)
+	tmpl, err := template.New("Condition").Funcs(sprig.TxtFuncMap()).Option("missingkey=error").Delims(stepTemplateLeftDelim, stepTemplateRightDelim).Parse(tmplStr)
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate condition %q: %w", expr, err)
+	}
+	result, err := executeTemplateWithData(tmpl, data)
+	if err != nil {
+		return false, fmt.Errorf("failed to evaluate condition %q: %w", expr, err)
+	}
+	return result == "true", nil
+}
+
+// rewriteConditionToken resolves a token matched by conditionTokenRegex.
+// It returns either the value that the token should be replaced
+// with or (if non-empty) the text that should replace it.
+func (c TTPExecutionContext) rewriteConditionToken(token string) (interface{}, string, error) {
+	if !strings.HasPrefix(token, "\"") && !strings.HasPrefix(token, "`") {
+		if strings.HasPrefix(token, "$$") {
+			return nil, strings.TrimPrefix(token, "$"), nil
+		}
+		val, err := c.resolveReference(token)
+		if err == nil {
+			val, err = literalValue(val)
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid variable expression %v: %w", token, err)
+		}
+		return val, "", nil
+	}
+
+	literal, err := strconv.Unquote(token)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid string literal %v: %w", token, err)
+	}
+	if !strings.Contains(literal, contextVariablePrefix) {
+		return nil, token, nil
+	}
+	if conditionRefRegex.MatchString(literal) && !strings.HasPrefix(literal, "$$") {
+		val, err := c.processMatch(literal)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid variable expression %v: %w", literal, err)
+		}
+		return val, "", nil
+	}
+	expanded, err := c.ExpandVariables([]string{literal})
+	if err != nil {
+		return nil, "", err
+	}
+	return nil, strconv.Quote(expanded[0]), nil
+}
+
+// resolveReference returns the value of a step result reference - unlike
+// processMatch, typed outputs keep their type rather than being formatted
+func (c TTPExecutionContext) resolveReference(ref string) (interface{}, error) {
+	tokens := strings.Split(strings.TrimPrefix(ref, contextVariablePrefix), ".")
+	if len(tokens) >= 4 && tokens[0] == "steps" && tokens[2] == "outputs" {
+		if _, err := c.processMatch(ref); err != nil {
+			return nil, err
+		}
+		val := c.StepResults.ByName[tokens[1]].Outputs[tokens[3]]
+		return lookupPath(val, tokens[4:])
+	}
+	return c.processMatch(ref)
+}
+
+// literalValue converts the value of a reference that stands on its
+// own in a condition into the number or boolean that its text spells
+// (as when the text was part of the expression) - other scalars become
+// strings, while lists and maps are passed through unchanged
+func literalValue(val interface{}) (interface{}, error) {
+	switch val.(type) {
+	case map[string]interface{}, []interface{}:
+		return val, nil
+	}
+	s, err := formatValue(val)
+	if err != nil {
+		return nil, err
+	}
+	if i, err := strconv.Atoi(s); err == nil {
+		return i, nil
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return f, nil
+	}
+	if s == "true" || s == "false" {
+		return s == "true", nil
+	}
+	return s, nil
+}
+
 func (c TTPExecutionContext) containsStepTemplating(input string) bool {
 	return strings.Contains(input, stepTemplateLeftDelim)
 }
@@ -160,15 +423,19 @@ func (c TTPExecutionContext) processStepsVariable(path string) (string, error) {
 		}
 		return stepResult.Stdout, nil
 	case "outputs":
-		if len(tokens) != 3 {
-			return "", fmt.Errorf("step output reference %v should be exactly one level deep (e.g. steps.foo.outputs.bar)", "steps."+path)
+		if len(tokens) < 3 {
+			return "", fmt.Errorf("step output reference %v should name an output (e.g. steps.foo.outputs.bar)", "steps."+path)
 		}
 		key := tokens[2]
 		val, ok := stepResult.Outputs[key]
 		if !ok {
 			return "", fmt.Errorf("key %v not found in output of step %v", key, stepName)
 		}
-		return val, nil
+		val, err := lookupPath(val, tokens[3:])
+		if err != nil {
+			return "", fmt.Errorf("invalid reference to output %v of step %v: %w", key, stepName, err)
+		}
+		return formatValue(val)
 	}
 	return "", fmt.Errorf("invalid step result field selector: %v", fieldSelector)
 }
@@ -196,3 +463,49 @@ func (c TTPExecutionContext) processMatch(match string) (string, error) {
 	}
 	return "", fmt.Errorf("invalid variable prefix: %v", prefix)
 }
+
+// lookupPath walks a path of map keys and list
+// indexes into a (possibly nested) output value
+func lookupPath(val interface{}, path []string) (interface{}, error) {
+	for idx, token := range path {
+		switch v := val.(type) {
+		case map[string]interface{}:
+			elem, ok := v[token]
+			if !ok {
+				return nil, fmt.Errorf("key %v not found", strings.Join(path[:idx+1], "."))
+			}
+			val = elem
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, fmt.Errorf("invalid list index %v (list has %d elements)", strings.Join(path[:idx+1], "."), len(v))
+			}
+			val = v[i]
+		default:
+			return nil, fmt.Errorf("%v is not a list or map", strings.Join(append([]string{"value"}, path[:idx]...), "."))
+		}
+	}
+	return val, nil
+}
+
+// formatValue converts an output value into the string
+// that replaces a reference to it - lists and maps
+// are formatted as JSON
+func formatValue(val interface{}) (string, error) {
+	switch v := val.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case map[string]interface{}, []interface{}:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
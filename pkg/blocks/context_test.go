@@ -42,8 +42,13 @@ func TestExpandVariablesStepResults(t *testing.T) {
 	stepResults.ByName["third_step"] = &ExecutionResult{
 		ActResult: ActResult{
 			Stdout: `{"foo":{"bar":"baz"}}`,
-			Outputs: map[string]string{
+			Outputs: map[string]interface{}{
 				"myresult": "baz",
+				"count":    float64(3),
+				"hosts": []interface{}{
+					map[string]interface{}{"ip": "10.0.0.1", "ports": []interface{}{22, 80}},
+					map[string]interface{}{"ip": "10.0.0.2"},
+				},
 			},
 		},
 	}
@@ -83,6 +88,44 @@ func TestExpandVariablesStepResults(t *testing.T) {
 			},
 			wantError: false,
 		},
+		{
+			name: "Step Output Expansion - Nested Path",
+			stringsToExpand: []string{
+				"ip: $forge.steps.third_step.outputs.hosts.1.ip",
+				"port: $forge.steps.third_step.outputs.hosts.0.ports.1",
+				"count: $forge.steps.third_step.outputs.count",
+			},
+			expectedResult: []string{
+				"ip: 10.0.0.2",
+				"port: 80",
+				"count: 3",
+			},
+			wantError: false,
+		},
+		{
+			name: "Step Output Expansion - List As JSON",
+			stringsToExpand: []string{
+				"ports: $forge.steps.third_step.outputs.hosts.0.ports",
+			},
+			expectedResult: []string{
+				"ports: [22,80]",
+			},
+			wantError: false,
+		},
+		{
+			name: "Invalid List Index",
+			stringsToExpand: []string{
+				"should fail: $forge.steps.third_step.outputs.hosts.2.ip",
+			},
+			wantError: true,
+		},
+		{
+			name: "Path Into Scalar Output",
+			stringsToExpand: []string{
+				"should fail: $forge.steps.third_step.outputs.myresult.foo",
+			},
+			wantError: true,
+		},
 		{
 			name: "Escape forge magic string",
 			stringsToExpand: []string{
@@ -152,14 +195,14 @@ func TestTemplateStep(t *testing.T) {
 	testCases := []struct {
 		name             string
 		stringToTemplate string
-		stepVars         map[string]string
+		stepVars         map[string]interface{}
 		expectedResult   string
 		wantError        bool
 	}{
 		{
 			name:             "Template basic string",
 			stringToTemplate: "this is {[{.StepVars.foo}]}",
-			stepVars: map[string]string{
+			stepVars: map[string]interface{}{
 				"foo": "templated",
 			},
 			expectedResult: "this is templated",
@@ -168,7 +211,7 @@ func TestTemplateStep(t *testing.T) {
 		{
 			name:             "Doesn't template original delimiters",
 			stringToTemplate: "this is {{.StepVars.foo}}",
-			stepVars: map[string]string{
+			stepVars: map[string]interface{}{
 				"foo": "templated",
 			},
 			expectedResult: "this is {{.StepVars.foo}}",
@@ -177,10 +220,26 @@ func TestTemplateStep(t *testing.T) {
 		{
 			name:             "Errors on missing variable",
 			stringToTemplate: "this is {[{.StepVars.foo}]}",
-			stepVars:         map[string]string{},
+			stepVars:         map[string]interface{}{},
 			expectedResult:   "",
 			wantError:        true,
 		},
+		{
+			name:             "Iterates over list variable",
+			stringToTemplate: "{[{range .StepVars.pids}]}kill {[{.}]};{[{end}]}",
+			stepVars: map[string]interface{}{
+				"pids": []interface{}{1, 2},
+			},
+			expectedResult: "kill 1;kill 2;",
+			wantError:      false,
+		},
+		{
+			name:             "Iterates over step outputs",
+			stringToTemplate: "{[{range .Steps.scan.Outputs.hosts}]}{[{.ip}]} {[{end}]}",
+			stepVars:         map[string]interface{}{},
+			expectedResult:   "10.0.0.1 10.0.0.2 ",
+			wantError:        false,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -188,6 +247,16 @@ func TestTemplateStep(t *testing.T) {
 			// Build execution context
 			execCtx := NewTTPExecutionContext()
 			execCtx.Vars.StepVars = tc.stepVars
+			execCtx.StepResults.ByName["scan"] = &ExecutionResult{
+				ActResult: ActResult{
+					Outputs: map[string]interface{}{
+						"hosts": []interface{}{
+							map[string]interface{}{"ip": "10.0.0.1"},
+							map[string]interface{}{"ip": "10.0.0.2"},
+						},
+					},
+				},
+			}
 
 			// test templating
 			result, err := execCtx.templateStep(tc.stringToTemplate)
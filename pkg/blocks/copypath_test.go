@@ -47,7 +47,7 @@ func TestCopyPathExecute(t *testing.T) {
 		relativeDestination string
 		overwrite           bool
 		recursive           bool
-		stepVars            map[string]string
+		stepVars            map[string]interface{}
 		expectTemplateError bool
 		expectExecuteError  bool
 	}{
@@ -56,7 +56,7 @@ func TestCopyPathExecute(t *testing.T) {
 			description:         "Expected to fail due to non-existent source file",
 			relativeSource:      "thisfiledoesnotexistok",
 			relativeDestination: "thisdoesntmatter",
-			stepVars:            map[string]string{},
+			stepVars:            map[string]interface{}{},
 			expectTemplateError: false,
 			expectExecuteError:  true,
 		},
@@ -65,7 +65,7 @@ func TestCopyPathExecute(t *testing.T) {
 			description:         "This should succeed as source file exists and destination does not",
 			relativeSource:      filepath.Join(sourceDirectory, "ttpforge_test.txt"),
 			relativeDestination: filepath.Join(destinationDirectory, "ttpforge_test_copy.txt"),
-			stepVars:            map[string]string{},
+			stepVars:            map[string]interface{}{},
 			expectTemplateError: false,
 		},
 		{
@@ -73,7 +73,7 @@ func TestCopyPathExecute(t *testing.T) {
 			description:         "This should fail since the destination file exists and we are not specifying to overwrite it",
 			relativeSource:      filepath.Join(sourceDirectory, "ttpforge_test.txt"),
 			relativeDestination: filepath.Join(destinationDirectory, "ttpforge_test.txt"),
-			stepVars:            map[string]string{},
+			stepVars:            map[string]interface{}{},
 			expectTemplateError: false,
 			expectExecuteError:  true,
 		},
@@ -82,7 +82,7 @@ func TestCopyPathExecute(t *testing.T) {
 			description:         "This should pass since when destination file exists since we are specifying overwrite true",
 			relativeSource:      filepath.Join(sourceDirectory, "ttpforge_test.txt"),
 			relativeDestination: filepath.Join(destinationDirectory, "ttpforge_test.txt"),
-			stepVars:            map[string]string{},
+			stepVars:            map[string]interface{}{},
 			expectTemplateError: false,
 			overwrite:           true,
 		},
@@ -92,7 +92,7 @@ func TestCopyPathExecute(t *testing.T) {
 			relativeSource:      sourceDirectory,
 			relativeDestination: destinationDirectory,
 			recursive:           true,
-			stepVars:            map[string]string{},
+			stepVars:            map[string]interface{}{},
 			expectTemplateError: false,
 			expectExecuteError:  true,
 		},
@@ -102,7 +102,7 @@ func TestCopyPathExecute(t *testing.T) {
 			relativeSource:      sourceDirectory,
 			relativeDestination: destinationDirectory,
 			recursive:           true,
-			stepVars:            map[string]string{},
+			stepVars:            map[string]interface{}{},
 			expectTemplateError: false,
 			overwrite:           true,
 		},
@@ -111,7 +111,7 @@ func TestCopyPathExecute(t *testing.T) {
 			description:         "This should pass when the source and destination paths are templated",
 			relativeSource:      filepath.Join(sourceDirectory, "{[{.StepVars.foo}]}_test.txt"),
 			relativeDestination: filepath.Join(destinationDirectory, "{[{.StepVars.foo}]}_test_copy.txt"),
-			stepVars: map[string]string{
+			stepVars: map[string]interface{}{
 				"foo": "ttpforge",
 			},
 			expectTemplateError: false,
@@ -121,7 +121,7 @@ func TestCopyPathExecute(t *testing.T) {
 			description:         "This should fail when the foo variable is missing",
 			relativeSource:      filepath.Join(sourceDirectory, "{[{.StepVars.foo}]}_test.txt"),
 			relativeDestination: filepath.Join(destinationDirectory, "{[{.StepVars.foo}]}_test_copy.txt"),
-			stepVars:            map[string]string{},
+			stepVars:            map[string]interface{}{},
 			expectTemplateError: true,
 		},
 	}
@@ -41,7 +41,7 @@ func TestCreateFileExecute(t *testing.T) {
 		description         string
 		step                *CreateFileStep
 		fsysContents        map[string][]byte
-		stepVars            map[string]string
+		stepVars            map[string]interface{}
 		expectTemplateError bool
 		expectExecuteError  bool
 	}{
@@ -112,7 +112,7 @@ func TestCreateFileExecute(t *testing.T) {
 				Path:     "{[{.StepVars.name}]}.txt",
 				Contents: "{[{.StepVars.contents}]}",
 			},
-			stepVars: map[string]string{
+			stepVars: map[string]interface{}{
 				"name":     "file-name",
 				"contents": "hello world",
 			},
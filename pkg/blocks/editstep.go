@@ -28,11 +28,13 @@ import (
 
 // Edit represents a single old+new find-and-replace pair
 type Edit struct {
-	Old    string `yaml:"old,omitempty"`
-	New    string `yaml:"new,omitempty"`
-	Append string `yaml:"append,omitempty"`
-	Delete string `yaml:"delete,omitempty"`
-	Regexp bool   `yaml:"regexp,omitempty"`
+	// Description documents the purpose of the edit
+	Description string `yaml:"description,omitempty"`
+	Old         string `yaml:"old,omitempty"`
+	New         string `yaml:"new,omitempty"`
+	Append      string `yaml:"append,omitempty"`
+	Delete      string `yaml:"delete,omitempty"`
+	Regexp      bool   `yaml:"regexp,omitempty"`
 
 	oldRegexp *regexp.Regexp
 }
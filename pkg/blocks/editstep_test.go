@@ -41,7 +41,7 @@ func TestEditStep(t *testing.T) {
 		expectedContentsAfterEdit string
 		expectedErrTxt            string
 		fsysContents              map[string][]byte
-		stepVars                  map[string]string
+		stepVars                  map[string]interface{}
 	}{
 		{
 			name: "Test Unmarshal Edit Valid",
@@ -270,7 +270,7 @@ edits:
   - old: another
     new: one`,
 			fsysContents:              map[string][]byte{"/tmp/test": []byte("foo\nanother")},
-			stepVars:                  map[string]string{"filename": "test"},
+			stepVars:                  map[string]interface{}{"filename": "test"},
 			expectedContentsAfterEdit: "yolo\none",
 		},
 		{
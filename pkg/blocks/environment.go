@@ -0,0 +1,252 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package blocks
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+)
+
+// InheritEnv controls which variables of the environment of TTPForge
+// (or, for sub-TTPs, of the parent TTP) are passed on to steps. It is
+// written in YAML as `all` (the default), `none` or a list of variable
+// names, which may be glob patterns such as `LC_*`.
+type InheritEnv struct {
+	// Restricted is set if only the variables
+	// in Allowlist are inherited
+	Restricted bool
+	Allowlist  []string
+}
+
+// UnmarshalYAML decodes `all`, `none` or a list of variable names
+func (ie *InheritEnv) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		switch node.Value {
+		case "all":
+			*ie = InheritEnv{}
+		case "none":
+			*ie = InheritEnv{Restricted: true}
+		default:
+			return fmt.Errorf("invalid inherit_env value %q - must be all, none or a list of variable names", node.Value)
+		}
+		return nil
+	}
+	var allowlist []string
+	if err := node.Decode(&allowlist); err != nil {
+		return fmt.Errorf("inherit_env must be all, none or a list of variable names: %w", err)
+	}
+	for _, pattern := range allowlist {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid inherit_env pattern %q: %w", pattern, err)
+		}
+	}
+	*ie = InheritEnv{Restricted: true, Allowlist: allowlist}
+	return nil
+}
+
+// MarshalYAML encodes the setting as `all`, `none` or a list of names
+func (ie InheritEnv) MarshalYAML() (interface{}, error) {
+	switch {
+	case !ie.Restricted:
+		return "all", nil
+	case len(ie.Allowlist) == 0:
+		return "none", nil
+	default:
+		return ie.Allowlist, nil
+	}
+}
+
+// inherits returns true if the variable with the given name is inherited
+func (ie InheritEnv) inherits(name string) bool {
+	if !ie.Restricted {
+		return true
+	}
+	for _, pattern := range ie.Allowlist {
+		if matched, _ := filepath.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// buildEnvironment computes the environment of the steps of a TTP.
+// From lowest to highest precedence, it consists of:
+//
+// 1. the inherited variables (filtered according to inherit_env)
+// 2. the variables set in the env_file (if any)
+// 3. the variables set in the top-level env of the TTP
+//
+// The env of each step takes precedence over all of these.
+//
+// **Parameters:**
+//
+// inherited: the environment to inherit - nil means that of TTPForge
+// fsys: the filesystem containing the env_file
+// ttpDir: the directory of the TTP, relative to which the env_file is resolved
+//
+// **Returns:**
+//
+// map[string]string: the environment of the steps
+// error: an error if the env_file cannot be read
+func (t *TTP) buildEnvironment(inherited map[string]string, fsys afero.Fs, ttpDir string) (map[string]string, error) {
+	if inherited == nil {
+		inherited = environToMap(os.Environ())
+	}
+	env := make(map[string]string)
+	for name, value := range inherited {
+		if t.InheritEnv.inherits(name) {
+			env[name] = value
+		}
+	}
+
+	if t.EnvFile != "" {
+		envFilePath := t.EnvFile
+		if !filepath.IsAbs(envFilePath) {
+			envFilePath = filepath.Join(ttpDir, envFilePath)
+		}
+		contents, err := afero.ReadFile(fsys, envFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read env_file: %w", err)
+		}
+		fileEnv, err := parseEnvFile(contents)
+		if err != nil {
+			return nil, fmt.Errorf("invalid env_file %v: %w", t.EnvFile, err)
+		}
+		for name, value := range fileEnv {
+			env[name] = value
+		}
+	}
+
+	for name, value := range t.Environment {
+		env[name] = value
+	}
+	return env, nil
+}
+
+// envFileLineRegexp matches the variable assignments of env files
+var envFileLineRegexp = regexp.MustCompile(`^(?:export\s+)?([A-Za-z_][A-Za-z0-9_]*)\s*=\s*(.*)$`)
+
+// parseEnvFile parses the contents of a .env file: each line holds a
+// `NAME=value` assignment (optionally prefixed with `export`), a comment
+// starting with # or nothing. Values may be enclosed in single quotes
+// (taken literally) or double quotes (in which escape sequences such as
+// \n are interpreted) - unquoted values end at the first ` #`.
+func parseEnvFile(contents []byte) (map[string]string, error) {
+	env := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		match := envFileLineRegexp.FindStringSubmatch(line)
+		if match == nil {
+			return nil, fmt.Errorf("line %d: expected NAME=value", lineNum)
+		}
+		value, err := parseEnvFileValue(match[2])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		env[match[1]] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return env, nil
+}
+
+// parseEnvFileValue parses the value of an assignment in a .env file
+func parseEnvFileValue(raw string) (string, error) {
+	switch {
+	case strings.HasPrefix(raw, "'"):
+		end := strings.Index(raw[1:], "'")
+		if end == -1 {
+			return "", fmt.Errorf("unterminated single-quoted value")
+		}
+		return raw[1 : end+1], nil
+	case strings.HasPrefix(raw, `"`):
+		value, err := strconv.QuotedPrefix(raw)
+		if err != nil {
+			return "", fmt.Errorf("invalid double-quoted value: %w", err)
+		}
+		return strconv.Unquote(value)
+	default:
+		if idx := strings.Index(raw, " #"); idx != -1 {
+			raw = raw[:idx]
+		}
+		return strings.TrimSpace(raw), nil
+	}
+}
+
+// environToMap converts an environment in
+// "NAME=value" format (as returned by os.Environ) to a map
+func environToMap(environ []string) map[string]string {
+	env := make(map[string]string, len(environ))
+	for _, kv := range environ {
+		if name, value, ok := strings.Cut(kv, "="); ok {
+			env[name] = value
+		}
+	}
+	return env
+}
+
+// environ returns the environment of a command run by a step: the
+// environment of the TTP (see buildEnvironment) overridden by the
+// env of the step. The variables are sorted by name.
+//
+// **Parameters:**
+//
+// stepEnv: the env of the step
+//
+// **Returns:**
+//
+// []string: the environment in "NAME=value" format
+func (c TTPExecutionContext) environ(stepEnv map[string]string) []string {
+	env := c.Cfg.Environment
+	if env == nil {
+		env = environToMap(os.Environ())
+	}
+	merged := make(map[string]string, len(env)+len(stepEnv))
+	for name, value := range env {
+		merged[name] = value
+	}
+	for name, value := range stepEnv {
+		merged[name] = value
+	}
+	// an empty (rather than nil) environment must be returned
+	// when no variables are set, as exec.Cmd treats a nil
+	// environment as the environment of TTPForge
+	environ := make([]string, 0, len(merged))
+	environ = append(environ, FetchEnv(merged)...)
+	sort.Strings(environ)
+	return environ
+}
@@ -0,0 +1,300 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package blocks
+
+import (
+	"testing"
+
+	"github.com/facebookincubator/ttpforge/pkg/repos"
+	"github.com/facebookincubator/ttpforge/pkg/testutils"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestInheritEnvUnmarshal(t *testing.T) {
+	testCases := []struct {
+		name               string
+		content            string
+		expectedInheritEnv InheritEnv
+		wantError          bool
+	}{
+		{
+			name:               "Default",
+			content:            `name: test`,
+			expectedInheritEnv: InheritEnv{},
+		},
+		{
+			name:               "All",
+			content:            `inherit_env: all`,
+			expectedInheritEnv: InheritEnv{},
+		},
+		{
+			name:               "None",
+			content:            `inherit_env: none`,
+			expectedInheritEnv: InheritEnv{Restricted: true},
+		},
+		{
+			name:               "Allowlist",
+			content:            `inherit_env: [PATH, "LC_*"]`,
+			expectedInheritEnv: InheritEnv{Restricted: true, Allowlist: []string{"PATH", "LC_*"}},
+		},
+		{
+			name:      "Invalid String",
+			content:   `inherit_env: some`,
+			wantError: true,
+		},
+		{
+			name:      "Invalid Pattern",
+			content:   `inherit_env: ["["]`,
+			wantError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var container struct {
+				InheritEnv InheritEnv `yaml:"inherit_env"`
+			}
+			err := yaml.Unmarshal([]byte(tc.content), &container)
+			if tc.wantError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedInheritEnv, container.InheritEnv)
+
+			// the setting must survive a round trip
+			marshaled, err := yaml.Marshal(container)
+			require.NoError(t, err)
+			var roundTrip struct {
+				InheritEnv InheritEnv `yaml:"inherit_env"`
+			}
+			require.NoError(t, yaml.Unmarshal(marshaled, &roundTrip))
+			assert.Equal(t, tc.expectedInheritEnv, roundTrip.InheritEnv)
+		})
+	}
+}
+
+func TestParseEnvFile(t *testing.T) {
+	testCases := []struct {
+		name        string
+		content     string
+		expectedEnv map[string]string
+		wantError   bool
+	}{
+		{
+			name: "Assignments And Comments",
+			content: `# a comment
+PLAIN=value
+export EXPORTED=yes
+
+SPACED = trimmed   # trailing comment
+EMPTY=
+`,
+			expectedEnv: map[string]string{
+				"PLAIN":    "value",
+				"EXPORTED": "yes",
+				"SPACED":   "trimmed",
+				"EMPTY":    "",
+			},
+		},
+		{
+			name: "Quoted Values",
+			content: `SINGLE='literal $HOME \n # not a comment'
+DOUBLE="line one\nline \"two\"" # comment`,
+			expectedEnv: map[string]string{
+				"SINGLE": `literal $HOME \n # not a comment`,
+				"DOUBLE": "line one\nline \"two\"",
+			},
+		},
+		{
+			name:      "Missing Equals Sign",
+			content:   "NAME",
+			wantError: true,
+		},
+		{
+			name:      "Invalid Name",
+			content:   "1NAME=value",
+			wantError: true,
+		},
+		{
+			name:      "Unterminated Quote",
+			content:   `NAME="value`,
+			wantError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			env, err := parseEnvFile([]byte(tc.content))
+			if tc.wantError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedEnv, env)
+		})
+	}
+}
+
+func TestTTPEnvironment(t *testing.T) {
+	// the environment of TTPForge must never leak into
+	// steps when an inherited environment is provided
+	t.Setenv("INHERITED", "leaked")
+	inherited := map[string]string{
+		"INHERITED": "inherited",
+		"LC_ALL":    "C",
+		"OVERRIDE":  "inherited",
+	}
+	testCases := []struct {
+		name           string
+		content        string
+		envFile        string
+		inherited      map[string]string
+		expectedStdout string
+		wantError      bool
+	}{
+		{
+			name: "Precedence",
+			content: `name: test
+env_file: test.env
+env:
+  OVERRIDE: ttp
+  TTP_ONLY: ttp
+steps:
+  - name: step1
+    inline: echo "$INHERITED $FILE_ONLY $TTP_ONLY $OVERRIDE"
+  - name: step2
+    inline: echo "$OVERRIDE"
+    env:
+      OVERRIDE: step`,
+			envFile:        "FILE_ONLY=file\nOVERRIDE=file\n",
+			inherited:      inherited,
+			expectedStdout: "inherited file ttp ttp\nstep\n",
+		},
+		{
+			name: "Inherit None",
+			content: `name: test
+inherit_env: none
+env:
+  TTP_ONLY: ttp
+steps:
+  - name: step1
+    inline: echo "[$INHERITED] [$LC_ALL] $TTP_ONLY"`,
+			inherited:      inherited,
+			expectedStdout: "[] [] ttp\n",
+		},
+		{
+			name: "Empty Environment",
+			content: `name: test
+inherit_env: none
+steps:
+  - name: step1
+    inline: echo "[$INHERITED]"`,
+			inherited:      inherited,
+			expectedStdout: "[]\n",
+		},
+		{
+			name: "Inherit Allowlist",
+			content: `name: test
+inherit_env: ["LC_*"]
+steps:
+  - name: step1
+    inline: echo "[$INHERITED] [$LC_ALL]"`,
+			inherited:      inherited,
+			expectedStdout: "[] [C]\n",
+		},
+		{
+			name: "Missing Env File",
+			content: `name: test
+env_file: missing.env
+steps:
+  - name: step1
+    inline: echo hello`,
+			wantError: true,
+		},
+		{
+			name: "Invalid Env File",
+			content: `name: test
+env_file: test.env
+steps:
+  - name: step1
+    inline: echo hello`,
+			envFile:   "not an assignment",
+			wantError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fsys := afero.NewMemMapFs()
+			require.NoError(t, afero.WriteFile(fsys, "/ttps/test.yaml", []byte(tc.content), 0644))
+			if tc.envFile != "" {
+				require.NoError(t, afero.WriteFile(fsys, "/ttps/test.env", []byte(tc.envFile), 0644))
+			}
+
+			execCfg := TTPExecutionConfig{Environment: tc.inherited}
+			ttp, execCtx, err := LoadTTP("/ttps/test.yaml", fsys, &execCfg, map[string]interface{}{}, nil)
+			if tc.wantError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, ttp.Execute(*execCtx))
+
+			var stdout string
+			for _, result := range execCtx.StepResults.ByIndex {
+				stdout += result.Stdout
+			}
+			assert.Equal(t, tc.expectedStdout, stdout)
+		})
+	}
+}
+
+func TestSubTTPInheritsEnvironment(t *testing.T) {
+	fsys, err := testutils.MakeAferoTestFs(map[string][]byte{
+		"repos/a/" + repos.RepoConfigFileName: []byte(`ttp_search_paths: ["ttps"]`),
+		"repos/a/ttps/parent.yaml": []byte(`name: parent
+env:
+  FROM_PARENT: parent
+  OVERRIDE: parent
+steps:
+  - name: child
+    ttp: child.yaml`),
+		"repos/a/ttps/child.yaml": []byte(`name: child
+env:
+  OVERRIDE: child
+steps:
+  - name: step1
+    inline: echo "$FROM_PARENT $OVERRIDE"`),
+	})
+	require.NoError(t, err)
+	spec := repos.Spec{Name: "default", Path: "repos/a"}
+	repo, err := spec.Load(fsys, "")
+	require.NoError(t, err)
+
+	execCfg := TTPExecutionConfig{Repo: repo, Environment: map[string]string{}}
+	ttp, execCtx, err := LoadTTP("repos/a/ttps/parent.yaml", fsys, &execCfg, map[string]interface{}{}, nil)
+	require.NoError(t, err)
+	require.NoError(t, ttp.Execute(*execCtx))
+	assert.Equal(t, "parent child\n", execCtx.StepResults.ByName["child"].Stdout)
+}
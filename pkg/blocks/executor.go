@@ -22,7 +22,6 @@ package blocks
 import (
 	"context"
 	"fmt"
-	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
@@ -87,6 +86,18 @@ func (e *ScriptExecutor) buildCommand(ctx context.Context) *exec.Cmd {
 
 // Execute runs the command
 func (e *ScriptExecutor) Execute(ctx context.Context, execCtx TTPExecutionContext) (*ActResult, error) {
+	cmd, err := e.prepareCommand(ctx, execCtx)
+	if err != nil {
+		return nil, err
+	}
+	killProcessGroupOnCancel(cmd)
+
+	return streamAndCapture(cmd, execCtx.Cfg.Stdout, execCtx.Cfg.Stderr)
+}
+
+// prepareCommand builds the command that runs the script, with
+// the variables in the script and its environment expanded
+func (e *ScriptExecutor) prepareCommand(ctx context.Context, execCtx TTPExecutionContext) (*exec.Cmd, error) {
 	// expand variables in command
 	expandedInlines, err := execCtx.ExpandVariables([]string{e.Inline})
 	if err != nil {
@@ -100,7 +111,7 @@ func (e *ScriptExecutor) Execute(ctx context.Context, execCtx TTPExecutionContex
 	}
 
 	// expand variables in environment
-	envAsList := append(FetchEnv(e.Environment), os.Environ()...)
+	envAsList := execCtx.environ(e.Environment)
 	expandedEnvAsList, err := execCtx.ExpandVariables(envAsList)
 	if err != nil {
 		return nil, err
@@ -110,8 +121,7 @@ func (e *ScriptExecutor) Execute(ctx context.Context, execCtx TTPExecutionContex
 	cmd.Env = expandedEnvAsList
 	cmd.Dir = execCtx.Vars.WorkDir
 	cmd.Stdin = strings.NewReader(body)
-
-	return streamAndCapture(*cmd, execCtx.Cfg.Stdout, execCtx.Cfg.Stderr)
+	return cmd, nil
 }
 
 // Execute runs the binary with arguments
@@ -123,7 +133,7 @@ func (e *FileExecutor) Execute(ctx context.Context, execCtx TTPExecutionContext)
 	}
 
 	// expand variables in environment
-	envAsList := append(FetchEnv(e.Environment), os.Environ()...)
+	envAsList := execCtx.environ(e.Environment)
 	expandedEnvAsList, err := execCtx.ExpandVariables(envAsList)
 	if err != nil {
 		return nil, err
@@ -139,7 +149,8 @@ func (e *FileExecutor) Execute(ctx context.Context, execCtx TTPExecutionContext)
 
 	cmd.Env = expandedEnvAsList
 	cmd.Dir = execCtx.Vars.WorkDir
-	return streamAndCapture(*cmd, execCtx.Cfg.Stdout, execCtx.Cfg.Stderr)
+	killProcessGroupOnCancel(cmd)
+	return streamAndCapture(cmd, execCtx.Cfg.Stdout, execCtx.Cfg.Stderr)
 }
 
 // InferExecutor infers the executor based on the file extension and
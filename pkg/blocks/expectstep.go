@@ -25,20 +25,26 @@ import (
 	"os"
 	"os/exec"
 	"regexp"
+	"strconv"
 	"time"
 
 	"github.com/Netflix/go-expect"
 	"github.com/facebookincubator/ttpforge/pkg/logging"
 	"github.com/facebookincubator/ttpforge/pkg/outputs"
+	"gopkg.in/yaml.v3"
 )
 
+// defaultPromptTimeout is how long expect steps
+// wait for each prompt when they have no prompt_timeout
+const defaultPromptTimeout = 120 * time.Second
+
 // ExpectStep represents an expect command.
 //
 // **Attributes:**
 //
 // Chdir: Directory to change to before executing the command.
 // Responses: List of expected prompts and responses.
-// Timeout: Timeout duration for the expect command.
+// PromptTimeout: How long to wait for each prompt.
 // Executor: Shell to use for executing the command.
 // Environment: Environment variables for the command.
 // Inline: Inline script to execute.
@@ -47,7 +53,7 @@ import (
 type ExpectStep struct {
 	actionDefaults `yaml:",inline"`
 	Chdir          string                  `yaml:"chdir,omitempty"`
-	Timeout        int                     `yaml:"timeout,omitempty"`
+	PromptTimeout  Timeout                 `yaml:"prompt_timeout,omitempty"`
 	Executor       string                  `yaml:"executor,omitempty"`
 	Expect         *ExpectSpec             `yaml:"expect,omitempty"`
 	Environment    map[string]string       `yaml:"env,omitempty"`
@@ -85,6 +91,53 @@ func NewExpectStep() *ExpectStep {
 	return &ExpectStep{}
 }
 
+// UnmarshalYAML decodes an expect step. For backward compatibility,
+// a `timeout:` given as a bare number of seconds is the timeout of
+// each prompt (unless prompt_timeout is also specified).
+func (s *ExpectStep) UnmarshalYAML(node *yaml.Node) error {
+	// use of this auxiliary type prevents infinite recursion
+	type rawExpectStep ExpectStep
+	var raw rawExpectStep
+	if err := node.Decode(&raw); err != nil {
+		return err
+	}
+	*s = ExpectStep(raw)
+	if legacy, ok := legacyPromptTimeout(node); ok && s.PromptTimeout == 0 {
+		s.PromptTimeout = legacy
+	}
+	return nil
+}
+
+// legacyPromptTimeout returns the `timeout:` of an expect
+// step if it is a bare number of seconds, which expect steps
+// used as their prompt timeout before step timeouts existed
+//
+// **Parameters:**
+//
+// node: the YAML node of the step
+//
+// **Returns:**
+//
+// Timeout: the prompt timeout
+// bool: true if the step has a legacy timeout
+func legacyPromptTimeout(node *yaml.Node) (Timeout, bool) {
+	if node.Kind != yaml.MappingNode {
+		return 0, false
+	}
+	for idx := 0; idx+1 < len(node.Content); idx += 2 {
+		key, value := node.Content[idx], node.Content[idx+1]
+		if key.Value != "timeout" || value.Kind != yaml.ScalarNode || value.ShortTag() != "!!int" {
+			continue
+		}
+		seconds, err := strconv.Atoi(value.Value)
+		if err != nil {
+			return 0, false
+		}
+		return Timeout(time.Duration(seconds) * time.Second), true
+	}
+	return 0, false
+}
+
 // IsNil checks if the step is nil or empty and returns a boolean value.
 //
 // **Returns:**
@@ -114,6 +167,10 @@ func (s *ExpectStep) Validate(_ TTPExecutionContext) error {
 		return fmt.Errorf("responses must be provided")
 	}
 
+	if s.PromptTimeout < 0 {
+		return fmt.Errorf("prompt_timeout must not be negative")
+	}
+
 	if s.Expect.Inline == "" {
 		return fmt.Errorf("inline must be provided")
 	} else if s.Executor == "" {
@@ -200,16 +257,10 @@ func (s *ExpectStep) Execute(execCtx TTPExecutionContext) (*ActResult, error) {
 	}
 	defer console.Close()
 
-	if s.Environment != nil {
-		for k, v := range s.Environment {
-			if err := os.Setenv(k, v); err != nil {
-				return nil, fmt.Errorf("failed to set environment variable: %w", err)
-			}
-		}
-	}
-
-	envAsList := os.Environ()
-	cmd := s.prepareCommand(context.Background(), execCtx, envAsList, s.Expect.Inline)
+	envAsList := execCtx.environ(s.Environment)
+	ctx := execCtx.Context()
+	cmd := s.prepareCommand(ctx, execCtx, envAsList, s.Expect.Inline)
+	killProcessGroupOnCancel(cmd)
 	cmd.Stdin = console.Tty()
 	cmd.Stdout = console.Tty()
 	cmd.Stderr = console.Tty()
@@ -218,17 +269,17 @@ func (s *ExpectStep) Execute(execCtx TTPExecutionContext) (*ActResult, error) {
 		return nil, fmt.Errorf("failed to start command: %w", err)
 	}
 
+	promptTimeout := s.PromptTimeout.Duration()
+	if promptTimeout == 0 {
+		promptTimeout = defaultPromptTimeout
+	}
 	done := make(chan error, 1)
 	go func() {
 		defer close(done)
 		for _, response := range s.Expect.Responses {
 			logging.L().Debugf("Waiting for prompt: %s\n", response.Prompt)
 			re := regexp.MustCompile(response.Prompt)
-			timeout := 120 // Default timeout is 120 seconds
-			if s.Timeout > 0 {
-				timeout = s.Timeout // Use the provided timeout if it is greater than 0
-			}
-			matched, err := console.Expect(expect.Regexp(re), expect.WithTimeout(time.Duration(timeout)*time.Second))
+			matched, err := console.Expect(expect.Regexp(re), expect.WithTimeout(promptTimeout))
 			if err != nil {
 				done <- fmt.Errorf("failed to expect %q: %w", re, err)
 				return
@@ -255,13 +306,21 @@ func (s *ExpectStep) Execute(execCtx TTPExecutionContext) (*ActResult, error) {
 		done <- nil
 	}()
 
+	// the step timeout (if any) bounds the whole command -
+	// without one, the command is stopped after 120 seconds
+	var expired <-chan time.Time
+	if _, ok := ctx.Deadline(); !ok {
+		expired = time.After(120 * time.Second)
+	}
 	select {
 	case err := <-done:
 		if err != nil {
 			return nil, err
 		}
-	case <-time.After(120 * time.Second):
+	case <-expired:
 		return nil, fmt.Errorf("command timed out")
+	case <-ctx.Done():
+		return nil, fmt.Errorf("command cancelled: %w", ctx.Err())
 	}
 
 	if _, err := console.ExpectEOF(); err != nil {
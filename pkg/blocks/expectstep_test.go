@@ -326,7 +326,7 @@ func TestExpectStep(t *testing.T) {
 		wantTemplateError  bool
 		wantExecuteError   bool
 		expectedErrTxt     string
-		stepVars           map[string]string
+		stepVars           map[string]interface{}
 	}{
 		{
 			name: "Test ExpectStep Execute With Output",
@@ -396,7 +396,7 @@ steps:
         - prompt: "{[{.StepVars.prompt}]}"
           response: "{[{.StepVars.number}]}"
 `,
-			stepVars: map[string]string{
+			stepVars: map[string]interface{}{
 				"dir":    "/tmp",
 				"script": "interactive.py",
 				"prompt": "Enter a number:",
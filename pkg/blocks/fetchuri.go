@@ -20,19 +20,45 @@ THE SOFTWARE.
 package blocks
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"hash"
 	"io"
+	"io/fs"
+	"math/rand"
 	"net/http"
 	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/facebookincubator/ttpforge/pkg/logging"
 	"github.com/spf13/afero"
 	"go.uber.org/zap"
 )
 
+// Delays between the attempts at fetching a URI - the delay
+// doubles after each failed attempt, up to the maximum
+var (
+	fetchRetryDelay    = time.Second
+	fetchRetryMaxDelay = 30 * time.Second
+)
+
+// sha256Regexp matches SHA256 checksums in hexadecimal
+var sha256Regexp = regexp.MustCompile(`^[0-9a-fA-F]{64}$`)
+
 // FetchURIStep represents a step in a process that consists of a main action,
 // a cleanup action, and additional metadata.
+//
+// Retries is the number of times a failed download (due to a network
+// error or a 5xx or 429 response) is retried. If SHA256 is set, the
+// download is verified against it and stored in (or, on subsequent
+// runs, read from) the download cache of TTPForge.
 type FetchURIStep struct {
 	actionDefaults `yaml:",inline"`
 	FetchURI       string   `yaml:"fetch_uri,omitempty"`
@@ -40,6 +66,7 @@ type FetchURIStep struct {
 	Location       string   `yaml:"location,omitempty"`
 	Proxy          string   `yaml:"proxy,omitempty"`
 	Overwrite      bool     `yaml:"overwrite,omitempty"`
+	SHA256         string   `yaml:"sha256,omitempty"`
 	FileSystem     afero.Fs `yaml:"-,omitempty"`
 }
 
@@ -89,6 +116,18 @@ func (f *FetchURIStep) Validate(execCtx TTPExecutionContext) error {
 		}
 	}
 
+	// Validate retries and checksum, if they don't contain templating
+	if !execCtx.containsStepTemplating(f.Retries) {
+		if _, err := f.retries(); err != nil {
+			return err
+		}
+	}
+	if !execCtx.containsStepTemplating(f.SHA256) {
+		if err := f.validateSHA256(); err != nil {
+			return err
+		}
+	}
+
 	// Retrieve the absolute path to the file, if location doesn't contain templating
 	if !execCtx.containsStepTemplating(f.Location) {
 		err := f.validateLocation(execCtx)
@@ -113,10 +152,26 @@ func (f *FetchURIStep) Template(execCtx TTPExecutionContext) error {
 		return err
 	}
 
-	// Template retries
-	f.Retries, err = execCtx.templateStep(f.Retries)
-	if err != nil {
-		return err
+	// Template and revalidate retries
+	if execCtx.containsStepTemplating(f.Retries) {
+		f.Retries, err = execCtx.templateStep(f.Retries)
+		if err != nil {
+			return err
+		}
+		if _, err := f.retries(); err != nil {
+			return err
+		}
+	}
+
+	// Template and revalidate checksum
+	if execCtx.containsStepTemplating(f.SHA256) {
+		f.SHA256, err = execCtx.templateStep(f.SHA256)
+		if err != nil {
+			return err
+		}
+		if err := f.validateSHA256(); err != nil {
+			return err
+		}
 	}
 
 	// Template and revalidate location
@@ -191,6 +246,81 @@ func (f *FetchURIStep) fetchURI(execCtx TTPExecutionContext) error {
 		return fmt.Errorf("location [%s] exists and overwrite is set to false. remove and retry", f.Location)
 	}
 
+	if err := f.download(execCtx, appFs, absLocal); err != nil {
+		return err
+	}
+
+	logging.L().Debugw("wrote contents of URI to specified location", "location", absLocal, "uri", f.FetchURI)
+
+	return nil
+}
+
+// download writes the contents of the URI - from the download cache
+// if possible - to the location, retrying failed requests. The contents
+// are streamed to a temporary file next to the location, which only
+// replaces the location once its checksum (if any) has been verified.
+func (f *FetchURIStep) download(execCtx TTPExecutionContext, fsys afero.Fs, location string) error {
+	tmpFile, err := createDownloadFile(fsys, location)
+	if err != nil {
+		return err
+	}
+	// renaming the file changes its name in some file
+	// systems (such as afero.MemMapFs), so it is recorded first
+	tmpPath := tmpFile.Name()
+	defer func() {
+		tmpFile.Close()
+		// the file no longer exists if the download succeeded
+		fsys.Remove(tmpPath)
+	}()
+
+	hash := sha256.New()
+	cachePath := f.cachePath(execCtx)
+	fromCache := cachePath != "" && f.copyFromCache(cachePath, tmpFile, hash)
+	if !fromCache {
+		if err := f.fetch(execCtx, tmpFile, hash); err != nil {
+			return err
+		}
+		if err := f.verify(hash); err != nil {
+			return fmt.Errorf("downloaded file %v failed verification: %w", f.FetchURI, err)
+		}
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+
+	if cachePath != "" && !fromCache {
+		// failing to cache the file only means that
+		// it will be downloaded again next time
+		if err := writeCacheFile(fsys, tmpPath, cachePath); err != nil {
+			logging.L().Warnf("Could not cache %v: %v", f.FetchURI, err)
+		}
+	}
+	return fsys.Rename(tmpPath, location)
+}
+
+// copyFromCache copies the cached copy of the URI to the download
+// file, returning false if there is no valid cached copy
+func (f *FetchURIStep) copyFromCache(cachePath string, dst afero.File, hash hash.Hash) bool {
+	cached, err := os.Open(cachePath)
+	if err != nil {
+		return false
+	}
+	defer cached.Close()
+	if _, err := io.Copy(io.MultiWriter(dst, hash), cached); err != nil {
+		logging.L().Warnf("Could not copy cached copy of %v: %v", f.FetchURI, err)
+		return false
+	}
+	if err := f.verify(hash); err != nil {
+		logging.L().Warnf("Ignoring corrupt cached copy of %v: %v", f.FetchURI, cachePath)
+		return false
+	}
+	logging.L().Infof("Using cached copy of %v: %v", f.FetchURI, cachePath)
+	return true
+}
+
+// fetch writes the contents of the URI to the download
+// file, retrying failed requests as specified by the step
+func (f *FetchURIStep) fetch(execCtx TTPExecutionContext, dst afero.File, hash hash.Hash) error {
 	client := http.DefaultClient
 	if f.Proxy != "" {
 		proxyURI, err := url.Parse(f.Proxy)
@@ -205,25 +335,173 @@ func (f *FetchURIStep) fetchURI(execCtx TTPExecutionContext) error {
 		client = &http.Client{Transport: tr}
 	}
 
-	resp, err := client.Get(f.FetchURI)
+	retries, err := f.retries()
 	if err != nil {
 		return err
 	}
+	policy := RetryPolicy{Attempts: retries + 1, Delay: Timeout(fetchRetryDelay), Backoff: 2}
+	for attempt := 1; ; attempt++ {
+		// discard anything written by an earlier attempt (or the cache)
+		if err := resetDownloadFile(dst, hash); err != nil {
+			return err
+		}
+		retryable, err := f.fetchOnce(execCtx, client, io.MultiWriter(dst, hash))
+		if err == nil {
+			return nil
+		}
+		if !retryable || attempt >= policy.Attempts {
+			if attempt > 1 {
+				return fmt.Errorf("%w (after %d attempts)", err, attempt)
+			}
+			return err
+		}
+		delay := min(policy.delayAfter(attempt), fetchRetryMaxDelay)
+		logging.L().Warnf("Attempt %d of %d to fetch %v failed: %v - retrying in %v", attempt, policy.Attempts, f.FetchURI, err, delay)
+		select {
+		case <-time.After(delay):
+		case <-execCtx.Context().Done():
+			return err
+		}
+	}
+}
+
+// fetchOnce makes a single request for the URI and streams the response
+// to w. It returns whether a failed request is worth retrying: network
+// errors, server errors (5xx) and rate limiting (429) are, other
+// responses and errors writing the response are not.
+func (f *FetchURIStep) fetchOnce(execCtx TTPExecutionContext, client *http.Client, w io.Writer) (bool, error) {
+	req, err := http.NewRequestWithContext(execCtx.Context(), http.MethodGet, f.FetchURI, nil)
+	if err != nil {
+		return false, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return execCtx.Context().Err() == nil, err
+	}
 	defer resp.Body.Close()
 
-	fHandle, err := appFs.Create(absLocal)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		retryable := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+		return retryable, fmt.Errorf("failed to fetch %v: server responded with %v", f.FetchURI, resp.Status)
+	}
+	dst := &writeErrRecorder{w: w}
+	if _, err := io.Copy(dst, resp.Body); err != nil {
+		if dst.err != nil {
+			return false, fmt.Errorf("failed to write contents of %v: %w", f.FetchURI, err)
+		}
+		return execCtx.Context().Err() == nil, fmt.Errorf("failed to read response from %v: %w", f.FetchURI, err)
+	}
+	return false, nil
+}
+
+// writeErrRecorder records the error of a failed write, so that
+// errors writing a download can be told apart from errors reading it
+type writeErrRecorder struct {
+	w   io.Writer
+	err error
+}
+
+// Write writes to the underlying writer and records any error
+func (r *writeErrRecorder) Write(p []byte) (int, error) {
+	n, err := r.w.Write(p)
 	if err != nil {
+		r.err = err
+	}
+	return n, err
+}
+
+// verify checks the hash of the contents against
+// the SHA256 checksum of the step (if any)
+func (f *FetchURIStep) verify(hash hash.Hash) error {
+	if f.SHA256 == "" {
+		return nil
+	}
+	expected := strings.ToLower(f.SHA256)
+	actual := hex.EncodeToString(hash.Sum(nil))
+	if actual != expected {
+		return fmt.Errorf("sha256 checksum mismatch: expected %s, got %s", expected, actual)
+	}
+	return nil
+}
+
+// cachePath returns the path of the file in the download cache
+// that holds the contents of the URI, or an empty string if the
+// contents cannot be cached - only files with a known checksum are
+// cached, so that the cache is addressed by checksum
+func (f *FetchURIStep) cachePath(execCtx TTPExecutionContext) string {
+	if execCtx.Cfg.CacheDir == "" || f.SHA256 == "" {
+		return ""
+	}
+	return filepath.Join(execCtx.Cfg.CacheDir, "sha256", strings.ToLower(f.SHA256))
+}
+
+// createDownloadFile creates a new hidden file next to the location
+// of a download to hold its contents until they are verified. It is
+// created with the permissions that creating the location would use.
+func createDownloadFile(fsys afero.Fs, location string) (afero.File, error) {
+	dir, base := filepath.Split(location)
+	for {
+		name := filepath.Join(dir, fmt.Sprintf(".%v.download-%d", base, rand.Uint32()))
+		file, err := fsys.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0666)
+		if !errors.Is(err, fs.ErrExist) {
+			return file, err
+		}
+	}
+}
+
+// resetDownloadFile discards the contents of a download file
+func resetDownloadFile(file afero.File, hash hash.Hash) error {
+	hash.Reset()
+	if err := file.Truncate(0); err != nil {
 		return err
 	}
-	defer fHandle.Close()
+	_, err := file.Seek(0, io.SeekStart)
+	return err
+}
 
-	_, err = io.Copy(fHandle, resp.Body)
+// writeCacheFile atomically copies a downloaded file to the download
+// cache, so that concurrent runs never read partially written files
+func writeCacheFile(fsys afero.Fs, downloadPath, cachePath string) error {
+	downloaded, err := fsys.Open(downloadPath)
 	if err != nil {
 		return err
 	}
+	defer downloaded.Close()
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0700); err != nil {
+		return err
+	}
+	tmpFile, err := os.CreateTemp(filepath.Dir(cachePath), ".download-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+	if _, err := io.Copy(tmpFile, downloaded); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile.Name(), cachePath)
+}
 
-	logging.L().Debugw("wrote contents of URI to specified location", "location", absLocal, "uri", f.FetchURI)
+// retries returns the number of times a failed download is retried
+func (f *FetchURIStep) retries() (int, error) {
+	if f.Retries == "" {
+		return 0, nil
+	}
+	retries, err := strconv.Atoi(f.Retries)
+	if err != nil || retries < 0 {
+		return 0, fmt.Errorf("invalid retries value %q - must be a non-negative integer", f.Retries)
+	}
+	return retries, nil
+}
 
+// validateSHA256 checks that the checksum (if any) is a hexadecimal SHA256 checksum
+func (f *FetchURIStep) validateSHA256() error {
+	if f.SHA256 != "" && !sha256Regexp.MatchString(f.SHA256) {
+		return fmt.Errorf("invalid sha256 value %q - must be 64 hexadecimal characters", f.SHA256)
+	}
 	return nil
 }
 
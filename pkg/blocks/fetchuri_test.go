@@ -20,12 +20,17 @@ THE SOFTWARE.
 package blocks
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"github.com/spf13/afero"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/facebookincubator/ttpforge/pkg/testutils"
 	"github.com/stretchr/testify/assert"
@@ -100,7 +105,7 @@ func TestFetchURI(t *testing.T) {
 		expectTemplateError bool
 		expectExecuteError  bool
 		fsysContents        map[string][]byte
-		stepVars            map[string]string
+		stepVars            map[string]interface{}
 	}{
 		{
 			name: "simple fetch",
@@ -184,7 +189,7 @@ location: /tmp/{[{.StepVars.filename}]}.txt
 proxy: http://{[{.StepVars.proxy}]}:8080
 retries: "{[{.StepVars.retries}]}"
 `,
-			stepVars: map[string]string{
+			stepVars: map[string]interface{}{
 				"site":     "someuri",
 				"filename": "output",
 				"proxy":    "localhost",
@@ -213,7 +218,7 @@ fetch_uri: http://someuri.com
 proxy: ://{[{.StepVars.proxy}]}:8080
 location: /tmp/output.txt
 `,
-			stepVars: map[string]string{
+			stepVars: map[string]interface{}{
 				"proxy": "someuri",
 			},
 			fsysContents: map[string][]byte{
@@ -228,7 +233,7 @@ name: fetch
 fetch_uri: http://someuri.com
 location: /tmp/{[{.StepVars.location}]}.txt
 `,
-			stepVars: map[string]string{
+			stepVars: map[string]interface{}{
 				"location": "test",
 			},
 			fsysContents: map[string][]byte{
@@ -236,6 +241,49 @@ location: /tmp/{[{.StepVars.location}]}.txt
 			},
 			expectTemplateError: true,
 		},
+		{
+			name: "invalid retries",
+			content: `
+name: fetch
+fetch_uri: http://someuri.com
+location: /tmp/output.txt
+retries: -1
+`,
+			expectValidateError: true,
+		},
+		{
+			name: "invalid sha256",
+			content: `
+name: fetch
+fetch_uri: http://someuri.com
+location: /tmp/output.txt
+sha256: notachecksum
+`,
+			expectValidateError: true,
+		},
+		{
+			name: "fails validation after templating retries",
+			content: `
+name: fetch
+fetch_uri: http://someuri.com
+location: /tmp/output.txt
+retries: "{[{.StepVars.retries}]}"
+`,
+			stepVars: map[string]interface{}{
+				"retries": "many",
+			},
+			expectTemplateError: true,
+		},
+		{
+			name: "checksum mismatch",
+			content: `
+name: fetch
+fetch_uri: http://someuri.com
+location: /tmp/output.txt
+sha256: 0000000000000000000000000000000000000000000000000000000000000000
+`,
+			expectExecuteError: true,
+		},
 	}
 
 	// prepare test server
@@ -342,3 +390,147 @@ overwrite: true
 	assert.Equal(t, string(dat), "Hello, client\n")
 
 }
+
+func TestFetchURIDownload(t *testing.T) {
+	payload := []byte("Here's some data!")
+	sum := sha256.Sum256(payload)
+	payloadSHA256 := hex.EncodeToString(sum[:])
+	otherSum := sha256.Sum256([]byte("other data"))
+	otherSHA256 := hex.EncodeToString(otherSum[:])
+
+	testCases := []struct {
+		name             string
+		retries          string
+		sha256           string
+		statuses         []int
+		cached           []byte
+		wantRequests     int32
+		wantError        bool
+		wantCachedResult bool
+	}{
+		{
+			name:         "fails on not found",
+			statuses:     []int{http.StatusNotFound},
+			wantRequests: 1,
+			wantError:    true,
+		},
+		{
+			name:         "does not retry client errors",
+			retries:      "3",
+			statuses:     []int{http.StatusForbidden},
+			wantRequests: 1,
+			wantError:    true,
+		},
+		{
+			name:         "retries server errors",
+			retries:      "2",
+			statuses:     []int{http.StatusInternalServerError, http.StatusTooManyRequests, http.StatusOK},
+			wantRequests: 3,
+		},
+		{
+			name:         "gives up after retries",
+			retries:      "1",
+			statuses:     []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusOK},
+			wantRequests: 2,
+			wantError:    true,
+		},
+		{
+			name:             "verifies and caches download",
+			sha256:           payloadSHA256,
+			statuses:         []int{http.StatusOK},
+			wantRequests:     1,
+			wantCachedResult: true,
+		},
+		{
+			name:             "uses cached copy",
+			sha256:           payloadSHA256,
+			statuses:         []int{http.StatusNotFound},
+			cached:           payload,
+			wantRequests:     0,
+			wantCachedResult: true,
+		},
+		{
+			name:             "replaces corrupt cached copy",
+			sha256:           payloadSHA256,
+			statuses:         []int{http.StatusOK},
+			cached:           []byte("Here's some corrupt data!"),
+			wantRequests:     1,
+			wantCachedResult: true,
+		},
+		{
+			name:         "fails on checksum mismatch",
+			sha256:       otherSHA256,
+			statuses:     []int{http.StatusOK},
+			wantRequests: 1,
+			wantError:    true,
+		},
+	}
+
+	origDelay := fetchRetryDelay
+	fetchRetryDelay = time.Millisecond
+	defer func() { fetchRetryDelay = origDelay }()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// the server responds with the statuses in turn
+			var requests atomic.Int32
+			testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := int(requests.Add(1))
+				status := tc.statuses[min(n, len(tc.statuses))-1]
+				w.WriteHeader(status)
+				if status == http.StatusOK {
+					w.Write(payload)
+				}
+			}))
+			defer testServer.Close()
+
+			cacheDir := t.TempDir()
+			cachePath := filepath.Join(cacheDir, "sha256", payloadSHA256)
+			if tc.cached != nil {
+				require.NoError(t, os.MkdirAll(filepath.Dir(cachePath), 0700))
+				require.NoError(t, os.WriteFile(cachePath, tc.cached, 0600))
+			}
+
+			step := FetchURIStep{
+				FetchURI:   testServer.URL,
+				Location:   "/tmp/output.txt",
+				Retries:    tc.retries,
+				SHA256:     tc.sha256,
+				FileSystem: afero.NewMemMapFs(),
+			}
+			execCtx := NewTTPExecutionContext()
+			execCtx.Cfg.CacheDir = cacheDir
+
+			require.NoError(t, step.Validate(execCtx))
+			_, err := step.Execute(execCtx)
+			assert.Equal(t, tc.wantRequests, requests.Load())
+
+			// the temporary download file is always removed
+			entries, readErr := afero.ReadDir(step.FileSystem, filepath.Dir(step.Location))
+			require.NoError(t, readErr)
+			for _, entry := range entries {
+				assert.Equal(t, filepath.Base(step.Location), entry.Name())
+			}
+			if tc.wantError {
+				require.Error(t, err)
+				exists, err := afero.Exists(step.FileSystem, step.Location)
+				require.NoError(t, err)
+				assert.False(t, exists, "location should not be written on failure")
+				return
+			}
+			require.NoError(t, err)
+
+			contents, err := afero.ReadFile(step.FileSystem, step.Location)
+			require.NoError(t, err)
+			assert.Equal(t, payload, contents)
+
+			cached, err := os.ReadFile(cachePath)
+			if tc.wantCachedResult {
+				require.NoError(t, err)
+				assert.Equal(t, payload, cached)
+			} else {
+				assert.True(t, os.IsNotExist(err))
+			}
+		})
+	}
+}
@@ -20,7 +20,6 @@ THE SOFTWARE.
 package blocks
 
 import (
-	"context"
 	"errors"
 	"os/exec"
 
@@ -132,7 +131,7 @@ func (f *FileStep) Template(execCtx TTPExecutionContext) error {
 
 // Execute runs the step and returns an error if one occurs.
 func (f *FileStep) Execute(execCtx TTPExecutionContext) (*ActResult, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), DefaultExecutionTimeout)
+	ctx, cancel := contextWithDefaultTimeout(execCtx.Context())
 	defer cancel()
 
 	executor := NewExecutor(f.Executor, "", f.FilePath, f.Args, f.Environment)
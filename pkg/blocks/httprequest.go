@@ -234,7 +234,7 @@ func (r *HTTPRequestStep) SendRequest(execCtx TTPExecutionContext) error {
 	trimBody := strings.TrimSuffix(r.Body, "\n")
 
 	// Create a new request with the specified method, URL, and body.
-	req, err := http.NewRequest(r.Type, fullURL, strings.NewReader(trimBody))
+	req, err := http.NewRequestWithContext(execCtx.Context(), r.Type, fullURL, strings.NewReader(trimBody))
 	if err != nil {
 		return fmt.Errorf("error creating request: %w", err)
 	}
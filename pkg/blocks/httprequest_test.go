@@ -154,7 +154,7 @@ func TestHTTPequest(t *testing.T) {
 		expectValidateError bool
 		expectTemplateError bool
 		expectExecuteError  bool
-		stepVars            map[string]string
+		stepVars            map[string]interface{}
 		overwriteProxy      bool
 		expectedOutput      string
 	}{
@@ -298,7 +298,7 @@ parameters:
   value: "{[{.StepVars.value}]}"
 body: "{'body': '{[{.StepVars.body}]}'}"
 `,
-			stepVars: map[string]string{
+			stepVars: map[string]interface{}{
 				"path":  "some/path",
 				"proxy": "localhost",
 				"type":  "POST",
@@ -331,7 +331,7 @@ body: "{'body': '{[{.StepVars.body}]}'}"
 name: template test
 http_request: ://someuri.com/{[{.StepVars.path}]}
 `,
-			stepVars: map[string]string{
+			stepVars: map[string]interface{}{
 				"path": "some/path",
 			},
 			expectTemplateError: true,
@@ -343,7 +343,7 @@ name: template test
 http_request: http://someuri.com/
 proxy: ://{[{.StepVars.proxy}]}:8080
 `,
-			stepVars: map[string]string{
+			stepVars: map[string]interface{}{
 				"proxy": "localhost",
 			},
 			expectTemplateError: true,
@@ -355,7 +355,7 @@ name: template test
 http_request: http://someuri.com/
 type: "{[{.StepVars.type}]}"
 `,
-			stepVars: map[string]string{
+			stepVars: map[string]interface{}{
 				"type": "WTF",
 			},
 			expectTemplateError: true,
@@ -367,7 +367,7 @@ name: template test
 http_request: http://someuri.com/
 outputvar: testvar
 `,
-			stepVars:       map[string]string{},
+			stepVars:       map[string]interface{}{},
 			expectedOutput: "Here's some data!",
 		},
 		{
@@ -377,7 +377,7 @@ name: template test
 http_request: http://someuri.com/
 outputvar: testvar
 `,
-			stepVars: map[string]string{
+			stepVars: map[string]interface{}{
 				"testvar": "some other data",
 			},
 			expectedOutput: "Here's some data!",
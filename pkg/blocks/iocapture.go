@@ -23,6 +23,7 @@ import (
 	"bytes"
 	"io"
 	"os/exec"
+	"sync"
 
 	"github.com/facebookincubator/ttpforge/pkg/logging"
 )
@@ -88,7 +89,28 @@ func (zw *zapWriter) Write(p []byte) (n int, err error) {
 	return len(p), nil
 }
 
-func streamAndCapture(cmd exec.Cmd, stdout, stderr io.Writer) (*ActResult, error) {
+func streamAndCapture(cmd *exec.Cmd, stdout, stderr io.Writer) (*ActResult, error) {
+	proc, err := startAndCapture(cmd, stdout, stderr)
+	if err != nil {
+		return nil, err
+	}
+	return proc.wait()
+}
+
+// capturedCommand is a running command whose output is streamed
+// (to the log, by default) and captured at the same time
+type capturedCommand struct {
+	cmd       *exec.Cmd
+	stdout    io.Writer
+	stderr    io.Writer
+	stdoutBuf captureBuffer
+	stderrBuf captureBuffer
+}
+
+// startAndCapture starts the command without waiting for it to exit.
+// Its output is streamed to stdout and stderr (or to the log if they
+// are nil) and captured until it exits or stopCapture is called.
+func startAndCapture(cmd *exec.Cmd, stdout, stderr io.Writer) (*capturedCommand, error) {
 	if stdout == nil {
 		stdout = &bufferedWriter{
 			writer: &zapWriter{
@@ -104,27 +126,80 @@ func streamAndCapture(cmd exec.Cmd, stdout, stderr io.Writer) (*ActResult, error
 		}
 	}
 
-	var stdoutBuf, stderrBuf bytes.Buffer
-	cmd.Stdout = io.MultiWriter(stdout, &stdoutBuf)
-	cmd.Stderr = io.MultiWriter(stderr, &stderrBuf)
+	proc := &capturedCommand{
+		cmd:    cmd,
+		stdout: stdout,
+		stderr: stderr,
+	}
+	cmd.Stdout = io.MultiWriter(stdout, &proc.stdoutBuf)
+	cmd.Stderr = io.MultiWriter(stderr, &proc.stderrBuf)
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	return proc, nil
+}
 
-	err := cmd.Run()
+// wait waits for the command to exit and returns its captured output
+func (c *capturedCommand) wait() (*ActResult, error) {
+	err := c.cmd.Wait()
 
 	// Flush any remaining stdout output that doesn't end with newline
-	if bw, ok := stdout.(*bufferedWriter); ok {
+	if bw, ok := c.stdout.(*bufferedWriter); ok {
 		bw.Close()
 	}
 	// Flush any remaining stderr output that doesn't end with newline
-	if bw, ok := stderr.(*bufferedWriter); ok {
+	if bw, ok := c.stderr.(*bufferedWriter); ok {
 		bw.Close()
 	}
 
 	if err != nil {
 		return nil, err
 	}
-	outStr, errStr := stdoutBuf.String(), stderrBuf.String()
+	outStr, errStr := c.output()
 	result := ActResult{}
 	result.Stdout = outStr
 	result.Stderr = errStr
 	return &result, nil
 }
+
+// output returns the output that the command has produced so far
+func (c *capturedCommand) output() (stdout, stderr string) {
+	return c.stdoutBuf.String(), c.stderrBuf.String()
+}
+
+// stopCapture stops capturing (but not streaming) the output
+// of the command, so that the output of long-lived commands
+// does not accumulate in memory
+func (c *capturedCommand) stopCapture() {
+	c.stdoutBuf.stop()
+	c.stderrBuf.stop()
+}
+
+// captureBuffer is a buffer that can be read while
+// a command is writing to it
+type captureBuffer struct {
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	stopped bool
+}
+
+func (cb *captureBuffer) Write(p []byte) (int, error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if cb.stopped {
+		return len(p), nil
+	}
+	return cb.buf.Write(p)
+}
+
+func (cb *captureBuffer) String() string {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return cb.buf.String()
+}
+
+func (cb *captureBuffer) stop() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.stopped = true
+}
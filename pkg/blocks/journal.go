@@ -0,0 +1,707 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package blocks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/facebookincubator/ttpforge/pkg/logging"
+	"github.com/google/uuid"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+)
+
+// RunStatus describes the overall state of a journaled TTP run
+type RunStatus string
+
+const (
+	// RunStatusRunning means the steps of the TTP are still being
+	// executed - or that the run was interrupted before it finished
+	RunStatusRunning RunStatus = "running"
+	// RunStatusSucceeded means every step of the TTP completed successfully
+	RunStatusSucceeded RunStatus = "succeeded"
+	// RunStatusFailed means the TTP stopped early because a step failed
+	RunStatusFailed RunStatus = "failed"
+)
+
+// runStateFileName is the name of the file (in the directory
+// of each run) that holds the journaled state of the run
+const runStateFileName = "state.json"
+
+// JournaledStep is the persisted record of a step
+// that completed during a journaled run
+type JournaledStep struct {
+	*ExecutionResult
+	// CleanupAction identifies the type of the cleanup
+	// action of the step (such as `inline` or `remove_path`)
+	CleanupAction string `json:"cleanup_action,omitempty"`
+	// RenderedCleanup is the YAML of the cleanup action
+	// specified for the step, with step templates such as
+	// `{[{.StepVars.foo}]}` resolved. It is empty for
+	// default cleanup actions.
+	RenderedCleanup string `json:"rendered_cleanup,omitempty"`
+}
+
+// RunState is the persisted state of a TTP run. It contains
+// everything needed to resume the run or clean it up from a
+// separate invocation of ttpforge.
+type RunState struct {
+	RunID   string   `json:"run_id"`
+	TTPRef  string   `json:"ttp_ref"`
+	TTPPath string   `json:"ttp_path"`
+	Args    []string `json:"args,omitempty"`
+	// WorkingDir is the directory from which the run was started,
+	// which is needed to resolve relative path arguments
+	WorkingDir string `json:"working_dir"`
+	// RenderedTTP is the TTP after template rendering, so that
+	// template functions such as randAlphaNum produce the same
+	// values when the run is resumed
+	RenderedTTP string        `json:"rendered_ttp"`
+	NoChecks    bool          `json:"no_checks,omitempty"`
+	StepTimeout time.Duration `json:"step_timeout,omitempty"`
+	Status      RunStatus     `json:"status"`
+	Error       string        `json:"error,omitempty"`
+	// CleanupPending is true if any completed
+	// step has not yet been cleaned up
+	CleanupPending bool                   `json:"cleanup_pending"`
+	StartTime      time.Time              `json:"start_time"`
+	UpdateTime     time.Time              `json:"update_time"`
+	StepVars       map[string]interface{} `json:"step_vars"`
+	Steps          []JournaledStep        `json:"steps"`
+}
+
+// RunJournal persists the state of a TTP run after every step
+// (and every cleanup action) so that the run can be resumed or
+// cleaned up by a later invocation of ttpforge if it is interrupted.
+// The values of secret arguments are never written to disk - they are
+// replaced by placeholders that are filled in again by LoadTTP.
+type RunJournal struct {
+	State   RunState
+	dir     string
+	secrets map[string]string
+}
+
+// NewRunID generates a unique identifier for a
+// run that sorts in the order in which runs started
+func NewRunID() string {
+	return time.Now().Format("20060102-150405") + "-" + uuid.New().String()[:8]
+}
+
+// StartRunJournal creates the journal of a new run of a TTP in the
+// directory runsDir/<run-id> and attaches it to the execution context,
+// so that the state of the run is saved as each step completes.
+//
+// **Parameters:**
+//
+// runsDir: the directory that holds the journals of all runs
+// ttpRef: the reference used to locate the TTP (e.g. repo//path/to/ttp.yaml)
+// ttpPath: the absolute path to the TTP file
+// ttp: the loaded TTP
+// execCtx: the execution context of the TTP
+// argsKvStrs: the argument values passed to the TTP
+//
+// **Returns:**
+//
+// *RunJournal: the journal of the new run
+// error: an error if the journal could not be created
+func StartRunJournal(runsDir, ttpRef, ttpPath string, ttp *TTP, execCtx *TTPExecutionContext, argsKvStrs []string) (*RunJournal, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	runID := NewRunID()
+	j := &RunJournal{
+		dir: filepath.Join(runsDir, runID),
+		State: RunState{
+			RunID:       runID,
+			TTPRef:      ttpRef,
+			TTPPath:     ttpPath,
+			Args:        argsKvStrs,
+			WorkingDir:  wd,
+			RenderedTTP: string(ttp.rendered),
+			NoChecks:    execCtx.Cfg.NoChecks,
+			StepTimeout: execCtx.Cfg.StepTimeout,
+			Status:      RunStatusRunning,
+			StartTime:   time.Now(),
+		},
+		secrets: secretArgs(ttp, execCtx.Vars.Args),
+	}
+	// the journal may contain sensitive argument values and outputs
+	if err := os.MkdirAll(j.dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create run directory: %w", err)
+	}
+	if err := j.save(); err != nil {
+		return nil, err
+	}
+	execCtx.Journal = j
+	return j, nil
+}
+
+// LatestRunJournal loads the journal of the most recently started run
+//
+// **Parameters:**
+//
+// runsDir: the directory that holds the journals of all runs
+//
+// **Returns:**
+//
+// *RunJournal: the journal of the most recent run
+// error: an error if there are no runs or they could not be read
+func LatestRunJournal(runsDir string) (*RunJournal, error) {
+	entries, err := os.ReadDir(runsDir)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	var latest *RunJournal
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		j, err := OpenRunJournal(runsDir, entry.Name())
+		if err != nil {
+			logging.L().Debugf("Ignoring run %v: %v", entry.Name(), err)
+			continue
+		}
+		if latest == nil || j.State.StartTime.After(latest.State.StartTime) {
+			latest = j
+		}
+	}
+	if latest == nil {
+		return nil, fmt.Errorf("no runs found in %v", runsDir)
+	}
+	return latest, nil
+}
+
+// PruneRunJournals deletes the journals of runs that finished (and
+// were cleaned up completely) longer than retention ago. The journals
+// of runs that may still need to be resumed or cleaned up are kept,
+// as are journals that cannot be read.
+//
+// **Parameters:**
+//
+// runsDir: the directory that holds the journals of all runs
+// retention: how long the journals of finished runs are kept
+//
+// **Returns:**
+//
+// int: the number of journals that were deleted
+// error: an error if any journal could not be deleted
+func PruneRunJournals(runsDir string, retention time.Duration) (int, error) {
+	entries, err := os.ReadDir(runsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	cutoff := time.Now().Add(-retention)
+	var pruned int
+	var errs []error
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		j, err := OpenRunJournal(runsDir, entry.Name())
+		if err != nil {
+			continue
+		}
+		if j.State.Status == RunStatusRunning || j.State.CleanupPending || j.State.UpdateTime.After(cutoff) {
+			continue
+		}
+		if err := os.RemoveAll(j.dir); err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete run %v: %w", j.State.RunID, err))
+			continue
+		}
+		pruned++
+	}
+	return pruned, errors.Join(errs...)
+}
+
+// OpenRunJournal loads the journal of a previous run
+//
+// **Parameters:**
+//
+// runsDir: the directory that holds the journals of all runs
+// runID: the identifier of the run
+//
+// **Returns:**
+//
+// *RunJournal: the journal of the run
+// error: an error if the journal does not exist or could not be read
+func OpenRunJournal(runsDir, runID string) (*RunJournal, error) {
+	if runID == "" || filepath.Base(runID) != runID {
+		return nil, fmt.Errorf("invalid run ID %q", runID)
+	}
+	j := &RunJournal{dir: filepath.Join(runsDir, runID)}
+	contents, err := os.ReadFile(filepath.Join(j.dir, runStateFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no run with ID %q found in %v", runID, runsDir)
+		}
+		return nil, err
+	}
+	if err := json.Unmarshal(contents, &j.State); err != nil {
+		return nil, fmt.Errorf("failed to parse state of run %v: %w", runID, err)
+	}
+	return j, nil
+}
+
+// ID returns the identifier of the run
+func (j *RunJournal) ID() string {
+	return j.State.RunID
+}
+
+// Dir returns the directory that holds the state of the run
+func (j *RunJournal) Dir() string {
+	return j.dir
+}
+
+// LoadTTP reloads the TTP of the run exactly as it was rendered for the
+// original run. The checks and step timeout settings of the original run
+// take precedence over those in execCfg. Secret argument values are read
+// again from their environment variable, file or an interactive prompt.
+//
+// **Parameters:**
+//
+// fsys: the file system of the repository that contains the TTP
+// execCfg: the execution configuration for the TTP
+//
+// **Returns:**
+//
+// *TTP: the reloaded TTP
+// *TTPExecutionContext: the execution context for the TTP
+// error: an error if the TTP could not be loaded
+func (j *RunJournal) LoadTTP(fsys afero.Fs, execCfg *TTPExecutionConfig) (*TTP, *TTPExecutionContext, error) {
+	// the values of secret arguments were not persisted, so
+	// they are read again from their other sources (if any)
+	var argsKvStrs []string
+	for _, argKvStr := range j.State.Args {
+		name, val, _ := strings.Cut(argKvStr, "=")
+		if val != secretPlaceholder(name) {
+			argsKvStrs = append(argsKvStrs, argKvStr)
+		}
+	}
+	argValues, err := parseTTPArgs([]byte(j.State.RenderedTTP), j.State.TTPPath, argsKvStrs, j.State.WorkingDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var ttp TTP
+	if err := yaml.Unmarshal([]byte(j.State.RenderedTTP), &ttp); err != nil {
+		return nil, nil, fmt.Errorf("failed to decode TTP of run %v: %w", j.State.RunID, err)
+	}
+	if secrets := secretArgs(&ttp, argValues); len(secrets) > 0 {
+		j.unmaskSecrets(secrets)
+		ttp = TTP{}
+		if err := yaml.Unmarshal([]byte(j.State.RenderedTTP), &ttp); err != nil {
+			return nil, nil, fmt.Errorf("failed to decode TTP of run %v: %w", j.State.RunID, err)
+		}
+	}
+	ttp.rendered = []byte(j.State.RenderedTTP)
+
+	cfg := *execCfg
+	cfg.NoChecks = j.State.NoChecks
+	cfg.StepTimeout = j.State.StepTimeout
+	stepVars := make(map[string]interface{}, len(j.State.StepVars))
+	for k, v := range j.State.StepVars {
+		stepVars[k] = v
+	}
+	return prepareTTP(&ttp, j.State.TTPPath, fsys, &cfg, stepVars, argValues)
+}
+
+// Resume restores the results of the steps that completed before the
+// run was interrupted, so that TTP.Execute continues with the first step
+// that did not complete. Steps that failed or were already cleaned up
+// (along with every step after them) are executed again.
+//
+// **Parameters:**
+//
+// ttp: the TTP loaded by LoadTTP
+// execCtx: the execution context loaded by LoadTTP
+//
+// **Returns:**
+//
+// int: the number of steps that will not be executed again
+// error: an error if the run has already completed
+func (j *RunJournal) Resume(ttp *TTP, execCtx *TTPExecutionContext) (int, error) {
+	if j.State.Status == RunStatusSucceeded {
+		return 0, fmt.Errorf("run %v has already completed successfully", j.State.RunID)
+	}
+	completed := 0
+	for _, step := range j.State.Steps {
+		if step.ExecutionResult == nil || step.CleanedUp ||
+			(step.Status != StepStatusSucceeded && step.Status != StepStatusSkipped) {
+			break
+		}
+		completed++
+	}
+	if completed >= len(ttp.Steps) {
+		return 0, fmt.Errorf("all steps of run %v have already completed - use `ttpforge cleanup %v` to clean it up", j.State.RunID, j.State.RunID)
+	}
+
+	ttp.restoreResults(*execCtx, j.results()[:completed])
+	j.State.Status = RunStatusRunning
+	j.State.Error = ""
+	execCtx.Journal = j
+	j.record(ttp, *execCtx)
+	return completed, nil
+}
+
+// Restore restores the results of every journaled step of the run so
+// that TTP.RunCleanup cleans up each step that has not been cleaned up yet.
+// The persisted cleanup actions of the steps are used in place of those
+// in the TTP, so that they are executed exactly as they would have been
+// at the end of the original run.
+//
+// **Parameters:**
+//
+// ttp: the TTP loaded by LoadTTP
+// execCtx: the execution context loaded by LoadTTP
+//
+// **Returns:**
+//
+// error: an error if a persisted cleanup action is invalid
+func (j *RunJournal) Restore(ttp *TTP, execCtx *TTPExecutionContext) error {
+	for idx, step := range j.State.Steps {
+		if idx >= len(ttp.Steps) || step.RenderedCleanup == "" {
+			continue
+		}
+		var doc yaml.Node
+		if err := yaml.Unmarshal([]byte(step.RenderedCleanup), &doc); err != nil || len(doc.Content) == 0 {
+			return fmt.Errorf("invalid cleanup action persisted for step %q: %v", ttp.Steps[idx].Name, err)
+		}
+		cleanup, err := ttp.Steps[idx].ParseAction(doc.Content[0])
+		if err != nil {
+			return fmt.Errorf("could not parse cleanup action persisted for step %q: %w", ttp.Steps[idx].Name, err)
+		}
+		if err := cleanup.Validate(*execCtx); err != nil {
+			return fmt.Errorf("invalid cleanup action persisted for step %q: %w", ttp.Steps[idx].Name, err)
+		}
+		ttp.Steps[idx].cleanup = cleanup
+	}
+	ttp.restoreResults(*execCtx, j.results())
+	execCtx.Journal = j
+	return nil
+}
+
+// Finish records the outcome of executing the steps of the run
+//
+// **Parameters:**
+//
+// runErr: the error (if any) returned by TTP.Execute
+//
+// **Returns:**
+//
+// error: an error if the state of the run could not be saved
+func (j *RunJournal) Finish(runErr error) error {
+	j.State.Status = RunStatusSucceeded
+	j.State.Error = ""
+	if runErr != nil {
+		j.State.Status = RunStatusFailed
+		j.State.Error = runErr.Error()
+	}
+	return j.save()
+}
+
+// results returns the journaled results of the steps of the run
+func (j *RunJournal) results() []*ExecutionResult {
+	var results []*ExecutionResult
+	for _, step := range j.State.Steps {
+		if step.ExecutionResult == nil {
+			break
+		}
+		results = append(results, step.ExecutionResult)
+	}
+	return results
+}
+
+// record updates the journal with the current results and
+// step variables of the run. Failing to save the journal does
+// not interrupt the run, so errors are only logged.
+func (j *RunJournal) record(ttp *TTP, execCtx TTPExecutionContext) {
+	if j == nil {
+		return
+	}
+	j.State.CleanupPending = false
+	j.State.Steps = make([]JournaledStep, len(execCtx.StepResults.ByIndex))
+	for idx, result := range execCtx.StepResults.ByIndex {
+		j.State.Steps[idx] = JournaledStep{ExecutionResult: result}
+		if idx >= len(ttp.Steps) || ttp.Steps[idx].cleanup == nil {
+			continue
+		}
+		j.State.Steps[idx].CleanupAction = ActionTypeName(ttp.Steps[idx].cleanup)
+		if result.NeedsCleanup() {
+			j.State.CleanupPending = true
+			j.State.Steps[idx].RenderedCleanup = renderCleanup(&ttp.Steps[idx], execCtx)
+		}
+	}
+	j.State.StepVars = make(map[string]interface{}, len(execCtx.Vars.StepVars))
+	for k, v := range execCtx.Vars.StepVars {
+		j.State.StepVars[k] = v
+	}
+	if err := j.save(); err != nil {
+		logging.L().Warnf("Failed to save state of run %v: %v", j.State.RunID, err)
+	}
+}
+
+// renderCleanup returns the YAML of the cleanup action specified
+// for a step with its step templates resolved using the current
+// step variables. If the templates cannot be resolved yet, they are
+// left as they are and resolved when the cleanup action runs.
+func renderCleanup(step *Step, execCtx TTPExecutionContext) string {
+	if step.CleanupSpec.IsZero() {
+		return ""
+	}
+	if useDefault, _ := isDefaultCleanup(&step.CleanupSpec); useDefault {
+		return ""
+	}
+	b, err := yaml.Marshal(&step.CleanupSpec)
+	if err != nil {
+		logging.L().Debugf("Could not encode cleanup action of step %q: %v", step.Name, err)
+		return ""
+	}
+
+	// render each value separately (on a copy of the
+	// spec) so that rendered values cannot break the YAML
+	var doc yaml.Node
+	if err := yaml.Unmarshal(b, &doc); err != nil {
+		return string(b)
+	}
+	if err := templateScalars(&doc, execCtx); err != nil {
+		logging.L().Debugf("Could not render cleanup action of step %q: %v", step.Name, err)
+		return string(b)
+	}
+	rendered, err := yaml.Marshal(&doc)
+	if err != nil {
+		return string(b)
+	}
+	return string(rendered)
+}
+
+// templateScalars resolves the step templates in
+// every scalar value of a YAML node tree
+func templateScalars(node *yaml.Node, execCtx TTPExecutionContext) error {
+	if node.Kind == yaml.ScalarNode && execCtx.containsStepTemplating(node.Value) {
+		value, err := execCtx.templateStep(node.Value)
+		if err != nil {
+			return err
+		}
+		node.Value = value
+		node.Tag = "!!str"
+		node.Style = 0
+	}
+	for _, child := range node.Content {
+		if err := templateScalars(child, execCtx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// save atomically writes the state of the run to its state file
+func (j *RunJournal) save() error {
+	j.State.UpdateTime = time.Now()
+	state := j.State
+	if len(j.secrets) > 0 {
+		masked, err := j.maskedState()
+		if err != nil {
+			return err
+		}
+		state = *masked
+	}
+	contents, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state of run %v: %w", j.State.RunID, err)
+	}
+	statePath := filepath.Join(j.dir, runStateFileName)
+	tmpPath := statePath + ".tmp"
+	if err := os.WriteFile(tmpPath, contents, 0600); err != nil {
+		return fmt.Errorf("failed to write state of run %v: %w", j.State.RunID, err)
+	}
+	return os.Rename(tmpPath, statePath)
+}
+
+// maskedState returns a copy of the state of the run in which
+// the values of secret arguments are replaced by placeholders.
+// Only the string values that can contain argument values are
+// masked, so that (short) secrets cannot corrupt the structure
+// of the state file or fields such as its run ID and timestamps.
+func (j *RunJournal) maskedState() (*RunState, error) {
+	// the state shares step results with the running
+	// TTP, so it is copied before it is masked
+	contents, err := json.Marshal(j.State)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode state of run %v: %w", j.State.RunID, err)
+	}
+	var state RunState
+	if err := json.Unmarshal(contents, &state); err != nil {
+		return nil, fmt.Errorf("failed to encode state of run %v: %w", j.State.RunID, err)
+	}
+	oldNew := make([]string, 0, 2*len(j.secrets))
+	for _, name := range j.secretNames() {
+		oldNew = append(oldNew, j.secrets[name], secretPlaceholder(name))
+	}
+	state.replaceSecrets(strings.NewReplacer(oldNew...))
+	return &state, nil
+}
+
+// unmaskSecrets replaces the placeholders of secret
+// argument values in the state of the run with the values
+func (j *RunJournal) unmaskSecrets(secrets map[string]string) {
+	j.secrets = secrets
+	oldNew := make([]string, 0, 2*len(j.secrets))
+	for _, name := range j.secretNames() {
+		oldNew = append(oldNew, secretPlaceholder(name), j.secrets[name])
+	}
+	j.State.replaceSecrets(strings.NewReplacer(oldNew...))
+}
+
+// secretNames returns the names of the secret arguments of
+// the run, ordered so that longer values are replaced first
+func (j *RunJournal) secretNames() []string {
+	names := make([]string, 0, len(j.secrets))
+	for name := range j.secrets {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(a, b int) bool {
+		if len(j.secrets[names[a]]) != len(j.secrets[names[b]]) {
+			return len(j.secrets[names[a]]) > len(j.secrets[names[b]])
+		}
+		return names[a] < names[b]
+	})
+	return names
+}
+
+// replaceSecrets applies the replacer to the parts of the
+// state of the run that can contain argument values
+func (s *RunState) replaceSecrets(replacer *strings.Replacer) {
+	replaceStrings(reflect.ValueOf(&s.Args), replacer)
+	s.RenderedTTP = replacer.Replace(s.RenderedTTP)
+	s.Error = replacer.Replace(s.Error)
+	replaceStrings(reflect.ValueOf(&s.StepVars), replacer)
+	for idx := range s.Steps {
+		s.Steps[idx].RenderedCleanup = replacer.Replace(s.Steps[idx].RenderedCleanup)
+		if s.Steps[idx].ExecutionResult != nil {
+			s.Steps[idx].ExecutionResult.replaceSecrets(replacer)
+		}
+	}
+}
+
+// replaceSecrets applies the replacer to the parts
+// of the result that can contain argument values
+func (er *ExecutionResult) replaceSecrets(replacer *strings.Replacer) {
+	replaceStrings(reflect.ValueOf(&er.ActResult), replacer)
+	er.Error = replacer.Replace(er.Error)
+	replaceStrings(reflect.ValueOf(&er.Checks), replacer)
+	replaceStrings(reflect.ValueOf(&er.Cleanup), replacer)
+	er.CleanupError = replacer.Replace(er.CleanupError)
+	for idx := range er.Attempts {
+		er.Attempts[idx].Error = replacer.Replace(er.Attempts[idx].Error)
+	}
+	replaceStrings(reflect.ValueOf(&er.Loop), replacer)
+	for _, child := range er.Children {
+		if child != nil {
+			child.replaceSecrets(replacer)
+		}
+	}
+}
+
+// replaceStrings applies the replacer to every string in the
+// (settable) value, including the exported string fields of structs
+// and the strings nested in pointers, slices, maps and interfaces.
+// Map keys and unexported fields (such as those of time.Time)
+// are left unchanged.
+func replaceStrings(v reflect.Value, replacer *strings.Replacer) {
+	switch v.Kind() {
+	case reflect.String:
+		if v.CanSet() {
+			v.SetString(replacer.Replace(v.String()))
+		}
+	case reflect.Ptr:
+		if !v.IsNil() {
+			replaceStrings(v.Elem(), replacer)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				replaceStrings(v.Field(i), replacer)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			replaceStrings(v.Index(i), replacer)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			// map values are not addressable, so
+			// each value is replaced by a copy
+			elem := reflect.New(iter.Value().Type()).Elem()
+			elem.Set(iter.Value())
+			replaceStrings(elem, replacer)
+			v.SetMapIndex(iter.Key(), elem)
+		}
+	case reflect.Interface:
+		if !v.IsNil() && v.CanSet() {
+			elem := reflect.New(v.Elem().Type()).Elem()
+			elem.Set(v.Elem())
+			replaceStrings(elem, replacer)
+			v.Set(elem)
+		}
+	}
+}
+
+// secretArgs returns the values of the secret arguments of the TTP
+func secretArgs(ttp *TTP, argValues map[string]interface{}) map[string]string {
+	secrets := make(map[string]string)
+	for _, spec := range ttp.ArgSpecs {
+		if val, ok := argValues[spec.Name].(string); ok && spec.IsSecret() && val != "" {
+			secrets[spec.Name] = val
+		}
+	}
+	return secrets
+}
+
+// secretPlaceholder returns the text that replaces the value
+// of the named secret argument in the persisted run state
+func secretPlaceholder(name string) string {
+	return "((secret:" + name + "))"
+}
+
+// restoreResults records the results of a previous execution of the
+// first len(results) steps of the TTP in the provided execution context
+func (t *TTP) restoreResults(execCtx TTPExecutionContext, results []*ExecutionResult) {
+	for idx, result := range results {
+		if idx >= len(t.Steps) {
+			break
+		}
+		execCtx.StepResults.ByName[result.Name] = result
+		execCtx.StepResults.ByIndex = append(execCtx.StepResults.ByIndex, result)
+		t.Steps[idx].restoreResult(execCtx, result)
+	}
+}
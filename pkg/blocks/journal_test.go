@@ -0,0 +1,417 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package blocks
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/facebookincubator/ttpforge/pkg/logging"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const journalTestTTP = `---
+name: journal_test
+args:
+  - name: marker
+    type: path
+steps:
+  - name: first
+    inline: 'echo "first: value"'
+    outputvar: first_out
+    cleanup:
+      inline: echo "cleanup {[{.StepVars.first_out}]}"
+  - name: second
+    inline: test -f {{.Args.marker}} && echo "second {[{.StepVars.first_out}]} token-{{randAlphaNum 8}}"
+    cleanup:
+      inline: echo cleanup second
+`
+
+func TestRunJournalResume(t *testing.T) {
+	tmpDir := t.TempDir()
+	runsDir := filepath.Join(tmpDir, "runs")
+	ttpPath := filepath.Join(tmpDir, "ttp.yaml")
+	markerPath := filepath.Join(tmpDir, "marker")
+	require.NoError(t, os.WriteFile(ttpPath, []byte(journalTestTTP), 0644))
+	argsKvStrs := []string{"marker=" + markerPath}
+
+	var stdout bytes.Buffer
+	execCfg := TTPExecutionConfig{Stdout: &stdout}
+	ttp, execCtx, err := LoadTTP(ttpPath, afero.NewOsFs(), &execCfg, map[string]interface{}{}, argsKvStrs)
+	require.NoError(t, err)
+
+	// the second step fails because the marker file does not exist yet
+	journal, err := StartRunJournal(runsDir, "test-repo//ttp.yaml", ttpPath, ttp, execCtx, argsKvStrs)
+	require.NoError(t, err)
+	runErr := ttp.Execute(*execCtx)
+	require.Error(t, runErr)
+	require.NoError(t, journal.Finish(runErr))
+
+	journal, err = OpenRunJournal(runsDir, journal.ID())
+	require.NoError(t, err)
+	assert.Equal(t, RunStatusFailed, journal.State.Status)
+	assert.True(t, journal.State.CleanupPending)
+	assert.Equal(t, "first: value", journal.State.StepVars["first_out"])
+	require.Len(t, journal.State.Steps, 2)
+	assert.Equal(t, StepStatusSucceeded, journal.State.Steps[0].Status)
+	assert.Equal(t, "inline", journal.State.Steps[0].CleanupAction)
+	assert.Equal(t, StepStatusFailed, journal.State.Steps[1].Status)
+	token := regexp.MustCompile(`token-\w{8}`).FindString(journal.State.RenderedTTP)
+	require.NotEmpty(t, token)
+
+	// resuming should only run the second step, using the
+	// step variables and rendered TTP of the original run
+	require.NoError(t, os.WriteFile(markerPath, []byte{}, 0644))
+	stdout.Reset()
+	ttp, execCtx, err = journal.LoadTTP(afero.NewOsFs(), &execCfg)
+	require.NoError(t, err)
+	completed, err := journal.Resume(ttp, execCtx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, completed)
+	runErr = ttp.Execute(*execCtx)
+	require.NoError(t, runErr)
+	require.NoError(t, journal.Finish(runErr))
+	require.NoError(t, ttp.RunCleanup(*execCtx))
+	assert.Equal(t, "second first: value "+token+"\ncleanup second\ncleanup first: value\n", stdout.String())
+
+	journal, err = OpenRunJournal(runsDir, journal.ID())
+	require.NoError(t, err)
+	assert.Equal(t, RunStatusSucceeded, journal.State.Status)
+	assert.False(t, journal.State.CleanupPending)
+	require.Len(t, journal.State.Steps, 2)
+	for _, step := range journal.State.Steps {
+		assert.True(t, step.CleanedUp)
+	}
+
+	// a successful run cannot be resumed again
+	ttp, execCtx, err = journal.LoadTTP(afero.NewOsFs(), &execCfg)
+	require.NoError(t, err)
+	_, err = journal.Resume(ttp, execCtx)
+	assert.Error(t, err)
+}
+
+func TestRunJournalRestore(t *testing.T) {
+	tmpDir := t.TempDir()
+	runsDir := filepath.Join(tmpDir, "runs")
+	ttpPath := filepath.Join(tmpDir, "ttp.yaml")
+	markerPath := filepath.Join(tmpDir, "marker")
+	require.NoError(t, os.WriteFile(ttpPath, []byte(journalTestTTP), 0644))
+	require.NoError(t, os.WriteFile(markerPath, []byte{}, 0644))
+	argsKvStrs := []string{"marker=" + markerPath}
+
+	var stdout bytes.Buffer
+	execCfg := TTPExecutionConfig{Stdout: &stdout, NoCleanup: true}
+	ttp, execCtx, err := LoadTTP(ttpPath, afero.NewOsFs(), &execCfg, map[string]interface{}{}, argsKvStrs)
+	require.NoError(t, err)
+	journal, err := StartRunJournal(runsDir, "test-repo//ttp.yaml", ttpPath, ttp, execCtx, argsKvStrs)
+	require.NoError(t, err)
+	require.NoError(t, ttp.Execute(*execCtx))
+	require.NoError(t, ttp.RunCleanup(*execCtx))
+	require.NoError(t, journal.Finish(nil))
+	assert.True(t, journal.State.CleanupPending)
+
+	// cleaning up the run later should clean up every step using
+	// the cleanup actions rendered at the end of the original run
+	journal, err = OpenRunJournal(runsDir, journal.ID())
+	require.NoError(t, err)
+	require.Len(t, journal.State.Steps, 2)
+	assert.Equal(t, "inline: 'echo \"cleanup first: value\"'\n", journal.State.Steps[0].RenderedCleanup)
+	journal.State.StepVars["first_out"] = "changed"
+	stdout.Reset()
+	execCfg.NoCleanup = false
+	ttp, execCtx, err = journal.LoadTTP(afero.NewOsFs(), &execCfg)
+	require.NoError(t, err)
+	require.NoError(t, journal.Restore(ttp, execCtx))
+	require.NoError(t, ttp.RunCleanup(*execCtx))
+	assert.Equal(t, "cleanup second\ncleanup first: value\n", stdout.String())
+	assert.False(t, journal.State.CleanupPending)
+
+	// steps that were cleaned up are not cleaned up again
+	stdout.Reset()
+	journal, err = OpenRunJournal(runsDir, journal.ID())
+	require.NoError(t, err)
+	ttp, execCtx, err = journal.LoadTTP(afero.NewOsFs(), &execCfg)
+	require.NoError(t, err)
+	require.NoError(t, journal.Restore(ttp, execCtx))
+	require.NoError(t, ttp.RunCleanup(*execCtx))
+	assert.Empty(t, stdout.String())
+}
+
+func TestOpenRunJournal(t *testing.T) {
+	runsDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(runsDir, "corrupt"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(runsDir, "corrupt", runStateFileName), []byte("{"), 0600))
+
+	testCases := []struct {
+		name  string
+		runID string
+	}{
+		{
+			name:  "Empty Run ID",
+			runID: "",
+		},
+		{
+			name:  "Path Traversal",
+			runID: "../etc",
+		},
+		{
+			name:  "Missing Run",
+			runID: "20250101-000000-abcdef12",
+		},
+		{
+			name:  "Corrupt State",
+			runID: "corrupt",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := OpenRunJournal(runsDir, tc.runID)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestRunJournalRestoreLoop(t *testing.T) {
+	tmpDir := t.TempDir()
+	runsDir := filepath.Join(tmpDir, "runs")
+	ttpPath := filepath.Join(tmpDir, "ttp.yaml")
+	require.NoError(t, os.WriteFile(ttpPath, []byte(`---
+name: journal_loop_test
+steps:
+  - name: items
+    inline: echo -n "{[{.Loop.Item}]} "
+    loop:
+      items: [a, b]
+    cleanup:
+      inline: echo -n "undo {[{.Loop.Item}]} "
+`), 0644))
+
+	var stdout bytes.Buffer
+	execCfg := TTPExecutionConfig{Stdout: &stdout, NoCleanup: true}
+	ttp, execCtx, err := LoadTTP(ttpPath, afero.NewOsFs(), &execCfg, map[string]interface{}{}, nil)
+	require.NoError(t, err)
+	journal, err := StartRunJournal(runsDir, "test-repo//ttp.yaml", ttpPath, ttp, execCtx, nil)
+	require.NoError(t, err)
+	require.NoError(t, ttp.Execute(*execCtx))
+	require.NoError(t, journal.Finish(nil))
+	assert.Equal(t, "a b ", stdout.String())
+
+	journal, err = OpenRunJournal(runsDir, journal.ID())
+	require.NoError(t, err)
+	stdout.Reset()
+	execCfg.NoCleanup = false
+	ttp, execCtx, err = journal.LoadTTP(afero.NewOsFs(), &execCfg)
+	require.NoError(t, err)
+	require.NoError(t, journal.Restore(ttp, execCtx))
+	require.NoError(t, ttp.RunCleanup(*execCtx))
+	assert.Equal(t, "undo b undo a ", stdout.String())
+}
+
+func TestRunJournalSecrets(t *testing.T) {
+	tmpDir := t.TempDir()
+	runsDir := filepath.Join(tmpDir, "runs")
+	ttpPath := filepath.Join(tmpDir, "ttp.yaml")
+	markerPath := filepath.Join(tmpDir, "marker")
+	require.NoError(t, os.WriteFile(ttpPath, []byte(`---
+name: journal_secret_test
+args:
+  - name: marker
+    type: path
+  - name: token
+    type: secret
+    env: TTPFORGE_JOURNAL_TEST_TOKEN
+steps:
+  - name: login
+    inline: echo -n "login {{.Args.token}}"
+    cleanup:
+      inline: echo -n " logout {{.Args.token}}"
+  - name: use
+    inline: test -f {{.Args.marker}} && echo -n " use $forge.steps.login.stdout"
+`), 0644))
+	t.Cleanup(logging.ClearSecrets)
+	argsKvStrs := []string{"marker=" + markerPath, `token=hunter2<&>`}
+
+	var stdout bytes.Buffer
+	execCfg := TTPExecutionConfig{Stdout: &stdout, NoCleanup: true}
+	ttp, execCtx, err := LoadTTP(ttpPath, afero.NewOsFs(), &execCfg, map[string]interface{}{}, argsKvStrs)
+	require.NoError(t, err)
+	journal, err := StartRunJournal(runsDir, "test-repo//ttp.yaml", ttpPath, ttp, execCtx, argsKvStrs)
+	require.NoError(t, err)
+	runErr := ttp.Execute(*execCtx)
+	require.Error(t, runErr)
+	require.NoError(t, journal.Finish(runErr))
+
+	// the secret must not be written to disk in any form
+	contents, err := os.ReadFile(filepath.Join(journal.Dir(), runStateFileName))
+	require.NoError(t, err)
+	assert.NotContains(t, string(contents), "hunter2")
+	assert.Contains(t, string(contents), secretPlaceholder("token"))
+
+	// without a source for the secret, the run cannot be reloaded
+	journal, err = OpenRunJournal(runsDir, journal.ID())
+	require.NoError(t, err)
+	_, _, err = journal.LoadTTP(afero.NewOsFs(), &execCfg)
+	require.Error(t, err)
+
+	// with the secret available again, the run resumes with its value
+	t.Setenv("TTPFORGE_JOURNAL_TEST_TOKEN", `hunter2<&>`)
+	require.NoError(t, os.WriteFile(markerPath, []byte{}, 0644))
+	journal, err = OpenRunJournal(runsDir, journal.ID())
+	require.NoError(t, err)
+	stdout.Reset()
+	execCfg.NoCleanup = false
+	ttp, execCtx, err = journal.LoadTTP(afero.NewOsFs(), &execCfg)
+	require.NoError(t, err)
+	_, err = journal.Resume(ttp, execCtx)
+	require.NoError(t, err)
+	require.NoError(t, ttp.Execute(*execCtx))
+	require.NoError(t, ttp.RunCleanup(*execCtx))
+	assert.Equal(t, ` use login hunter2<&> logout hunter2<&>`, stdout.String())
+
+	contents, err = os.ReadFile(filepath.Join(journal.Dir(), runStateFileName))
+	require.NoError(t, err)
+	assert.NotContains(t, string(contents), "hunter2")
+}
+
+func TestRunJournalShortSecrets(t *testing.T) {
+	tmpDir := t.TempDir()
+	runsDir := filepath.Join(tmpDir, "runs")
+	ttpPath := filepath.Join(tmpDir, "ttp.yaml")
+	require.NoError(t, os.WriteFile(ttpPath, []byte(`---
+name: journal_short_secret_test
+args:
+  - name: pin
+    type: secret
+    env: TTPFORGE_JOURNAL_TEST_PIN
+steps:
+  - name: unlock
+    inline: echo -n "unlock {{.Args.pin}}"
+    cleanup:
+      inline: echo -n " lock {{.Args.pin}}"
+`), 0644))
+	t.Cleanup(logging.ClearSecrets)
+	argsKvStrs := []string{"pin=0"}
+
+	var stdout bytes.Buffer
+	execCfg := TTPExecutionConfig{Stdout: &stdout, NoCleanup: true}
+	ttp, execCtx, err := LoadTTP(ttpPath, afero.NewOsFs(), &execCfg, map[string]interface{}{}, argsKvStrs)
+	require.NoError(t, err)
+	journal, err := StartRunJournal(runsDir, "test-repo//ttp.yaml", ttpPath, ttp, execCtx, argsKvStrs)
+	require.NoError(t, err)
+	require.NoError(t, ttp.Execute(*execCtx))
+	require.NoError(t, journal.Finish(nil))
+
+	// only the values that can contain the secret are masked -
+	// numbers, run IDs and timestamps are left intact
+	contents, err := os.ReadFile(filepath.Join(journal.Dir(), runStateFileName))
+	require.NoError(t, err)
+	assert.NotContains(t, string(contents), "unlock 0")
+	assert.Contains(t, string(contents), "unlock "+secretPlaceholder("pin"))
+	assert.Contains(t, string(contents), `"exit_code": 0`)
+	latest, err := LatestRunJournal(runsDir)
+	require.NoError(t, err)
+	assert.Equal(t, journal.ID(), latest.ID())
+	assert.Equal(t, journal.State.StartTime.Unix(), latest.State.StartTime.Unix())
+
+	t.Setenv("TTPFORGE_JOURNAL_TEST_PIN", "0")
+	stdout.Reset()
+	execCfg.NoCleanup = false
+	ttp, execCtx, err = latest.LoadTTP(afero.NewOsFs(), &execCfg)
+	require.NoError(t, err)
+	require.NoError(t, latest.Restore(ttp, execCtx))
+	assert.Equal(t, "unlock 0", execCtx.StepResults.ByName["unlock"].Stdout)
+	require.NoError(t, ttp.RunCleanup(*execCtx))
+	assert.Equal(t, " lock 0", stdout.String())
+}
+
+func TestPruneRunJournals(t *testing.T) {
+	testCases := []struct {
+		name           string
+		status         RunStatus
+		cleanupPending bool
+		age            time.Duration
+		expectPruned   bool
+	}{
+		{
+			name:         "Old Succeeded Run",
+			status:       RunStatusSucceeded,
+			age:          48 * time.Hour,
+			expectPruned: true,
+		},
+		{
+			name:         "Old Failed Run",
+			status:       RunStatusFailed,
+			age:          48 * time.Hour,
+			expectPruned: true,
+		},
+		{
+			name:   "Recent Run",
+			status: RunStatusSucceeded,
+			age:    time.Hour,
+		},
+		{
+			name:           "Old Run Pending Cleanup",
+			status:         RunStatusSucceeded,
+			cleanupPending: true,
+			age:            48 * time.Hour,
+		},
+		{
+			name:   "Old Interrupted Run",
+			status: RunStatusRunning,
+			age:    48 * time.Hour,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			runsDir := t.TempDir()
+			state := RunState{
+				RunID:          "test-run",
+				Status:         tc.status,
+				CleanupPending: tc.cleanupPending,
+				UpdateTime:     time.Now().Add(-tc.age),
+			}
+			contents, err := json.Marshal(state)
+			require.NoError(t, err)
+			runDir := filepath.Join(runsDir, state.RunID)
+			require.NoError(t, os.Mkdir(runDir, 0700))
+			require.NoError(t, os.WriteFile(filepath.Join(runDir, runStateFileName), contents, 0600))
+
+			pruned, err := PruneRunJournals(runsDir, 24*time.Hour)
+			require.NoError(t, err)
+			_, statErr := os.Stat(runDir)
+			if tc.expectPruned {
+				assert.Equal(t, 1, pruned)
+				assert.True(t, os.IsNotExist(statErr))
+			} else {
+				assert.Equal(t, 0, pruned)
+				assert.NoError(t, statErr)
+			}
+		})
+	}
+}
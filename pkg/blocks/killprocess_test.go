@@ -34,7 +34,7 @@ func TestKillProcessExecute(t *testing.T) {
 		name                string
 		description         string
 		step                *KillProcessStep
-		stepVars            map[string]string
+		stepVars            map[string]interface{}
 		createProcess       bool
 		expectTemplateError bool
 		expectExecuteError  bool
@@ -140,7 +140,7 @@ func TestKillProcessExecute(t *testing.T) {
 				ProcessName:               "ping",
 				ErrorOnFindProcessFailure: true,
 			},
-			stepVars: map[string]string{
+			stepVars: map[string]interface{}{
 				"pid": "123456789",
 			},
 			expectTemplateError: false,
@@ -155,7 +155,7 @@ func TestKillProcessExecute(t *testing.T) {
 				ProcessName:               "{[{.StepVars.processName}]}",
 				ErrorOnFindProcessFailure: true,
 			},
-			stepVars: map[string]string{
+			stepVars: map[string]interface{}{
 				"processName": "touch",
 			},
 			expectTemplateError: false,
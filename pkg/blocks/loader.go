@@ -82,6 +82,7 @@ func RenderTemplatedTTP(ttpStr string, rp RenderParameters) (*TTP, error) {
 		logging.DividerThin()
 		return nil, err
 	}
+	ttp.rendered = result.Bytes()
 	return &ttp, nil
 }
 
@@ -98,16 +99,47 @@ func RenderTemplatedTTP(ttpStr string, rp RenderParameters) (*TTP, error) {
 // *TTP: Pointer to the created TTP instance, or nil if the file is empty or invalid.
 // TTPExecutionContext: the initialized TTPExecutionContext suitable for passing to TTP.Execute(...)
 // err: An error if the file contains invalid data or cannot be read.
-func LoadTTP(ttpFilePath string, fsys afero.Fs, execCfg *TTPExecutionConfig, stepVars map[string]string, argsKvStrs []string) (*TTP, *TTPExecutionContext, error) {
+func LoadTTP(ttpFilePath string, fsys afero.Fs, execCfg *TTPExecutionConfig, stepVars map[string]interface{}, argsKvStrs []string) (*TTP, *TTPExecutionContext, error) {
 	ttpBytes, err := readTTPBytes(ttpFilePath, fsys)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	result, err := preprocess.Parse(ttpBytes)
+	// CLI path args resolve relative to where ttpforge was executed (current directory)
+	cliDir, err := os.Getwd()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	argValues, err := parseTTPArgs(ttpBytes, ttpFilePath, argsKvStrs, cliDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rp := RenderParameters{
+		Args:     argValues,
+		Platform: platforms.GetCurrentPlatformSpec(),
+	}
+	ttp, err := RenderTemplatedTTP(string(ttpBytes), rp)
 	if err != nil {
 		return nil, nil, err
 	}
+	if execCfg.Strict {
+		if err := CheckUnknownKeys(ttpFilePath, ttpBytes, ttp.rendered); err != nil {
+			return nil, nil, err
+		}
+	}
+	return prepareTTP(ttp, ttpFilePath, fsys, execCfg, stepVars, argValues)
+}
+
+// parseTTPArgs parses the argument values provided for a TTP
+// and validates them against the argument specifications
+// in the preamble of the TTP
+func parseTTPArgs(ttpBytes []byte, ttpFilePath string, argsKvStrs []string, cliDir string) (map[string]interface{}, error) {
+	result, err := preprocess.Parse(ttpBytes)
+	if err != nil {
+		return nil, err
+	}
 
 	// linting above establishes that the TTP yaml will be
 	// compatible with our rendering process
@@ -117,38 +149,39 @@ func LoadTTP(ttpFilePath string, fsys afero.Fs, execCfg *TTPExecutionConfig, ste
 	var tmpContainer ArgSpecContainer
 	err = yaml.Unmarshal(result.PreambleBytes, &tmpContainer)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to unmarshal YAML preamble section: %w", err)
+		return nil, fmt.Errorf("failed to unmarshal YAML preamble section: %w", err)
 	}
 
 	// Get directories for resolving path-type arguments
-	// - CLI path args resolve relative to where ttpforge was executed (current directory)
+	// - CLI path args resolve relative to cliDir
 	// - Default path values resolve relative to where the YAML file is located
-	cliDir, err := os.Getwd()
-	if err != nil {
-		return nil, nil, err
-	}
-
 	absPath, err := filepath.Abs(ttpFilePath)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 	ttpDir := filepath.Dir(absPath)
 
 	// Parse and validate arguments
 	argValues, err := args.ParseAndValidate(tmpContainer.ArgSpecs, argsKvStrs, cliDir, ttpDir)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to parse and validate arguments: %w", err)
+		return nil, fmt.Errorf("failed to parse and validate arguments: %w", err)
 	}
 
-	rp := RenderParameters{
-		Args:     argValues,
-		Platform: platforms.GetCurrentPlatformSpec(),
-	}
-	ttp, err := RenderTemplatedTTP(string(ttpBytes), rp)
-	if err != nil {
-		return nil, nil, err
+	// mask the values of secret arguments in all subsequent logs
+	for _, spec := range tmpContainer.ArgSpecs {
+		if val, ok := argValues[spec.Name].(string); ok && spec.IsSecret() {
+			if val != "" && len(val) < logging.MinSecretLength {
+				logging.L().Warnf("The value of secret argument %v is shorter than %d characters, so any text that contains it will also be masked in logs, reports and run journals", spec.Name, logging.MinSecretLength)
+			}
+			logging.RegisterSecret(val)
+		}
 	}
+	return argValues, nil
+}
 
+// prepareTTP sets up the working directory and execution
+// context of a rendered TTP and then validates it
+func prepareTTP(ttp *TTP, ttpFilePath string, fsys afero.Fs, execCfg *TTPExecutionConfig, stepVars map[string]interface{}, argValues map[string]interface{}) (*TTP, *TTPExecutionContext, error) {
 	// embedded fs has no notion of workdirs
 	// so we should only set workdir to the TTP's directory
 	// if we are using an OsFs
@@ -169,11 +202,17 @@ func LoadTTP(ttpFilePath string, fsys afero.Fs, execCfg *TTPExecutionConfig, ste
 
 	execCtx := NewTTPExecutionContext()
 	execCtx.Cfg = *execCfg
+	env, err := ttp.buildEnvironment(execCfg.Environment, fsys, filepath.Dir(ttpFilePath))
+	if err != nil {
+		return nil, nil, err
+	}
+	execCtx.Cfg.Environment = env
 	execCtx.Vars.WorkDir = ttp.WorkDir
 	execCtx.Vars.StepVars = stepVars
+	execCtx.Vars.Args = argValues
+	execCtx.Vars.Platform = platforms.GetCurrentPlatformSpec()
 
-	err = ttp.Validate(execCtx)
-	if err != nil {
+	if err := ttp.Validate(execCtx); err != nil {
 		return nil, nil, err
 	}
 	return ttp, &execCtx, nil
@@ -0,0 +1,378 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package blocks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/facebookincubator/ttpforge/pkg/logging"
+	"gopkg.in/yaml.v3"
+)
+
+// LoopSpec describes the values over which a step is repeated.
+// Exactly one of Items, Range and FromJSON must be specified.
+//
+// **Attributes:**
+//
+// Items: A literal list of values.
+// Range: A range of integers.
+// FromJSON: An expression (such as `$forge.steps.foo.stdout`)
+// that evaluates to a JSON array at run time.
+type LoopSpec struct {
+	Items    []interface{} `yaml:"items,omitempty"`
+	Range    *LoopRange    `yaml:"range,omitempty"`
+	FromJSON string        `yaml:"from_json,omitempty"`
+}
+
+// LoopRange is a range of integers from Start (inclusive)
+// to End (exclusive), in increments of Step (default 1)
+type LoopRange struct {
+	Start int `yaml:"start,omitempty"`
+	End   int `yaml:"end"`
+	Step  int `yaml:"step,omitempty"`
+}
+
+// LoopVars exposes the current iteration of a
+// loop to step templates as `{[{.Loop.Item}]}`
+// and `{[{.Loop.Index}]}`
+type LoopVars struct {
+	Item  interface{} `json:"item"`
+	Index int         `json:"index"`
+}
+
+// UnmarshalYAML accepts the shorthand forms of a loop
+// specification: a list of items, or a string that is
+// evaluated as a JSON array (like `from_json`)
+func (l *LoopSpec) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.SequenceNode:
+		l.Items = []interface{}{}
+		return node.Decode(&l.Items)
+	case yaml.ScalarNode:
+		return node.Decode(&l.FromJSON)
+	}
+	type rawLoopSpec LoopSpec
+	return node.Decode((*rawLoopSpec)(l))
+}
+
+// UnmarshalYAML accepts a single integer as
+// shorthand for the range from 0 to that integer
+func (r *LoopRange) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&r.End)
+	}
+	type rawLoopRange LoopRange
+	return node.Decode((*rawLoopRange)(r))
+}
+
+// Validate checks that exactly one source of items is specified
+func (l *LoopSpec) Validate() error {
+	specified := 0
+	if l.Items != nil {
+		specified++
+	}
+	if l.Range != nil {
+		specified++
+	}
+	if l.FromJSON != "" {
+		specified++
+	}
+	if specified != 1 {
+		return errors.New("loop must specify exactly one of items, range or from_json")
+	}
+	return nil
+}
+
+// resolveItems returns the items over which to loop, evaluating
+// any step templates and step output references at run time
+func (l *LoopSpec) resolveItems(execCtx TTPExecutionContext) ([]interface{}, error) {
+	switch {
+	case l.Items != nil:
+		items := make([]interface{}, len(l.Items))
+		for idx, item := range l.Items {
+			items[idx] = item
+			if s, ok := item.(string); ok && execCtx.containsStepTemplating(s) {
+				templated, err := execCtx.templateStep(s)
+				if err != nil {
+					return nil, err
+				}
+				items[idx] = templated
+			}
+		}
+		return items, nil
+	case l.Range != nil:
+		step := l.Range.Step
+		if step == 0 {
+			step = 1
+		}
+		var items []interface{}
+		for i := l.Range.Start; (step > 0 && i < l.Range.End) || (step < 0 && i > l.Range.End); i += step {
+			items = append(items, i)
+		}
+		return items, nil
+	default:
+		expanded, err := execCtx.ExpandVariables([]string{l.FromJSON})
+		if err != nil {
+			return nil, err
+		}
+		value := expanded[0]
+		if execCtx.containsStepTemplating(value) {
+			value, err = execCtx.templateStep(value)
+			if err != nil {
+				return nil, err
+			}
+		}
+		var items []interface{}
+		if err := json.Unmarshal([]byte(value), &items); err != nil {
+			return nil, fmt.Errorf("loop from_json value is not a JSON array: %w", err)
+		}
+		return items, nil
+	}
+}
+
+// withLoop returns a copy of the execution context that exposes
+// the provided loop iteration to step templates. The step variables
+// are shared so that `outputvar` values set by iterations remain
+// visible to subsequent steps.
+func (c TTPExecutionContext) withLoop(loop *LoopVars) TTPExecutionContext {
+	vars := *c.Vars
+	vars.Loop = loop
+	c.Vars = &vars
+	return c
+}
+
+// unmarshalLoop sets up a step that has a `loop:` or `for_each:`
+// modifier. The step is executed as a loopAction that creates one
+// step from the remaining fields of node for every item of the loop.
+// The `if:` condition of the step is evaluated once before the loop,
+// whereas checks, retries, timeouts and cleanup apply to each iteration.
+func (s *Step) unmarshalLoop(node *yaml.Node) error {
+	spec := s.Loop
+	if s.ForEach != nil {
+		if spec != nil {
+			return fmt.Errorf("step %q cannot specify both loop and for_each", s.Name)
+		}
+		spec = s.ForEach
+	}
+
+	iterationNode := &yaml.Node{
+		Kind:   node.Kind,
+		Tag:    node.Tag,
+		Line:   node.Line,
+		Column: node.Column,
+	}
+	for idx := 0; idx+1 < len(node.Content); idx += 2 {
+		switch node.Content[idx].Value {
+		case "loop", "for_each", "if":
+			continue
+		}
+		iterationNode.Content = append(iterationNode.Content, node.Content[idx], node.Content[idx+1])
+	}
+
+	// decode one iteration right away so that
+	// errors in the step are reported when it is loaded
+	var prototype Step
+	if err := iterationNode.Decode(&prototype); err != nil {
+		return err
+	}
+
+	s.action = &loopAction{
+		spec:      spec,
+		name:      s.Name,
+		node:      iterationNode,
+		prototype: &prototype,
+	}
+	s.cleanup = s.action.GetDefaultCleanupAction()
+	s.Checks = nil
+	s.Retry = nil
+	s.Timeout = 0
+	s.CleanupSpec = yaml.Node{}
+	return nil
+}
+
+// loopAction executes a step once for each item of its loop. Each
+// iteration is a separate step named `<name>_<index>`, with its
+// own result, checks and cleanup.
+type loopAction struct {
+	actionDefaults
+	spec      *LoopSpec
+	name      string
+	node      *yaml.Node
+	prototype *Step
+
+	iterations []*Step
+	results    []*ExecutionResult
+}
+
+// Validate checks the loop specification and the step that is repeated
+func (l *loopAction) Validate(execCtx TTPExecutionContext) error {
+	if err := l.spec.Validate(); err != nil {
+		return fmt.Errorf("step %q: %w", l.name, err)
+	}
+	return l.prototype.Validate(execCtx)
+}
+
+// Template is a no-op - each iteration is
+// templated immediately before it is executed
+func (l *loopAction) Template(_ TTPExecutionContext) error {
+	return nil
+}
+
+// Execute runs one iteration of the step for each item of the
+// loop, in order, stopping at the first iteration that fails
+//
+// **Parameters:**
+//
+// execCtx: The current TTPExecutionContext
+//
+// **Returns:**
+//
+// *ActResult: the combined output of all iterations
+// error: an error if the items could not be resolved or an iteration failed
+func (l *loopAction) Execute(execCtx TTPExecutionContext) (*ActResult, error) {
+	items, err := l.spec.resolveItems(execCtx)
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve loop items of step %q: %w", l.name, err)
+	}
+	logging.L().Infof("[*] Looping over %d items", len(items))
+
+	l.iterations = nil
+	l.results = nil
+	var actResults []*ActResult
+	for idx, item := range items {
+		iterationCtx := execCtx.withLoop(&LoopVars{Item: item, Index: idx})
+		iteration, err := l.newIteration(iterationCtx)
+		if err != nil {
+			return aggregateResults(actResults), err
+		}
+		l.iterations = append(l.iterations, iteration)
+
+		result, err := runChild(iteration, iterationCtx)
+		result.Loop = iterationCtx.Vars.Loop
+		l.results = append(l.results, result)
+		// inside a parallel step, this is the loop's own copy of the
+		// step results, which is merged once the whole group finishes
+		execCtx.StepResults.ByName[result.Name] = result
+		actResults = append(actResults, &result.ActResult)
+		if err != nil {
+			return aggregateResults(actResults), fmt.Errorf("loop iteration %d failed: %w", idx, err)
+		}
+	}
+	logging.L().Info("[*] Completed all loop iterations")
+	return aggregateResults(actResults), nil
+}
+
+// newIteration creates the step for the loop
+// iteration exposed by the provided context
+func (l *loopAction) newIteration(execCtx TTPExecutionContext) (*Step, error) {
+	var iteration Step
+	if err := l.node.Decode(&iteration); err != nil {
+		return nil, err
+	}
+	iteration.Name = fmt.Sprintf("%s_%d", l.name, execCtx.Vars.Loop.Index)
+	if err := iteration.Validate(execCtx); err != nil {
+		return nil, fmt.Errorf("invalid loop iteration %q: %w", iteration.Name, err)
+	}
+	return &iteration, nil
+}
+
+// ChildResults returns the execution results of
+// the iterations of the loop, in order
+func (l *loopAction) ChildResults() []*ExecutionResult {
+	return l.results
+}
+
+// restoreIterations recreates the iterations of the loop
+// from their results so that they can be cleaned up
+func (l *loopAction) restoreIterations(execCtx TTPExecutionContext, results []*ExecutionResult) {
+	l.iterations = nil
+	l.results = nil
+	for _, result := range results {
+		if result == nil || result.Loop == nil {
+			break
+		}
+		iteration, err := l.newIteration(execCtx.withLoop(result.Loop))
+		if err != nil {
+			logging.L().Warnf("Could not restore loop iteration %q: %v", result.Name, err)
+			break
+		}
+		l.iterations = append(l.iterations, iteration)
+		l.results = append(l.results, result)
+		execCtx.StepResults.ByName[result.Name] = result
+		iteration.restoreResult(execCtx.withLoop(result.Loop), result)
+	}
+}
+
+// GetDefaultCleanupAction will instruct the calling code
+// to cleanup all completed iterations in reverse order
+func (l *loopAction) GetDefaultCleanupAction() Action {
+	return &loopCleanupAction{
+		loop: l,
+	}
+}
+
+// loopCleanupAction cleans up the iterations
+// of a loop in reverse order
+type loopCleanupAction struct {
+	actionDefaults
+	loop *loopAction
+}
+
+// Validate is not needed here, as this is not a user-accessible step type
+func (a *loopCleanupAction) Validate(_ TTPExecutionContext) error {
+	return nil
+}
+
+// Template is not needed here, as this is not a user-accessible step type
+func (a *loopCleanupAction) Template(_ TTPExecutionContext) error {
+	return nil
+}
+
+// Execute cleans up every iteration that needs
+// cleanup, starting from the last iteration
+func (a *loopCleanupAction) Execute(execCtx TTPExecutionContext) (*ActResult, error) {
+	results := a.loop.results
+	cleanupResults := make([]*ActResult, len(results))
+	var errs []error
+	logging.IncreaseIndentLevel()
+	for idx := len(results) - 1; idx >= 0; idx-- {
+		result := results[idx]
+		if !result.NeedsCleanup() {
+			continue
+		}
+		iteration := a.loop.iterations[idx]
+		logging.L().Infof("Cleaning Up Loop Iteration %q", iteration.Name)
+		cleanupResult, err := iteration.Cleanup(execCtx.withLoop(result.Loop))
+		cleanupResults[idx] = cleanupResult
+		result.Cleanup = cleanupResult
+		if err != nil {
+			result.CleanupError = err.Error()
+			logging.L().Errorf("error cleaning up loop iteration %q: %v", iteration.Name, err)
+			errs = append(errs, err)
+			continue
+		}
+		result.CleanupError = ""
+		result.CleanedUp = true
+	}
+	logging.DecreaseIndentLevel()
+	return aggregateResults(cleanupResults), errors.Join(errs...)
+}
@@ -0,0 +1,339 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package blocks
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestLoopSpecUnmarshal(t *testing.T) {
+	testCases := []struct {
+		name         string
+		content      string
+		expectedSpec LoopSpec
+		wantError    bool
+	}{
+		{
+			name:    "Items",
+			content: `loop: {items: [a, 1]}`,
+			expectedSpec: LoopSpec{
+				Items: []interface{}{"a", 1},
+			},
+		},
+		{
+			name:    "Items Shorthand",
+			content: `loop: [a, b]`,
+			expectedSpec: LoopSpec{
+				Items: []interface{}{"a", "b"},
+			},
+		},
+		{
+			name:    "Range",
+			content: `loop: {range: {start: 1, end: 10, step: 3}}`,
+			expectedSpec: LoopSpec{
+				Range: &LoopRange{Start: 1, End: 10, Step: 3},
+			},
+		},
+		{
+			name:    "Range Shorthand",
+			content: `loop: {range: 3}`,
+			expectedSpec: LoopSpec{
+				Range: &LoopRange{End: 3},
+			},
+		},
+		{
+			name:    "From JSON Shorthand",
+			content: `loop: $forge.steps.list.stdout`,
+			expectedSpec: LoopSpec{
+				FromJSON: "$forge.steps.list.stdout",
+			},
+		},
+		{
+			name:      "Invalid Range",
+			content:   `loop: {range: {end: many}}`,
+			wantError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var container struct {
+				Loop LoopSpec `yaml:"loop"`
+			}
+			err := yaml.Unmarshal([]byte(tc.content), &container)
+			if tc.wantError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedSpec, container.Loop)
+		})
+	}
+}
+
+func TestLoopSpecResolveItems(t *testing.T) {
+	testCases := []struct {
+		name          string
+		spec          LoopSpec
+		stepVars      map[string]interface{}
+		expectedItems []interface{}
+		wantError     bool
+	}{
+		{
+			name: "Templated Items",
+			spec: LoopSpec{
+				Items: []interface{}{"{[{.StepVars.host}]}", 2},
+			},
+			stepVars:      map[string]interface{}{"host": "example.com"},
+			expectedItems: []interface{}{"example.com", 2},
+		},
+		{
+			name: "Range",
+			spec: LoopSpec{
+				Range: &LoopRange{Start: 1, End: 4},
+			},
+			expectedItems: []interface{}{1, 2, 3},
+		},
+		{
+			name: "Descending Range",
+			spec: LoopSpec{
+				Range: &LoopRange{Start: 6, End: 0, Step: -3},
+			},
+			expectedItems: []interface{}{6, 3},
+		},
+		{
+			name: "From JSON",
+			spec: LoopSpec{
+				FromJSON: "{[{.StepVars.hosts}]}",
+			},
+			stepVars:      map[string]interface{}{"hosts": `["a", {"name": "b"}]`},
+			expectedItems: []interface{}{"a", map[string]interface{}{"name": "b"}},
+		},
+		{
+			name: "From JSON Not An Array",
+			spec: LoopSpec{
+				FromJSON: "{[{.StepVars.hosts}]}",
+			},
+			stepVars:  map[string]interface{}{"hosts": `{"name": "b"}`},
+			wantError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			execCtx := NewTTPExecutionContext()
+			if tc.stepVars != nil {
+				execCtx.Vars.StepVars = tc.stepVars
+			}
+			items, err := tc.spec.resolveItems(execCtx)
+			if tc.wantError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedItems, items)
+		})
+	}
+}
+
+func TestLoopSteps(t *testing.T) {
+	testCases := []struct {
+		name               string
+		content            string
+		expectedStatuses   map[string]StepStatus
+		expectedStdout     string
+		wantValidateError  bool
+		expectExecuteError bool
+	}{
+		{
+			name: "Loop Over Items",
+			content: `name: test
+steps:
+  - name: greet
+    inline: echo -n "hello {[{.Loop.Item}]}({[{.Loop.Index}]}) "
+    loop:
+      items: [alice, bob]
+    cleanup:
+      inline: echo -n "bye {[{.Loop.Item}]} "`,
+			expectedStatuses: map[string]StepStatus{
+				"greet":   StepStatusSucceeded,
+				"greet_0": StepStatusSucceeded,
+				"greet_1": StepStatusSucceeded,
+			},
+			expectedStdout: "hello alice(0) hello bob(1) bye bob bye alice ",
+		},
+		{
+			name: "For Each Over Step Output",
+			content: `name: test
+steps:
+  - name: list
+    inline: echo '[{"name":"a"},{"name":"b"}]'
+  - name: visit
+    inline: echo -n "{[{.Loop.Item.name}]} "
+    for_each: $forge.steps.list.stdout
+  - name: after
+    inline: echo -n "$forge.steps.visit_1.stdout"`,
+			expectedStatuses: map[string]StepStatus{
+				"list":    StepStatusSucceeded,
+				"visit":   StepStatusSucceeded,
+				"visit_0": StepStatusSucceeded,
+				"visit_1": StepStatusSucceeded,
+				"after":   StepStatusSucceeded,
+			},
+			expectedStdout: "[{\"name\":\"a\"},{\"name\":\"b\"}]\na b b ",
+		},
+		{
+			name: "For Each Over Typed Output",
+			content: `name: test
+steps:
+  - name: list
+    inline: printf '101\n202\n'
+    outputs:
+      pids:
+        type: lines
+  - name: visit
+    inline: echo -n "{[{.Loop.Item}]} "
+    for_each: $forge.steps.list.outputs.pids
+  - name: after
+    inline: echo -n "{[{len .Steps.list.Outputs.pids}]} $forge.steps.list.outputs.pids.1"`,
+			expectedStatuses: map[string]StepStatus{
+				"list":    StepStatusSucceeded,
+				"visit":   StepStatusSucceeded,
+				"visit_0": StepStatusSucceeded,
+				"visit_1": StepStatusSucceeded,
+				"after":   StepStatusSucceeded,
+			},
+			expectedStdout: "101\n202\n101 202 2 202",
+		},
+		{
+			name: "Failing Iteration",
+			content: `name: test
+steps:
+  - name: count
+    inline: test {[{.Loop.Item}]} -lt 1 && echo -n "{[{.Loop.Item}]} "
+    loop:
+      range: 3
+    cleanup:
+      inline: echo -n "undo {[{.Loop.Item}]} "`,
+			expectedStatuses: map[string]StepStatus{
+				"count":   StepStatusFailed,
+				"count_0": StepStatusSucceeded,
+				"count_1": StepStatusFailed,
+			},
+			expectedStdout:     "0 undo 0 ",
+			expectExecuteError: true,
+		},
+		{
+			name: "Loop And For Each",
+			content: `name: test
+steps:
+  - name: both
+    inline: echo nope
+    loop: [a]
+    for_each: [b]`,
+			wantValidateError: true,
+		},
+		{
+			name: "Empty Loop",
+			content: `name: test
+steps:
+  - name: empty
+    inline: echo nope
+    loop: {}`,
+			wantValidateError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var ttp TTP
+			err := yaml.Unmarshal([]byte(tc.content), &ttp)
+			execCtx := NewTTPExecutionContext()
+			if err == nil {
+				err = ttp.Validate(execCtx)
+			}
+			if tc.wantValidateError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			var stdout bytes.Buffer
+			execCtx.Cfg.Stdout = &stdout
+			err = ttp.Execute(execCtx)
+			if tc.expectExecuteError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.NoError(t, ttp.RunCleanup(execCtx))
+
+			assert.Equal(t, tc.expectedStdout, stdout.String())
+			for name, status := range tc.expectedStatuses {
+				result, ok := execCtx.StepResults.ByName[name]
+				require.True(t, ok, "no result for step %v", name)
+				assert.Equal(t, status, result.Status, "status of step %v", name)
+			}
+		})
+	}
+}
+
+// TestLoopInParallel runs looped steps concurrently - run it with
+// -race to check that their iterations record their results safely
+func TestLoopInParallel(t *testing.T) {
+	content := `name: test
+steps:
+  - name: group
+    parallel:
+      - name: a
+        print_str: "a{[{.Loop.Item}]}"
+        loop:
+          range: 200
+      - name: b
+        print_str: "b{[{.Loop.Item}]}"
+        loop:
+          range: 200
+  - name: after
+    inline: echo -n "$forge.steps.a_199.stdout $forge.steps.b_0.stdout"`
+	var ttp TTP
+	require.NoError(t, yaml.Unmarshal([]byte(content), &ttp))
+	execCtx := NewTTPExecutionContext()
+	require.NoError(t, ttp.Validate(execCtx))
+
+	var stdout bytes.Buffer
+	execCtx.Cfg.Stdout = &stdout
+	require.NoError(t, ttp.Execute(execCtx))
+	require.NoError(t, ttp.RunCleanup(execCtx))
+
+	for _, name := range []string{"a", "b"} {
+		for idx := 0; idx < 200; idx++ {
+			result, ok := execCtx.StepResults.ByName[fmt.Sprintf("%s_%d", name, idx)]
+			require.True(t, ok, "no result for iteration %d of step %v", idx, name)
+			assert.Equal(t, StepStatusSucceeded, result.Status)
+		}
+	}
+	assert.Equal(t, "a199\n b0\n", execCtx.StepResults.ByName["after"].Stdout)
+}
@@ -0,0 +1,325 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package blocks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/facebookincubator/ttpforge/pkg/logging"
+)
+
+// ParallelStep runs a group of child steps concurrently.
+// Each child is a regular step with its own name, checks
+// and cleanup; the cleanup of the group as a whole runs the
+// cleanup of each child in reverse order of declaration.
+type ParallelStep struct {
+	actionDefaults `yaml:",inline"`
+	Steps          []Step `yaml:"parallel"`
+	MaxConcurrency int    `yaml:"max_concurrency,omitempty"`
+
+	results []*ExecutionResult
+}
+
+// NewParallelStep creates a new ParallelStep instance and returns a pointer to it.
+func NewParallelStep() *ParallelStep {
+	return &ParallelStep{}
+}
+
+// IsNil checks if the step is nil or empty and returns a boolean value.
+func (p *ParallelStep) IsNil() bool {
+	return len(p.Steps) == 0
+}
+
+// Validate checks that the child steps are valid and can safely run concurrently
+//
+// **Returns:**
+//
+// error: error if validation fails, nil otherwise
+func (p *ParallelStep) Validate(execCtx TTPExecutionContext) error {
+	if len(p.Steps) == 0 {
+		return errors.New("parallel must contain at least one step")
+	}
+	if p.MaxConcurrency < 0 {
+		return fmt.Errorf("max_concurrency must be non-negative, got %d", p.MaxConcurrency)
+	}
+	names := make(map[string]bool)
+	for idx := range p.Steps {
+		child := &p.Steps[idx]
+		if names[child.Name] {
+			return fmt.Errorf("duplicate step name %q in parallel group", child.Name)
+		}
+		names[child.Name] = true
+
+		// these actions change process-wide state (the working directory)
+		// and therefore cannot run concurrently with other steps
+		action := child.action
+		if loop, ok := action.(*loopAction); ok {
+			action = loop.prototype.action
+		}
+		switch action.(type) {
+		case *SubTTPStep, *ChangeDirectoryStep:
+			return fmt.Errorf("step %q: %v actions cannot be used inside parallel", child.Name, child.ActionType())
+		}
+		if err := child.Validate(execCtx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Template is a no-op - each child step is templated
+// immediately before it is executed
+func (p *ParallelStep) Template(_ TTPExecutionContext) error {
+	return nil
+}
+
+// Execute runs all child steps concurrently and waits for them to finish.
+// A failing child does not interrupt its siblings; once every child
+// has finished, the errors of all failed children are returned together.
+//
+// **Parameters:**
+//
+// execCtx: The current TTPExecutionContext
+//
+// **Returns:**
+//
+// *ActResult: the combined output of all child steps
+// error: an error if any child step failed
+func (p *ParallelStep) Execute(execCtx TTPExecutionContext) (*ActResult, error) {
+	logging.L().Infof("[*] Executing %d steps in parallel", len(p.Steps))
+
+	limit := p.MaxConcurrency
+	if limit == 0 || limit > len(p.Steps) {
+		limit = len(p.Steps)
+	}
+	sem := make(chan struct{}, limit)
+
+	// children write to the same output streams
+	var outMu sync.Mutex
+	if execCtx.Cfg.Stdout != nil {
+		execCtx.Cfg.Stdout = &lockedWriter{mu: &outMu, w: execCtx.Cfg.Stdout}
+	}
+	if execCtx.Cfg.Stderr != nil {
+		execCtx.Cfg.Stderr = &lockedWriter{mu: &outMu, w: execCtx.Cfg.Stderr}
+	}
+
+	p.results = make([]*ExecutionResult, len(p.Steps))
+	childVars := make([]*TTPExecutionVars, len(p.Steps))
+	childStepResults := make([]*StepResultsRecord, len(p.Steps))
+	errs := make([]error, len(p.Steps))
+	var wg sync.WaitGroup
+	for idx := range p.Steps {
+		// every child gets its own copy of the step variables and
+		// step results so that concurrent `outputvar` writes and
+		// the results recorded by nested steps (such as loop
+		// iterations) cannot race
+		childCtx := execCtx
+		childCtx.Vars = execCtx.Vars.copy()
+		childVars[idx] = childCtx.Vars
+		childCtx.StepResults = execCtx.StepResults.copy()
+		childStepResults[idx] = childCtx.StepResults
+
+		wg.Add(1)
+		go func(idx int, childCtx TTPExecutionContext) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			p.results[idx], errs[idx] = runChild(&p.Steps[idx], childCtx)
+		}(idx, childCtx)
+	}
+	wg.Wait()
+
+	// merge child state back into the parent context in declaration order
+	var childResults []*ActResult
+	for idx, result := range p.results {
+		for k, v := range childVars[idx].StepVars {
+			execCtx.Vars.StepVars[k] = v
+		}
+		for name, nested := range childStepResults[idx].ByName {
+			execCtx.StepResults.ByName[name] = nested
+		}
+		execCtx.StepResults.ByName[result.Name] = result
+		childResults = append(childResults, &result.ActResult)
+	}
+
+	combined := aggregateResults(childResults)
+	if err := errors.Join(errs...); err != nil {
+		return combined, fmt.Errorf("parallel step failed: %w", err)
+	}
+	logging.L().Info("[*] Completed all parallel steps")
+	return combined, nil
+}
+
+// runChild executes a step nested inside a parallel or loop step,
+// including its condition, retries, timeout and checks
+func runChild(child *Step, execCtx TTPExecutionContext) (*ExecutionResult, error) {
+	logging.L().Infof("Starting step %q", child.Name)
+	result := &ExecutionResult{
+		Name:       child.Name,
+		ActionType: child.ActionType(),
+		StartTime:  time.Now(),
+	}
+	defer func() {
+		result.EndTime = time.Now()
+	}()
+
+	shouldRun, err := child.ShouldRun(execCtx)
+	if err == nil && !shouldRun {
+		logging.L().Infof("Skipping step %q because its condition is false: %v", child.Name, child.If)
+		result.Status = StepStatusSkipped
+		return result, nil
+	}
+	if err != nil {
+		result.Status = StepStatusFailed
+		result.Error = err.Error()
+		result.ExitCode = exitCodeFromError(err)
+		return result, err
+	}
+
+	var childErr error
+	child.Retry.runAttempts(execCtx, child.Name, result, func() bool {
+		attemptCtx, cancel := child.contextWithTimeout(execCtx)
+		defer cancel()
+		childErr = runChildAttempt(child, attemptCtx, result)
+		return false
+	})
+	if childErr != nil {
+		return result, fmt.Errorf("step %q: %w", child.Name, childErr)
+	}
+	logging.L().Infof("Finished step %q", child.Name)
+	return result, nil
+}
+
+// runChildAttempt executes a child step (and its checks) once
+func runChildAttempt(child *Step, execCtx TTPExecutionContext, result *ExecutionResult) error {
+	result.ActResult = ActResult{}
+	result.Error = ""
+	result.ExitCode = 0
+	result.Checks = nil
+
+	err := child.Template(execCtx)
+	if err == nil {
+		var actResult *ActResult
+		actResult, err = child.Execute(execCtx)
+		if actResult != nil {
+			result.ActResult = *actResult
+		}
+	}
+	if err != nil {
+		result.Status = StepStatusFailed
+		if execCtx.Context().Err() == context.DeadlineExceeded {
+			result.Status = StepStatusTimeout
+			err = fmt.Errorf("timed out: %w", err)
+		}
+		result.Error = err.Error()
+		result.ExitCode = exitCodeFromError(err)
+		return err
+	}
+	result.Status = StepStatusSucceeded
+
+	if !execCtx.Cfg.NoChecks {
+		result.Checks, err = child.VerifyChecks()
+		if err != nil {
+			result.Status = StepStatusChecksFailed
+			result.Error = err.Error()
+			return err
+		}
+	}
+	return nil
+}
+
+// ChildResults returns the execution results of the
+// child steps, in declaration order. It returns nil if
+// the step has not yet been executed.
+func (p *ParallelStep) ChildResults() []*ExecutionResult {
+	return p.results
+}
+
+// GetDefaultCleanupAction will instruct the calling code
+// to cleanup all completed child steps in reverse order
+func (p *ParallelStep) GetDefaultCleanupAction() Action {
+	return &parallelCleanupAction{
+		step: p,
+	}
+}
+
+// parallelCleanupAction cleans up the child
+// steps of a ParallelStep in reverse order
+type parallelCleanupAction struct {
+	actionDefaults
+	step *ParallelStep
+}
+
+// Validate is not needed here, as this is not a user-accessible step type
+func (a *parallelCleanupAction) Validate(_ TTPExecutionContext) error {
+	return nil
+}
+
+// Template is not needed here, as this is not a user-accessible step type
+func (a *parallelCleanupAction) Template(_ TTPExecutionContext) error {
+	return nil
+}
+
+// Execute cleans up every child step that needs
+// cleanup, starting from the last declared step
+func (a *parallelCleanupAction) Execute(execCtx TTPExecutionContext) (*ActResult, error) {
+	results := a.step.results
+	cleanupResults := make([]*ActResult, len(results))
+	var errs []error
+	logging.IncreaseIndentLevel()
+	for idx := len(results) - 1; idx >= 0; idx-- {
+		result := results[idx]
+		if result == nil || !result.NeedsCleanup() {
+			continue
+		}
+		child := &a.step.Steps[idx]
+		logging.L().Infof("Cleaning Up Parallel Step %q", child.Name)
+		cleanupResult, err := child.Cleanup(execCtx)
+		cleanupResults[idx] = cleanupResult
+		result.Cleanup = cleanupResult
+		if err != nil {
+			result.CleanupError = err.Error()
+			logging.L().Errorf("error cleaning up parallel step %q: %v", child.Name, err)
+			errs = append(errs, err)
+			continue
+		}
+		result.CleanupError = ""
+		result.CleanedUp = true
+	}
+	logging.DecreaseIndentLevel()
+	return aggregateResults(cleanupResults), errors.Join(errs...)
+}
+
+// lockedWriter serializes writes from concurrently executing steps
+type lockedWriter struct {
+	mu *sync.Mutex
+	w  io.Writer
+}
+
+func (lw *lockedWriter) Write(b []byte) (int, error) {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+	return lw.w.Write(b)
+}
@@ -0,0 +1,247 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package blocks
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestParallelStepValidate(t *testing.T) {
+	testCases := []struct {
+		name                string
+		content             string
+		expectValidateError bool
+	}{
+		{
+			name: "Valid Parallel Step",
+			content: `name: group
+parallel:
+  - name: a
+    inline: echo a
+  - name: b
+    print_str: b`,
+		},
+		{
+			name: "Negative Max Concurrency",
+			content: `name: group
+max_concurrency: -1
+parallel:
+  - name: a
+    inline: echo a`,
+			expectValidateError: true,
+		},
+		{
+			name: "Duplicate Child Names",
+			content: `name: group
+parallel:
+  - name: a
+    inline: echo a
+  - name: a
+    inline: echo b`,
+			expectValidateError: true,
+		},
+		{
+			name: "Change Directory Not Allowed",
+			content: `name: group
+parallel:
+  - name: a
+    cd: /tmp`,
+			expectValidateError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var step Step
+			require.NoError(t, yaml.Unmarshal([]byte(tc.content), &step))
+			_, ok := step.action.(*ParallelStep)
+			require.True(t, ok, "step should be a parallel step")
+
+			err := step.Validate(NewTTPExecutionContext())
+			if tc.expectValidateError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestParallelStepInvalidChild(t *testing.T) {
+	content := `name: group
+parallel:
+  - inline: echo missing name`
+	var step Step
+	err := yaml.Unmarshal([]byte(content), &step)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no name specified for step")
+}
+
+func TestParallelStepExecution(t *testing.T) {
+	testCases := []struct {
+		name               string
+		content            string
+		expectExecuteError bool
+		maxDuration        time.Duration
+		minDuration        time.Duration
+		expectedByName     map[string]string
+		expectedStepVars   map[string]interface{}
+		expectedCleanupOut string
+	}{
+		{
+			name: "Children Run Concurrently",
+			content: `name: test
+description: children should overlap in time
+steps:
+  - name: group
+    parallel:
+      - name: a
+        inline: sleep 0.5 && echo a
+        cleanup:
+          inline: echo cleanup_a
+      - name: b
+        inline: sleep 0.5 && echo b
+        outputvar: b_out
+        cleanup:
+          inline: echo cleanup_b
+      - name: c
+        inline: sleep 0.5 && echo c
+        cleanup:
+          inline: echo cleanup_c
+  - name: after
+    inline: echo "b said {[{.StepVars.b_out}]} and a said $forge.steps.a.stdout"`,
+			maxDuration: 1400 * time.Millisecond,
+			expectedByName: map[string]string{
+				"a":     "a\n",
+				"b":     "b\n",
+				"c":     "c\n",
+				"after": "b said b and a said a\n\n",
+			},
+			expectedStepVars:   map[string]interface{}{"b_out": "b"},
+			expectedCleanupOut: "cleanup_c\ncleanup_b\ncleanup_a\n",
+		},
+		{
+			name: "Max Concurrency Limits Parallelism",
+			content: `name: test
+description: max_concurrency of 1 serializes the children
+steps:
+  - name: group
+    max_concurrency: 1
+    parallel:
+      - name: a
+        inline: sleep 0.3 && echo a
+      - name: b
+        inline: sleep 0.3 && echo b
+      - name: c
+        inline: sleep 0.3 && echo c`,
+			minDuration: 900 * time.Millisecond,
+			expectedByName: map[string]string{
+				"a": "a\n",
+				"b": "b\n",
+				"c": "c\n",
+			},
+		},
+		{
+			name: "Failed Child Cleans Up Siblings",
+			content: `name: test
+description: successful children are cleaned up when a sibling fails
+steps:
+  - name: group
+    parallel:
+      - name: a
+        inline: echo a
+        cleanup:
+          inline: echo cleanup_a
+      - name: b
+        inline: exit 3
+        cleanup:
+          inline: echo cleanup_b
+      - name: c
+        inline: echo c
+        cleanup:
+          inline: echo cleanup_c
+  - name: never
+    inline: echo never`,
+			expectExecuteError: true,
+			expectedByName: map[string]string{
+				"a": "a\n",
+				"c": "c\n",
+			},
+			expectedCleanupOut: "cleanup_c\ncleanup_a\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var ttp TTP
+			require.NoError(t, yaml.Unmarshal([]byte(tc.content), &ttp))
+
+			var stdout, stderr bytes.Buffer
+			execCtx := NewTTPExecutionContext()
+			execCtx.Cfg.Stdout = &stdout
+			execCtx.Cfg.Stderr = &stderr
+			require.NoError(t, ttp.Validate(execCtx))
+
+			start := time.Now()
+			err := ttp.RunSteps(execCtx)
+			elapsed := time.Since(start)
+			if tc.expectExecuteError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			if tc.maxDuration > 0 {
+				assert.Less(t, elapsed, tc.maxDuration)
+			}
+			if tc.minDuration > 0 {
+				assert.GreaterOrEqual(t, elapsed, tc.minDuration)
+			}
+
+			for name, expectedStdout := range tc.expectedByName {
+				result, ok := execCtx.StepResults.ByName[name]
+				require.True(t, ok, "missing result for step %v", name)
+				assert.Equal(t, expectedStdout, result.Stdout)
+			}
+			assert.NotContains(t, execCtx.StepResults.ByName, "never")
+
+			groupResult := execCtx.StepResults.ByIndex[0]
+			assert.Equal(t, "parallel", groupResult.ActionType)
+			assert.Len(t, groupResult.Children, 3)
+
+			for k, v := range tc.expectedStepVars {
+				assert.Equal(t, v, execCtx.Vars.StepVars[k])
+			}
+
+			// failed parallel steps are cleaned up immediately,
+			// so we check the tail of all output rather than
+			// only the output of RunCleanup
+			require.NoError(t, ttp.RunCleanup(execCtx))
+			assert.True(t, strings.HasSuffix(stdout.String(), tc.expectedCleanupOut),
+				"unexpected output %q", stdout.String())
+		})
+	}
+}
@@ -55,8 +55,13 @@ func (pf *PreambleFields) Validate(strict bool) error {
 		}
 	}
 	// validate MITRE mapping
-	if pf.MitreAttackMapping != nil && len(pf.MitreAttackMapping.Tactics) == 0 {
-		return fmt.Errorf("TTP '%s' has a MitreAttackMapping but no Tactic is defined", pf.Name)
+	if pf.MitreAttackMapping != nil {
+		if len(pf.MitreAttackMapping.Tactics) == 0 {
+			return fmt.Errorf("TTP '%s' has a MitreAttackMapping but no Tactic is defined", pf.Name)
+		}
+		if err := pf.MitreAttackMapping.Validate(); err != nil {
+			return fmt.Errorf("TTP '%s' has an invalid MITRE ATT&CK mapping: %w", pf.Name, err)
+		}
 	}
 
 	// validate requirements
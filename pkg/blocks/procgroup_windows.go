@@ -0,0 +1,66 @@
+//go:build windows
+
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package blocks
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+)
+
+// processGroupWaitDelay bounds how long we wait for the output
+// pipes of a killed command to be closed by its descendants
+const processGroupWaitDelay = 5 * time.Second
+
+// killProcessGroupOnCancel arranges for the command and all of its
+// descendants to be killed when the command's context is cancelled,
+// so that processes spawned by a timed-out step do not outlive it.
+func killProcessGroupOnCancel(cmd *exec.Cmd) {
+	cmd.Cancel = func() error {
+		if cmd.Process == nil {
+			return nil
+		}
+		return killProcessTree(cmd.Process.Pid)
+	}
+	cmd.WaitDelay = processGroupWaitDelay
+}
+
+// startInProcessGroup is a no-op on Windows, where
+// killProcessTree finds the descendants of a process itself
+func startInProcessGroup(_ *exec.Cmd) {}
+
+// killProcessTree kills the process with the
+// specified PID along with all of its descendants
+func killProcessTree(pid int) error {
+	// taskkill /T terminates the entire process tree
+	/* #nosec G204 */
+	killErr := exec.Command("taskkill", "/T", "/F", "/PID", strconv.Itoa(pid)).Run()
+	if killErr != nil {
+		proc, err := os.FindProcess(pid)
+		if err != nil {
+			return err
+		}
+		return proc.Kill()
+	}
+	return nil
+}
@@ -38,7 +38,7 @@ func TestRemovePathExecute(t *testing.T) {
 		name                string
 		description         string
 		step                *RemovePathAction
-		stepVars            map[string]string
+		stepVars            map[string]interface{}
 		fsysContents        map[string][]byte
 		expectValidateError bool
 		expectTemplateError bool
@@ -107,7 +107,7 @@ func TestRemovePathExecute(t *testing.T) {
 			fsysContents: map[string][]byte{
 				"valid-file.txt": []byte("whoops"),
 			},
-			stepVars: map[string]string{
+			stepVars: map[string]interface{}{
 				"filename": "valid-file",
 			},
 		},
@@ -0,0 +1,134 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package blocks
+
+import (
+	"fmt"
+
+	"github.com/facebookincubator/ttpforge/pkg/logging"
+	"github.com/spf13/afero"
+)
+
+// RenderTemplateStep renders a template file found in the
+// `template_search_paths` of the TTP's repository and writes
+// the result to the specified location. Templates use the regular
+// `{{ }}` delimiters and can reference the same data as step
+// templates (such as `{{ .Args.foo }}` or `{{ .StepVars.bar }}`),
+// so large payload files do not have to be inlined into a TTP.
+type RenderTemplateStep struct {
+	actionDefaults `yaml:",inline"`
+	TemplatePath   string   `yaml:"render_template,omitempty"`
+	Location       string   `yaml:"location,omitempty"`
+	Overwrite      bool     `yaml:"overwrite,omitempty"`
+	Mode           int      `yaml:"mode,omitempty"`
+	FileSystem     afero.Fs `yaml:"-,omitempty"`
+}
+
+// NewRenderTemplateStep creates a new RenderTemplateStep instance and returns a pointer to it.
+func NewRenderTemplateStep() *RenderTemplateStep {
+	return &RenderTemplateStep{}
+}
+
+// IsNil checks if the step is nil or empty and returns a boolean value.
+func (s *RenderTemplateStep) IsNil() bool {
+	switch {
+	case s.TemplatePath == "":
+		return true
+	default:
+		return false
+	}
+}
+
+// Validate validates the step, checking for the necessary attributes and dependencies.
+func (s *RenderTemplateStep) Validate(execCtx TTPExecutionContext) error {
+	if s.TemplatePath == "" {
+		return fmt.Errorf("render_template field cannot be empty")
+	}
+	if s.Location == "" {
+		return fmt.Errorf("location field cannot be empty")
+	}
+	// catch missing templates before the TTP starts,
+	// unless the template path is only known at run time
+	if execCtx.Cfg.Repo != nil && !execCtx.containsStepTemplating(s.TemplatePath) {
+		if _, err := execCtx.Cfg.Repo.FindTemplate(s.TemplatePath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Template takes each applicable field in the step and replaces any template strings with their resolved values.
+//
+// **Returns:**
+//
+// error: error if template resolution fails, nil otherwise
+func (s *RenderTemplateStep) Template(execCtx TTPExecutionContext) error {
+	var err error
+	s.TemplatePath, err = execCtx.templateStep(s.TemplatePath)
+	if err != nil {
+		return err
+	}
+	s.Location, err = execCtx.templateStep(s.Location)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Execute runs the step and returns an error if one occurs.
+func (s *RenderTemplateStep) Execute(execCtx TTPExecutionContext) (*ActResult, error) {
+	repo := execCtx.Cfg.Repo
+	if repo == nil {
+		return nil, fmt.Errorf("cannot locate template %v: the TTP does not belong to a repository", s.TemplatePath)
+	}
+	templatePath, err := repo.FindTemplate(s.TemplatePath)
+	if err != nil {
+		return nil, err
+	}
+	logging.L().Infof("Rendering template %v to %v", templatePath, s.Location)
+
+	contents, err := afero.ReadFile(repo.GetFs(), templatePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template %v: %w", templatePath, err)
+	}
+	rendered, err := execCtx.renderTemplate(s.TemplatePath, string(contents))
+	if err != nil {
+		return nil, fmt.Errorf("failed to render template %v: %w", s.TemplatePath, err)
+	}
+
+	// the rendered template is written just
+	// like the contents of a create_file step
+	createFile := CreateFileStep{
+		Path:       s.Location,
+		Contents:   rendered,
+		Overwrite:  s.Overwrite,
+		Mode:       s.Mode,
+		FileSystem: s.FileSystem,
+	}
+	return createFile.Execute(execCtx)
+}
+
+// GetDefaultCleanupAction will instruct the calling code
+// to remove the file rendered by this action
+func (s *RenderTemplateStep) GetDefaultCleanupAction() Action {
+	return &RemovePathAction{
+		Path: s.Location,
+	}
+}
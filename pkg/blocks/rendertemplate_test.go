@@ -0,0 +1,202 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package blocks
+
+import (
+	"testing"
+
+	"github.com/facebookincubator/ttpforge/pkg/repos"
+	"github.com/facebookincubator/ttpforge/pkg/testutils"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	testCases := []struct {
+		name                string
+		content             string
+		noRepo              bool
+		existingFiles       map[string][]byte
+		stepVars            map[string]interface{}
+		expectValidateError bool
+		expectExecuteError  bool
+		expectedPath        string
+		expectedContents    string
+	}{
+		{
+			name: "renders args, step variables and functions",
+			content: `
+name: render
+render_template: payloads/config.json.tmpl
+location: /tmp/{[{.StepVars.filename}]}
+`,
+			stepVars: map[string]interface{}{
+				"filename": "config.json",
+				"token":    "s3cr3t",
+			},
+			expectedPath:     "/tmp/config.json",
+			expectedContents: `{"host": "c2.example.com", "token": "s3cr3t", "tag": "TTPFORGE"}` + "\n",
+		},
+		{
+			name: "searches all template search paths",
+			content: `
+name: render
+render_template: plain.txt
+location: /tmp/plain.txt
+`,
+			expectedPath:     "/tmp/plain.txt",
+			expectedContents: "nothing to see here\n",
+		},
+		{
+			name: "missing template",
+			content: `
+name: render
+render_template: payloads/missing.tmpl
+location: /tmp/config.json
+`,
+			expectValidateError: true,
+		},
+		{
+			name: "missing template path after templating",
+			content: `
+name: render
+render_template: payloads/{[{.StepVars.name}]}
+location: /tmp/config.json
+`,
+			stepVars: map[string]interface{}{
+				"name": "missing.tmpl",
+			},
+			expectExecuteError: true,
+		},
+		{
+			name: "missing location",
+			content: `
+name: render
+render_template: plain.txt
+`,
+			expectValidateError: true,
+		},
+		{
+			name: "missing variable",
+			content: `
+name: render
+render_template: payloads/config.json.tmpl
+location: /tmp/config.json
+`,
+			expectExecuteError: true,
+		},
+		{
+			name: "location exists",
+			content: `
+name: render
+render_template: plain.txt
+location: /tmp/plain.txt
+`,
+			existingFiles: map[string][]byte{
+				"/tmp/plain.txt": []byte("original"),
+			},
+			expectExecuteError: true,
+		},
+		{
+			name: "overwrite location",
+			content: `
+name: render
+render_template: plain.txt
+location: /tmp/plain.txt
+overwrite: true
+`,
+			existingFiles: map[string][]byte{
+				"/tmp/plain.txt": []byte("original"),
+			},
+			expectedPath:     "/tmp/plain.txt",
+			expectedContents: "nothing to see here\n",
+		},
+		{
+			name: "no repository",
+			content: `
+name: render
+render_template: plain.txt
+location: /tmp/plain.txt
+`,
+			noRepo:             true,
+			expectExecuteError: true,
+		},
+	}
+
+	repoFs, err := testutils.MakeAferoTestFs(map[string][]byte{
+		"repos/a/" + repos.RepoConfigFileName: []byte(`template_search_paths: ["templates", "more-templates"]`),
+		"repos/a/templates/payloads/config.json.tmpl": []byte(
+			`{"host": "{{ .Args.host }}", "token": "{{ .StepVars.token }}", "tag": "{{ upper "ttpforge" }}"}` + "\n"),
+		"repos/a/more-templates/plain.txt": []byte("nothing to see here\n"),
+	})
+	require.NoError(t, err)
+	spec := repos.Spec{Name: "default", Path: "repos/a"}
+	repo, err := spec.Load(repoFs, "")
+	require.NoError(t, err)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var step RenderTemplateStep
+			err := yaml.Unmarshal([]byte(tc.content), &step)
+			require.NoError(t, err)
+
+			fsys := afero.NewMemMapFs()
+			if tc.existingFiles != nil {
+				fsys, err = testutils.MakeAferoTestFs(tc.existingFiles)
+				require.NoError(t, err)
+			}
+			step.FileSystem = fsys
+
+			execCtx := NewTTPExecutionContext()
+			if !tc.noRepo {
+				execCtx.Cfg.Repo = repo
+			}
+			execCtx.Vars.Args["host"] = "c2.example.com"
+			if tc.stepVars != nil {
+				execCtx.Vars.StepVars = tc.stepVars
+			}
+
+			err = step.Validate(execCtx)
+			if tc.expectValidateError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			require.NoError(t, step.Template(execCtx))
+			_, err = step.Execute(execCtx)
+			if tc.expectExecuteError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			contents, err := afero.ReadFile(fsys, tc.expectedPath)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedContents, string(contents))
+
+			cleanup, ok := step.GetDefaultCleanupAction().(*RemovePathAction)
+			require.True(t, ok)
+			assert.Equal(t, tc.expectedPath, cleanup.Path)
+		})
+	}
+}
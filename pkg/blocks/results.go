@@ -19,20 +19,95 @@ THE SOFTWARE.
 
 package blocks
 
+import (
+	"errors"
+	"os/exec"
+	"time"
+)
+
+// StepStatus describes the final state of a step
+// once the TTP has finished executing it
+type StepStatus string
+
+const (
+	// StepStatusSucceeded means the step action ran without error
+	// and all of its checks (if any) passed
+	StepStatusSucceeded StepStatus = "succeeded"
+	// StepStatusFailed means the step action returned an error
+	StepStatusFailed StepStatus = "failed"
+	// StepStatusChecksFailed means the step action ran without error
+	// but one of the success checks for the step failed
+	StepStatusChecksFailed StepStatus = "checks_failed"
+	// StepStatusTimeout means the step action
+	// did not finish before its timeout expired
+	StepStatusTimeout StepStatus = "timeout"
+	// StepStatusSkipped means the step was not run
+	// because its `if:` condition evaluated to false
+	StepStatusSkipped StepStatus = "skipped"
+)
+
 // ActResult contains common fields produced
 // from both the execution of steps and their
 // associated cleanup actions
 type ActResult struct {
-	Stdout  string
-	Stderr  string
-	Outputs map[string]string
+	Stdout  string                 `json:"stdout"`
+	Stderr  string                 `json:"stderr"`
+	Outputs map[string]interface{} `json:"outputs,omitempty"`
+}
+
+// CheckResult records the outcome of a single
+// success check associated with a step
+type CheckResult struct {
+	Msg    string `json:"msg"`
+	Passed bool   `json:"passed"`
+	Error  string `json:"error,omitempty"`
 }
 
 // ExecutionResult stores the results/outputs
-// generated by executing a Step
+// generated by executing a Step, along with the
+// metadata needed to report on that execution
 type ExecutionResult struct {
 	ActResult
-	Cleanup *ActResult
+	Name         string        `json:"name"`
+	ActionType   string        `json:"action_type"`
+	Status       StepStatus    `json:"status"`
+	StartTime    time.Time     `json:"start_time"`
+	EndTime      time.Time     `json:"end_time"`
+	ExitCode     int           `json:"exit_code"`
+	Error        string        `json:"error,omitempty"`
+	Checks       []CheckResult `json:"checks,omitempty"`
+	Cleanup      *ActResult    `json:"cleanup,omitempty"`
+	CleanupError string        `json:"cleanup_error,omitempty"`
+	// CleanedUp is set once the cleanup of the
+	// step has completed successfully
+	CleanedUp bool `json:"cleaned_up,omitempty"`
+	// Attempts records the outcome of every attempt at
+	// executing the step - there is more than one
+	// attempt only if the step has a retry policy
+	Attempts []AttemptResult `json:"attempts,omitempty"`
+	// Children holds the results of the child steps of a
+	// parallel, loop or sub-TTP step, in declaration order
+	Children []*ExecutionResult `json:"children,omitempty"`
+	// Loop identifies the item and index of the
+	// loop iteration that produced this result
+	Loop *LoopVars `json:"loop,omitempty"`
+}
+
+// NeedsCleanup returns true if the step that produced
+// this result should be cleaned up. Steps whose action failed
+// (or timed out) are not cleaned up by the regular cleanup process,
+// skipped steps have nothing to clean up, and steps that
+// were already cleaned up must not be cleaned up twice.
+func (er *ExecutionResult) NeedsCleanup() bool {
+	if er.CleanedUp {
+		return false
+	}
+	switch er.Status {
+	case StepStatusFailed, StepStatusTimeout, StepStatusSkipped:
+		return false
+	default:
+		return true
+	}
 }
 
 // StepResultsRecord provides convenient accessors
@@ -50,3 +125,32 @@ func NewStepResultsRecord() *StepResultsRecord {
 		ByIndex: []*ExecutionResult{},
 	}
 }
+
+// copy returns a copy of the record that steps running
+// concurrently with other steps can record their results
+// in without affecting the original
+func (r *StepResultsRecord) copy() *StepResultsRecord {
+	byName := make(map[string]*ExecutionResult, len(r.ByName))
+	for name, result := range r.ByName {
+		byName[name] = result
+	}
+	return &StepResultsRecord{
+		ByName:  byName,
+		ByIndex: append([]*ExecutionResult{}, r.ByIndex...),
+	}
+}
+
+// exitCodeFromError extracts the process exit code
+// from an error returned by an action.
+// It returns 0 if err is nil and -1 if the error
+// did not originate from a process exiting
+func exitCodeFromError(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
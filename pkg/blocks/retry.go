@@ -0,0 +1,188 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package blocks
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/facebookincubator/ttpforge/pkg/logging"
+	"gopkg.in/yaml.v3"
+)
+
+// Conditions that can trigger a retry - see RetryPolicy
+const (
+	// RetryOnExitCode retries the step when its action fails
+	// (for command-based actions, when they exit with a non-zero code)
+	RetryOnExitCode = "exit_code"
+	// RetryOnCheckFailure retries the step when
+	// one of its success checks fails
+	RetryOnCheckFailure = "check_failure"
+	// RetryOnTimeout retries the step when it exceeds its timeout
+	RetryOnTimeout = "timeout"
+)
+
+// RetryPolicy controls whether and how a failed step is executed again
+//
+// **Attributes:**
+//
+// Attempts: the total number of times the step may be executed (including the first).
+// Delay: how long to wait before the first retry.
+// Backoff: the factor by which the delay is multiplied after each retry.
+// RetryOn: the kinds of failure that trigger a retry - defaults to all of them.
+type RetryPolicy struct {
+	Attempts int             `yaml:"attempts"`
+	Delay    Timeout         `yaml:"delay,omitempty"`
+	Backoff  float64         `yaml:"backoff,omitempty"`
+	RetryOn  RetryConditions `yaml:"retry_on,omitempty"`
+}
+
+// RetryConditions is a list of retry conditions that
+// may also be written in YAML as a single string
+type RetryConditions []string
+
+// UnmarshalYAML accepts either a single condition or a list of conditions
+func (rc *RetryConditions) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*rc = RetryConditions{node.Value}
+		return nil
+	}
+	var conditions []string
+	if err := node.Decode(&conditions); err != nil {
+		return err
+	}
+	*rc = conditions
+	return nil
+}
+
+// AttemptResult records the outcome of a single
+// attempt at executing a step
+type AttemptResult struct {
+	Number    int        `json:"number"`
+	Status    StepStatus `json:"status"`
+	StartTime time.Time  `json:"start_time"`
+	EndTime   time.Time  `json:"end_time"`
+	ExitCode  int        `json:"exit_code"`
+	Error     string     `json:"error,omitempty"`
+}
+
+// Validate checks that the retry policy is well-formed
+func (p *RetryPolicy) Validate() error {
+	if p.Attempts < 1 {
+		return fmt.Errorf("retry attempts must be at least 1, got %d", p.Attempts)
+	}
+	if p.Delay < 0 {
+		return fmt.Errorf("retry delay must not be negative, got %v", p.Delay.Duration())
+	}
+	if p.Backoff != 0 && p.Backoff < 1 {
+		return fmt.Errorf("retry backoff must be at least 1, got %v", p.Backoff)
+	}
+	for _, condition := range p.RetryOn {
+		switch condition {
+		case RetryOnExitCode, RetryOnCheckFailure, RetryOnTimeout:
+		default:
+			return fmt.Errorf("invalid retry_on value %q - must be %q, %q or %q", condition, RetryOnExitCode, RetryOnCheckFailure, RetryOnTimeout)
+		}
+	}
+	return nil
+}
+
+// shouldRetry reports whether a step should be executed again
+// after the specified attempt finished with the specified status
+func (p *RetryPolicy) shouldRetry(status StepStatus, attempt int) bool {
+	if p == nil || attempt >= p.Attempts {
+		return false
+	}
+	var condition string
+	switch status {
+	case StepStatusFailed:
+		condition = RetryOnExitCode
+	case StepStatusChecksFailed:
+		condition = RetryOnCheckFailure
+	case StepStatusTimeout:
+		condition = RetryOnTimeout
+	default:
+		return false
+	}
+	if len(p.RetryOn) == 0 {
+		return true
+	}
+	for _, c := range p.RetryOn {
+		if c == condition {
+			return true
+		}
+	}
+	return false
+}
+
+// delayAfter returns how long to wait after the specified attempt
+func (p *RetryPolicy) delayAfter(attempt int) time.Duration {
+	backoff := p.Backoff
+	if backoff == 0 {
+		backoff = 1
+	}
+	return time.Duration(float64(p.Delay) * math.Pow(backoff, float64(attempt-1)))
+}
+
+// runAttempts calls the provided attempt function until it succeeds
+// or the retry policy is exhausted, recording every attempt in the
+// execution result. The attempt function must set the Status (and, on
+// failure, the Error and ExitCode) of the execution result and return
+// true if a shutdown signal was received.
+// A nil policy runs exactly one attempt.
+//
+// **Parameters:**
+//
+// execCtx: The current TTPExecutionContext
+// stepName: the name of the step, used for logging
+// result: the execution result to be updated
+// attempt: a function that executes the step once
+//
+// **Returns:**
+//
+// bool: true if a shutdown signal was received
+func (p *RetryPolicy) runAttempts(execCtx TTPExecutionContext, stepName string, result *ExecutionResult, attempt func() bool) bool {
+	for number := 1; ; number++ {
+		attemptStart := time.Now()
+		shutdown := attempt()
+		result.Attempts = append(result.Attempts, AttemptResult{
+			Number:    number,
+			Status:    result.Status,
+			StartTime: attemptStart,
+			EndTime:   time.Now(),
+			ExitCode:  result.ExitCode,
+			Error:     result.Error,
+		})
+		if shutdown || !p.shouldRetry(result.Status, number) {
+			return shutdown
+		}
+
+		delay := p.delayAfter(number)
+		logging.L().Warnf("Attempt %d of %d for step %q failed: %v - retrying in %v", number, p.Attempts, stepName, result.Error, delay)
+		select {
+		case <-time.After(delay):
+		case <-execCtx.shutdownChan:
+			logging.L().Warn("Shutting down due to signal received")
+			result.Error = "shutdown signal received"
+			return true
+		}
+	}
+}
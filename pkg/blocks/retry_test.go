@@ -0,0 +1,242 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package blocks
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestRetryPolicyUnmarshalAndValidate(t *testing.T) {
+	testCases := []struct {
+		name                string
+		content             string
+		expectedPolicy      RetryPolicy
+		expectValidateError bool
+	}{
+		{
+			name: "Full Policy",
+			content: `attempts: 3
+delay: 2s
+backoff: 1.5
+retry_on:
+  - exit_code
+  - check_failure`,
+			expectedPolicy: RetryPolicy{
+				Attempts: 3,
+				Delay:    Timeout(2 * time.Second),
+				Backoff:  1.5,
+				RetryOn:  RetryConditions{RetryOnExitCode, RetryOnCheckFailure},
+			},
+		},
+		{
+			name: "Delay In Seconds",
+			content: `attempts: 2
+delay: 2`,
+			expectedPolicy: RetryPolicy{
+				Attempts: 2,
+				Delay:    Timeout(2 * time.Second),
+			},
+		},
+		{
+			name: "Negative Delay",
+			content: `attempts: 2
+delay: -1s`,
+			expectedPolicy: RetryPolicy{
+				Attempts: 2,
+				Delay:    Timeout(-time.Second),
+			},
+			expectValidateError: true,
+		},
+		{
+			name: "Single Retry Condition",
+			content: `attempts: 2
+retry_on: check_failure`,
+			expectedPolicy: RetryPolicy{
+				Attempts: 2,
+				RetryOn:  RetryConditions{RetryOnCheckFailure},
+			},
+		},
+		{
+			name:                "Zero Attempts",
+			content:             `attempts: 0`,
+			expectedPolicy:      RetryPolicy{},
+			expectValidateError: true,
+		},
+		{
+			name: "Backoff Below One",
+			content: `attempts: 2
+backoff: 0.5`,
+			expectedPolicy:      RetryPolicy{Attempts: 2, Backoff: 0.5},
+			expectValidateError: true,
+		},
+		{
+			name: "Invalid Retry Condition",
+			content: `attempts: 2
+retry_on: sometimes`,
+			expectedPolicy: RetryPolicy{
+				Attempts: 2,
+				RetryOn:  RetryConditions{"sometimes"},
+			},
+			expectValidateError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var policy RetryPolicy
+			require.NoError(t, yaml.Unmarshal([]byte(tc.content), &policy))
+			assert.Equal(t, tc.expectedPolicy, policy)
+
+			err := policy.Validate()
+			if tc.expectValidateError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestRetryPolicyShouldRetry(t *testing.T) {
+	policy := &RetryPolicy{Attempts: 3, RetryOn: RetryConditions{RetryOnExitCode}}
+	assert.True(t, policy.shouldRetry(StepStatusFailed, 1))
+	assert.True(t, policy.shouldRetry(StepStatusFailed, 2))
+	assert.False(t, policy.shouldRetry(StepStatusFailed, 3))
+	assert.False(t, policy.shouldRetry(StepStatusChecksFailed, 1))
+	assert.False(t, policy.shouldRetry(StepStatusSucceeded, 1))
+
+	allConditions := &RetryPolicy{Attempts: 2}
+	assert.True(t, allConditions.shouldRetry(StepStatusFailed, 1))
+	assert.True(t, allConditions.shouldRetry(StepStatusChecksFailed, 1))
+
+	var noPolicy *RetryPolicy
+	assert.False(t, noPolicy.shouldRetry(StepStatusFailed, 1))
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	constant := &RetryPolicy{Attempts: 4, Delay: Timeout(time.Second)}
+	assert.Equal(t, time.Second, constant.delayAfter(1))
+	assert.Equal(t, time.Second, constant.delayAfter(3))
+
+	exponential := &RetryPolicy{Attempts: 4, Delay: Timeout(time.Second), Backoff: 2}
+	assert.Equal(t, time.Second, exponential.delayAfter(1))
+	assert.Equal(t, 2*time.Second, exponential.delayAfter(2))
+	assert.Equal(t, 4*time.Second, exponential.delayAfter(3))
+}
+
+func TestRetryExecution(t *testing.T) {
+	// each execution of this command increments a counter
+	// stored in a file and fails until the counter reaches 3
+	const flakyCommand = `n=$(cat %[1]s 2>/dev/null || echo 0); n=$((n+1)); echo $n > %[1]s; echo -n attempt $n; [ $n -ge 3 ]`
+
+	testCases := []struct {
+		name               string
+		stepTemplate       string
+		expectExecuteError bool
+		expectedStatus     StepStatus
+		expectedAttempts   []StepStatus
+		expectedStdout     string
+	}{
+		{
+			name: "Succeeds After Retries",
+			stepTemplate: `name: flaky
+inline: '` + flakyCommand + `'
+retry:
+  attempts: 5
+  delay: 10ms`,
+			expectedStatus:   StepStatusSucceeded,
+			expectedAttempts: []StepStatus{StepStatusFailed, StepStatusFailed, StepStatusSucceeded},
+			expectedStdout:   "attempt 3",
+		},
+		{
+			name: "Attempts Exhausted",
+			stepTemplate: `name: flaky
+inline: '` + flakyCommand + `'
+retry:
+  attempts: 2`,
+			expectExecuteError: true,
+			expectedStatus:     StepStatusFailed,
+			expectedAttempts:   []StepStatus{StepStatusFailed, StepStatusFailed},
+		},
+		{
+			name: "Retry Condition Not Matched",
+			stepTemplate: `name: flaky
+inline: '` + flakyCommand + `'
+retry:
+  attempts: 5
+  retry_on: check_failure`,
+			expectExecuteError: true,
+			expectedStatus:     StepStatusFailed,
+			expectedAttempts:   []StepStatus{StepStatusFailed},
+		},
+		{
+			name: "Retry On Check Failure",
+			stepTemplate: `name: flaky
+inline: 'n=$(cat %[1]s 2>/dev/null || echo 0); n=$((n+1)); echo $n > %[1]s; [ $n -lt 2 ] || touch %[1]s.done'
+checks:
+  - msg: marker file should exist
+    path_exists: %[1]s.done
+retry:
+  attempts: 3
+  retry_on: check_failure`,
+			expectedStatus:   StepStatusSucceeded,
+			expectedAttempts: []StepStatus{StepStatusChecksFailed, StepStatusSucceeded},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			counterPath := filepath.Join(t.TempDir(), "counter")
+			stepYAML := fmt.Sprintf(tc.stepTemplate, counterPath)
+			var step Step
+			require.NoError(t, yaml.Unmarshal([]byte(stepYAML), &step))
+			ttp := TTP{Steps: []Step{step}}
+
+			execCtx := NewTTPExecutionContext()
+			require.NoError(t, ttp.Validate(execCtx))
+			err := ttp.RunSteps(execCtx)
+			if tc.expectExecuteError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			result := execCtx.StepResults.ByName["flaky"]
+			require.NotNil(t, result)
+			assert.Equal(t, tc.expectedStatus, result.Status)
+			var attemptStatuses []StepStatus
+			for idx, attempt := range result.Attempts {
+				assert.Equal(t, idx+1, attempt.Number)
+				attemptStatuses = append(attemptStatuses, attempt.Status)
+			}
+			assert.Equal(t, tc.expectedAttempts, attemptStatuses)
+			if tc.expectedStdout != "" {
+				assert.Equal(t, tc.expectedStdout, result.Stdout)
+			}
+		})
+	}
+}
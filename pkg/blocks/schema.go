@@ -0,0 +1,206 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package blocks
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/facebookincubator/ttpforge/pkg/args"
+	"github.com/facebookincubator/ttpforge/pkg/checks"
+	"github.com/facebookincubator/ttpforge/pkg/outputs"
+	"github.com/facebookincubator/ttpforge/pkg/parseutils"
+	"github.com/facebookincubator/ttpforge/pkg/schema"
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaID identifies the JSON Schema of the TTP format
+const SchemaID = "https://github.com/facebookincubator/TTPForge/ttp.schema.json"
+
+// actionSchemaTypes lists every type of action that can be
+// written in a step together with the YAML key that identifies
+// it - new action types must be added here as well as to ParseAction
+var actionSchemaTypes = []struct {
+	key    string
+	action Action
+}{
+	{"inline", &BasicStep{}},
+	{"cd", &ChangeDirectoryStep{}},
+	{"file", &FileStep{}},
+	{"ttp", &SubTTPStep{}},
+	{"edit_file", &EditStep{}},
+	{"fetch_uri", &FetchURIStep{}},
+	{"create_file", &CreateFileStep{}},
+	{"render_template", &RenderTemplateStep{}},
+	{"copy_path", &CopyPathStep{}},
+	{"remove_path", &RemovePathAction{}},
+	{"print_str", &PrintStrAction{}},
+	{"expect", &ExpectStep{}},
+	{"http_request", &HTTPRequestStep{}},
+	{"kill_process_id", &KillProcessStep{}},
+	{"kill_process_name", &KillProcessStep{}},
+	{"start_process", &StartProcessStep{}},
+	{"wait_for", &WaitForStep{}},
+	{"parallel", &ParallelStep{}},
+}
+
+// ActionTypes returns the YAML keys that identify
+// each type of action that can be written in a step
+//
+// **Returns:**
+//
+// []string: the action keys, such as inline or create_file
+func ActionTypes() []string {
+	keys := make([]string, 0, len(actionSchemaTypes))
+	for _, actionType := range actionSchemaTypes {
+		keys = append(keys, actionType.key)
+	}
+	return keys
+}
+
+// testCaseSchema describes the entries of the `tests:`
+// section, which is read by `ttpforge test`
+type testCaseSchema struct {
+	Name        string                 `yaml:"name"`
+	Description string                 `yaml:"description"`
+	Args        map[string]interface{} `yaml:"args"`
+	DryRun      bool                   `yaml:"dry_run"`
+}
+
+// Schema returns the JSON Schema of the TTP file format,
+// covering the preamble, every type of step action,
+// checks, outputs and cleanup actions
+//
+// **Returns:**
+//
+// *schema.Schema: the schema of a TTP file
+func Schema() *schema.Schema {
+	r := &schema.Reflector{Overrides: map[reflect.Type]*schema.Schema{
+		reflect.TypeOf(Step{}):      schema.Ref("step"),
+		reflect.TypeOf(yaml.Node{}): schema.AnyOf(&schema.Schema{Type: "string", Enum: []interface{}{"default"}}, schema.Ref("action")),
+		reflect.TypeOf(Timeout(0)):  schema.AnyOf(&schema.Schema{Type: "string"}, &schema.Schema{Type: "integer"}),
+		reflect.TypeOf(RetryConditions{}): schema.AnyOf(
+			&schema.Schema{Type: "string"},
+			&schema.Schema{Type: "array", Items: &schema.Schema{Type: "string"}},
+		),
+		reflect.TypeOf(InheritEnv{}): schema.AnyOf(
+			&schema.Schema{Type: "string", Enum: []interface{}{"all", "none"}},
+			&schema.Schema{Type: "array", Items: &schema.Schema{Type: "string"}},
+		),
+		reflect.TypeOf(args.EnumValue{}): schema.AnyOf(
+			&schema.Schema{Type: "string"},
+			schema.Object(map[string]*schema.Schema{
+				"value":       {Type: "string"},
+				"description": {Type: "string"},
+			}),
+		),
+	}}
+	// the other exported fields of cd steps are internal state
+	r.Overrides[reflect.TypeOf(ChangeDirectoryStep{})] = schema.Merge(
+		r.Reflect(actionDefaults{}),
+		schema.Object(map[string]*schema.Schema{"cd": {Type: "string"}}),
+	)
+	// checks and outputs are referenced from several
+	// action types, so they are defined only once
+	checkSchema, outputSchema := checks.Schema(r), outputs.SpecSchema(r)
+	r.Overrides[reflect.TypeOf(checks.Check{})] = schema.Ref("check")
+	r.Overrides[reflect.TypeOf(WaitCondition{})] = checks.ConditionSchema(r)
+	r.Overrides[reflect.TypeOf(outputs.Spec{})] = schema.Ref("output")
+	// the loop types have shorthand forms, so their
+	// fields are described through types without overrides
+	type loopRangeFields LoopRange
+	type loopSpecFields LoopSpec
+	r.Overrides[reflect.TypeOf(LoopRange{})] = schema.AnyOf(&schema.Schema{Type: "integer"}, r.Reflect(loopRangeFields{}))
+	r.Overrides[reflect.TypeOf(LoopSpec{})] = schema.AnyOf(
+		&schema.Schema{Type: "array"},
+		&schema.Schema{Type: "string"},
+		r.Reflect(loopSpecFields{}),
+	)
+
+	common := r.Reflect(CommonStepFields{})
+	var steps, actions []*schema.Schema
+	for _, actionType := range actionSchemaTypes {
+		action := r.Reflect(actionType.action)
+		action.Required = []string{actionType.key}
+		actions = append(actions, action)
+
+		step := schema.Merge(common, action)
+		step.Required = []string{actionType.key}
+		steps = append(steps, step)
+	}
+
+	root := r.Reflect(TTP{})
+	root.Properties["tests"] = &schema.Schema{Type: "array", Items: r.Reflect(testCaseSchema{})}
+	root.Schema = schema.Draft
+	root.ID = SchemaID
+	root.Title = "TTPForge TTP"
+	root.Defs = map[string]*schema.Schema{
+		"step":   schema.AnyOf(steps...),
+		"action": schema.AnyOf(actions...),
+		"check":  checkSchema,
+		"output": outputSchema,
+	}
+	return root
+}
+
+// CheckUnknownKeys checks that a TTP file does not contain
+// any keys that are not part of the TTP format, such as
+// misspelled field names that would otherwise be ignored.
+// The file is parsed without rendering it (see parseutils.StripTemplates)
+// so that the reported positions are those of the file itself - only
+// if it cannot be parsed that way is the rendered TTP checked instead.
+//
+// **Parameters:**
+//
+// ttpFilePath: the path of the TTP file, used in error messages
+// contents: the contents of the TTP file
+// rendered: the rendered TTP, or nil if it is not available
+//
+// **Returns:**
+//
+// error: an error listing the position of every unknown key
+func CheckUnknownKeys(ttpFilePath string, contents, rendered []byte) error {
+	location := ttpFilePath
+	var node yaml.Node
+	if err := yaml.Unmarshal(parseutils.StripTemplates(contents), &node); err != nil {
+		// some templates (such as the branches of an `if` that set
+		// the same key) only produce valid YAML once they are rendered
+		if rendered == nil {
+			return err
+		}
+		node = yaml.Node{}
+		if err := yaml.Unmarshal(rendered, &node); err != nil {
+			return err
+		}
+		location = fmt.Sprintf("%v (rendered)", ttpFilePath)
+	}
+	var lines []string
+	for _, unknownKey := range schema.UnknownKeys(&node, Schema()) {
+		// keys written as template actions are only known once rendered
+		if location == ttpFilePath && strings.Contains(unknownKey.Key, "TEMPLATE") {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%v:%d:%d: %v", location, unknownKey.Line, unknownKey.Column, unknownKey.Message()))
+	}
+	if len(lines) == 0 {
+		return nil
+	}
+	return fmt.Errorf("TTP file contains %d unknown key(s):\n\t%v", len(lines), strings.Join(lines, "\n\t"))
+}
@@ -0,0 +1,269 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package blocks
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaIsValidJSON(t *testing.T) {
+	schemaJSON, err := json.Marshal(Schema())
+	require.NoError(t, err)
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(schemaJSON, &decoded))
+	defs := decoded["$defs"].(map[string]interface{})
+	for _, def := range []string{"step", "action", "check", "output"} {
+		assert.Contains(t, defs, def)
+	}
+}
+
+func TestCheckUnknownKeys(t *testing.T) {
+	testCases := []struct {
+		name          string
+		content       string
+		expectedError string
+	}{
+		{
+			name: "valid",
+			content: `---
+api_version: 2.0
+uuid: 8f0dd3a5-2d5c-4f4b-a3a1-8d2d61b4e2c1
+name: valid
+description: uses most features of the format
+mitre:
+  tactics:
+    - TA0002
+requirements:
+  platforms:
+    - os: linux
+args:
+  - name: mode
+    type: enum
+    values:
+      - fast
+      - value: full
+        description: run every check
+tests:
+  - name: default
+    args:
+      mode: fast
+steps:
+  - name: first
+    inline: echo '{"a":1}'
+    timeout: 30s
+    retry:
+      attempts: 2
+      retry_on: exit_code
+    outputs:
+      a:
+        filters:
+          - json_path: a
+        type: int
+      trimmed:
+        filters:
+          - trim: true
+    checks:
+      - msg: file exists
+        path_exists: /tmp
+        checksum:
+          sha256: abc
+    cleanup:
+      inline: echo cleanup
+  - name: loop
+    loop:
+      range: 3
+    print_str: "{[{.Loop.Item}]}"
+  - name: group
+    parallel:
+      - name: a
+        create_file: /tmp/a
+        contents: a
+        cleanup: default
+      - name: b
+        kill_process_name: foo
+        error_on_kill_failure: false
+  - name: wait
+    wait_for:
+      path_exists: /tmp/a
+      content_contains: a
+    interval: 2s
+    negate: true
+`,
+		},
+		{
+			name: "typo-in-step",
+			content: `name: typos
+steps:
+  - name: first
+    inline: echo hi
+    outputvars: x
+`,
+			expectedError: `ttp.yaml:5:5: unknown key "outputvars" (did you mean "outputvar"?)`,
+		},
+		{
+			name: "typo-in-cleanup",
+			content: `name: typos
+steps:
+  - name: first
+    create_file: /tmp/a
+    cleanup:
+      remove_path: /tmp/a
+      recursiv: true
+`,
+			expectedError: `ttp.yaml:7:7: unknown key "recursiv" (did you mean "recursive"?)`,
+		},
+		{
+			name: "typo-in-action-field",
+			content: `name: typos
+steps:
+  - name: first
+    fetch_uri: https://example.com
+    location: /tmp/a
+    overwirte: true
+`,
+			expectedError: `ttp.yaml:6:5: unknown key "overwirte" (did you mean "overwrite"?)`,
+		},
+		{
+			name: "typo-in-preamble",
+			content: `name: typos
+descripton: x
+args:
+  - name: a
+    defualt: b
+steps:
+  - name: first
+    print_str: hi
+`,
+			expectedError: "TTP file contains 2 unknown key(s):\n" +
+				"\tttp.yaml:2:1: unknown key \"descripton\" (did you mean \"description\"?)\n" +
+				"\tttp.yaml:5:5: unknown key \"defualt\" (did you mean \"default\"?)",
+		},
+		{
+			name: "typo-in-check",
+			content: `name: typos
+steps:
+  - name: first
+    print_str: hi
+    checks:
+      - msg: check
+        command: "true"
+        expect_exitcode: 0
+`,
+			expectedError: `ttp.yaml:8:9: unknown key "expect_exitcode" (did you mean "expect_exit_code"?)`,
+		},
+		{
+			name: "typo-in-wait-condition",
+			content: `name: typos
+steps:
+  - name: wait
+    wait_for:
+      path_exists: /tmp/a
+      content_contain: a
+`,
+			expectedError: `ttp.yaml:6:7: unknown key "content_contain" (did you mean "content_contains"?)`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CheckUnknownKeys("ttp.yaml", []byte(tc.content), nil)
+			if tc.expectedError == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedError)
+		})
+	}
+}
+
+func TestStrictLoadReportsSourcePositions(t *testing.T) {
+	testCases := []struct {
+		name          string
+		content       string
+		expectedError string
+	}{
+		{
+			name: "typo-after-range",
+			content: `name: strict
+args:
+  - name: targets
+    default: a,b,c
+steps:
+{{- range (splitList "," .Args.targets) }}
+  - name: ping_{{ . }}
+    inline: echo {{ . }}
+{{- end }}
+  - name: typo
+    inline: echo typo
+    expect_exitcode: 0
+`,
+			expectedError: `ttp.yaml:12:5: unknown key "expect_exitcode"`,
+		},
+		{
+			name: "typo-after-if-else",
+			content: `name: strict
+args:
+  - name: loud
+    type: bool
+    default: true
+steps:
+  - name: greet
+{{- if .Args.loud }}
+    inline: echo HELLO
+{{- else }}
+    inline: echo hello
+{{- end }}
+    expect_exitcode: 0
+`,
+			expectedError: `ttp.yaml:13:5: unknown key "expect_exitcode"`,
+		},
+		{
+			name: "templated-key",
+			content: `name: strict
+args:
+  - name: action
+    default: inline
+steps:
+  - name: templated
+    {{ .Args.action }}: echo hello
+    outputvars: foo
+`,
+			expectedError: `ttp.yaml:8:5: unknown key "outputvars"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fsys := afero.NewMemMapFs()
+			require.NoError(t, afero.WriteFile(fsys, "ttp.yaml", []byte(tc.content), 0644))
+			execCfg := TTPExecutionConfig{Strict: true}
+			_, _, err := LoadTTP("ttp.yaml", fsys, &execCfg, map[string]interface{}{}, nil)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedError)
+			assert.Contains(t, err.Error(), "1 unknown key(s)")
+		})
+	}
+}
@@ -0,0 +1,359 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package blocks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/facebookincubator/ttpforge/pkg/logging"
+	"github.com/facebookincubator/ttpforge/pkg/processutils"
+)
+
+const (
+	// DefaultReadyTimeout is how long start_process steps
+	// wait for their process to become ready by default
+	DefaultReadyTimeout = 30 * time.Second
+
+	// readyPollInterval is how often the readiness
+	// conditions of start_process steps are checked
+	readyPollInterval = 100 * time.Millisecond
+)
+
+// StartProcessStep launches a command in the background so that
+// later steps can act against it - for example a listener, a service
+// or a stand-in for an implant. The step completes once the process
+// is ready: when its output matches ReadyWhen and/or ReadyPort accepts
+// connections (or immediately, if neither is specified). The PID of
+// the process is recorded as the `pid` output of the step (and its
+// start time as `start_time`) and the default cleanup action kills
+// the process along with its descendants.
+type StartProcessStep struct {
+	actionDefaults `yaml:",inline"`
+	ExecutorName   string            `yaml:"executor,omitempty"`
+	Inline         string            `yaml:"start_process,omitempty"`
+	Environment    map[string]string `yaml:"env,omitempty"`
+	ReadyWhen      string            `yaml:"ready_when,omitempty"`
+	ReadyPort      int               `yaml:"ready_port,omitempty"`
+	ReadyTimeout   Timeout           `yaml:"ready_timeout,omitempty"`
+
+	readyRegexp *regexp.Regexp
+	pid         int
+	// startTime identifies the process along with its PID
+	// (see processutils.GetStartTime) - it is 0 if unknown
+	startTime int64
+	// exited is closed once the process has exited
+	exited chan struct{}
+}
+
+// NewStartProcessStep creates a new StartProcessStep instance and returns a pointer to it.
+func NewStartProcessStep() *StartProcessStep {
+	return &StartProcessStep{}
+}
+
+// IsNil checks if the step is nil or empty and returns a boolean value.
+func (s *StartProcessStep) IsNil() bool {
+	switch {
+	case s.Inline == "":
+		return true
+	default:
+		return false
+	}
+}
+
+// Validate validates the step, checking for the necessary attributes and dependencies.
+func (s *StartProcessStep) Validate(execCtx TTPExecutionContext) error {
+	if s.Inline == "" {
+		return errors.New("start_process must be provided")
+	}
+	if s.ReadyPort < 0 || s.ReadyPort > 65535 {
+		return fmt.Errorf("invalid ready_port %d - must be between 1 and 65535", s.ReadyPort)
+	}
+	if s.ReadyTimeout < 0 {
+		return fmt.Errorf("ready_timeout must not be negative")
+	}
+	if !execCtx.containsStepTemplating(s.ReadyWhen) {
+		if err := s.compileReadyWhen(); err != nil {
+			return err
+		}
+	}
+
+	if s.ExecutorName == "" {
+		logging.L().Debug("defaulting to bash since executor was not provided")
+		s.ExecutorName = ExecutorBash
+		return nil
+	}
+	if _, err := exec.LookPath(s.ExecutorName); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Template takes each applicable field in the step and replaces any template strings with their resolved values.
+//
+// **Returns:**
+//
+// error: error if template resolution fails, nil otherwise
+func (s *StartProcessStep) Template(execCtx TTPExecutionContext) error {
+	var err error
+	s.Inline, err = execCtx.templateStep(s.Inline)
+	if err != nil {
+		return err
+	}
+	if execCtx.containsStepTemplating(s.ReadyWhen) {
+		s.ReadyWhen, err = execCtx.templateStep(s.ReadyWhen)
+		if err != nil {
+			return err
+		}
+	}
+	return s.compileReadyWhen()
+}
+
+// compileReadyWhen compiles the ready_when regular expression (if any)
+func (s *StartProcessStep) compileReadyWhen() error {
+	if s.ReadyWhen == "" {
+		s.readyRegexp = nil
+		return nil
+	}
+	// ^ and $ match at the start and end of each line of output
+	re, err := regexp.Compile("(?m)" + s.ReadyWhen)
+	if err != nil {
+		return fmt.Errorf("invalid ready_when regular expression %q: %w", s.ReadyWhen, err)
+	}
+	s.readyRegexp = re
+	return nil
+}
+
+// Execute starts the process and waits for it to become ready.
+func (s *StartProcessStep) Execute(execCtx TTPExecutionContext) (*ActResult, error) {
+	// the process must outlive the step,
+	// so it is not bound to the step context
+	executor := &ScriptExecutor{Name: s.ExecutorName, Inline: s.Inline, Environment: s.Environment}
+	cmd, err := executor.prepareCommand(context.Background(), execCtx)
+	if err != nil {
+		return nil, err
+	}
+	startInProcessGroup(cmd)
+	cmd.WaitDelay = processGroupWaitDelay
+
+	proc, err := startAndCapture(cmd, execCtx.Cfg.Stdout, execCtx.Cfg.Stderr)
+	if err != nil {
+		return nil, err
+	}
+	s.pid = cmd.Process.Pid
+	s.exited = make(chan struct{})
+	var exitErr error
+	go func() {
+		_, exitErr = proc.wait()
+		close(s.exited)
+	}()
+	logging.L().Infof("Started process %d", s.pid)
+	if s.startTime, err = processutils.GetStartTime(s.pid); err != nil {
+		logging.L().Warnf("Could not determine start time of process %d - `ttpforge cleanup` will not be able to stop it: %v", s.pid, err)
+	}
+
+	if err := s.waitUntilReady(execCtx, proc); err != nil {
+		select {
+		case <-s.exited:
+			if exitErr != nil {
+				err = fmt.Errorf("%w: %v", err, exitErr)
+			}
+		default:
+			if killErr := s.stop(); killErr != nil {
+				logging.L().Warnf("Failed to stop process %d: %v", s.pid, killErr)
+			}
+		}
+		return nil, err
+	}
+
+	// the output of the process is still
+	// logged, but is no longer captured
+	stdout, stderr := proc.output()
+	proc.stopCapture()
+
+	if s.OutputVar != "" {
+		execCtx.Vars.StepVars[s.OutputVar] = strconv.Itoa(s.pid)
+	}
+	return &ActResult{
+		Stdout:  stdout,
+		Stderr:  stderr,
+		Outputs: map[string]interface{}{"pid": s.pid, "start_time": s.startTime},
+	}, nil
+}
+
+// waitUntilReady waits until every readiness condition of the step
+// holds - it fails if the process exits, the readiness timeout
+// expires or the step is cancelled first
+func (s *StartProcessStep) waitUntilReady(execCtx TTPExecutionContext, proc *capturedCommand) error {
+	if s.readyRegexp == nil && s.ReadyPort == 0 {
+		return nil
+	}
+	timeout := s.ReadyTimeout.Duration()
+	if timeout == 0 {
+		timeout = DefaultReadyTimeout
+	}
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(readyPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if s.isReady(proc) {
+			logging.L().Infof("Process %d is ready", s.pid)
+			return nil
+		}
+		select {
+		case <-s.exited:
+			return fmt.Errorf("process %d exited before becoming ready", s.pid)
+		case <-deadline.C:
+			return fmt.Errorf("process %d did not become ready within %v", s.pid, timeout)
+		case <-execCtx.Context().Done():
+			return fmt.Errorf("process %d did not become ready: %w", s.pid, execCtx.Context().Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// isReady checks the readiness conditions of the step
+func (s *StartProcessStep) isReady(proc *capturedCommand) bool {
+	if s.readyRegexp != nil {
+		stdout, stderr := proc.output()
+		if !s.readyRegexp.MatchString(stdout) && !s.readyRegexp.MatchString(stderr) {
+			return false
+		}
+	}
+	if s.ReadyPort != 0 {
+		addr := net.JoinHostPort("localhost", strconv.Itoa(s.ReadyPort))
+		conn, err := net.DialTimeout("tcp", addr, readyPollInterval)
+		if err != nil {
+			return false
+		}
+		conn.Close()
+	}
+	return true
+}
+
+// stop kills the process along with its descendants
+// and waits for it to exit, if it was started by this run
+func (s *StartProcessStep) stop() error {
+	if s.pid == 0 {
+		return nil
+	}
+	if s.exited == nil && !s.isOriginalProcess() {
+		logging.L().Warnf("Process %d is not the process started by this step (its PID may have been reused) - not stopping it", s.pid)
+		return nil
+	}
+	logging.L().Infof("Stopping process %d and its descendants", s.pid)
+	err := killProcessTree(s.pid)
+	if s.exited == nil {
+		// restored from the journal of an earlier run
+		if errors.Is(err, os.ErrProcessDone) {
+			logging.L().Infof("Process %d has already exited", s.pid)
+			return nil
+		}
+		return err
+	}
+	select {
+	case <-s.exited:
+		return nil
+	case <-time.After(processGroupWaitDelay):
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("process %d did not exit after being killed", s.pid)
+	}
+}
+
+// isOriginalProcess checks that the process with the PID restored
+// from the journal of an earlier run is still the process started
+// by that run, rather than an unrelated process that reused its PID
+func (s *StartProcessStep) isOriginalProcess() bool {
+	startTime, err := processutils.GetStartTime(s.pid)
+	if errors.Is(err, processutils.ErrProcessNotRunning) {
+		// the PID is not in use, so any process group with
+		// the same ID only contains descendants of the process
+		return true
+	}
+	if err != nil {
+		logging.L().Debugf("Could not determine start time of process %d: %v", s.pid, err)
+		return false
+	}
+	return s.startTime != 0 && startTime == s.startTime
+}
+
+// restorePID records the PID and start time of the process started
+// by an earlier run of the step, so that it can be cleaned up
+func (s *StartProcessStep) restorePID(result *ExecutionResult) {
+	switch pid := result.Outputs["pid"].(type) {
+	case int:
+		s.pid = pid
+	case float64:
+		s.pid = int(pid)
+	}
+	switch startTime := result.Outputs["start_time"].(type) {
+	case int64:
+		s.startTime = startTime
+	case float64:
+		s.startTime = int64(startTime)
+	}
+}
+
+// GetDefaultCleanupAction will instruct the calling code
+// to kill the process tree started by this action
+func (s *StartProcessStep) GetDefaultCleanupAction() Action {
+	return &stopProcessAction{step: s}
+}
+
+// stopProcessAction is the default cleanup action of
+// start_process steps - it is not a user-accessible step type
+type stopProcessAction struct {
+	actionDefaults
+	step *StartProcessStep
+}
+
+// IsNil is not needed here, as this is not a user-accessible step type
+func (a *stopProcessAction) IsNil() bool {
+	return false
+}
+
+// Validate is not needed here, as this is not a user-accessible step type
+func (a *stopProcessAction) Validate(_ TTPExecutionContext) error {
+	return nil
+}
+
+// Template is not needed here, as this is not a user-accessible step type
+func (a *stopProcessAction) Template(_ TTPExecutionContext) error {
+	return nil
+}
+
+// Execute kills the process tree started by the step
+func (a *stopProcessAction) Execute(_ TTPExecutionContext) (*ActResult, error) {
+	if err := a.step.stop(); err != nil {
+		return nil, fmt.Errorf("failed to stop process %d: %w", a.step.pid, err)
+	}
+	return &ActResult{}, nil
+}
@@ -0,0 +1,293 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package blocks
+
+import (
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestStartProcessValidate(t *testing.T) {
+	testCases := []struct {
+		name      string
+		content   string
+		wantError bool
+	}{
+		{
+			name: "valid step",
+			content: `
+start_process: sleep 60
+ready_when: "listening on \\d+"
+ready_port: 8080
+ready_timeout: 10s
+`,
+		},
+		{
+			name:      "invalid regular expression",
+			content:   "start_process: sleep 60\nready_when: \"(unclosed\"",
+			wantError: true,
+		},
+		{
+			name:      "invalid port",
+			content:   "start_process: sleep 60\nready_port: 70000",
+			wantError: true,
+		},
+		{
+			name:      "missing executor",
+			content:   "start_process: sleep 60\nexecutor: not-a-real-executor",
+			wantError: true,
+		},
+		{
+			name:    "templated regular expression",
+			content: "start_process: sleep 60\nready_when: \"{[{.StepVars.banner}]}\"",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var step StartProcessStep
+			require.NoError(t, yaml.Unmarshal([]byte(tc.content), &step))
+			err := step.Validate(NewTTPExecutionContext())
+			if tc.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestStartProcessExecute(t *testing.T) {
+	// a port that is already open, for
+	// the ready_port test cases
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	openPort := listener.Addr().(*net.TCPAddr).Port
+
+	testCases := []struct {
+		name           string
+		content        string
+		stepVars       map[string]interface{}
+		expectedStdout string
+		wantError      bool
+	}{
+		{
+			name: "ready when output matches",
+			content: `
+start_process: |
+  echo "starting"
+  sleep 1
+  echo "listening on 4444"
+  sleep 300
+ready_when: listening on \d+
+outputvar: listener_pid
+`,
+			expectedStdout: "starting\nlistening on 4444\n",
+		},
+		{
+			name: "ready when templated output matches",
+			content: `
+start_process: echo "ready"; sleep 300
+ready_when: "{[{.StepVars.banner}]}"
+`,
+			stepVars:       map[string]interface{}{"banner": "^ready$"},
+			expectedStdout: "ready\n",
+		},
+		{
+			name: "ready when output matches on stderr",
+			content: `
+start_process: echo "ready" >&2; sleep 300
+ready_when: ready
+`,
+			expectedStdout: "",
+		},
+		{
+			name: "ready when port is open",
+			content: `
+start_process: sleep 300
+ready_port: ` + strconv.Itoa(openPort),
+			expectedStdout: "",
+		},
+		{
+			name:           "ready immediately without conditions",
+			content:        "start_process: sleep 300",
+			expectedStdout: "",
+		},
+		{
+			name: "process exits before becoming ready",
+			content: `
+start_process: echo "starting"; exit 3
+ready_when: listening
+`,
+			wantError: true,
+		},
+		{
+			name: "process never becomes ready",
+			content: `
+start_process: sleep 300
+ready_when: listening
+ready_timeout: 1s
+`,
+			wantError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var step StartProcessStep
+			require.NoError(t, yaml.Unmarshal([]byte(tc.content), &step))
+
+			execCtx := NewTTPExecutionContext()
+			if tc.stepVars != nil {
+				execCtx.Vars.StepVars = tc.stepVars
+			}
+			require.NoError(t, step.Validate(execCtx))
+			require.NoError(t, step.Template(execCtx))
+
+			result, err := step.Execute(execCtx)
+			if tc.wantError {
+				require.Error(t, err)
+				// the process must not outlive a failed step
+				select {
+				case <-step.exited:
+				case <-time.After(processGroupWaitDelay):
+					t.Fatal("process is still running after the step failed")
+				}
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedStdout, result.Stdout)
+			assert.Equal(t, step.pid, result.Outputs["pid"])
+			if step.OutputVar != "" {
+				assert.Equal(t, strconv.Itoa(step.pid), execCtx.Vars.StepVars[step.OutputVar])
+			}
+
+			// the process keeps running after the step completes
+			select {
+			case <-step.exited:
+				t.Fatal("process exited after the step completed")
+			default:
+			}
+
+			// the default cleanup kills the process
+			// along with its descendants
+			_, err = step.GetDefaultCleanupAction().Execute(execCtx)
+			require.NoError(t, err)
+			select {
+			case <-step.exited:
+			default:
+				t.Fatal("process is still running after cleanup")
+			}
+		})
+	}
+}
+
+func TestStartProcessCleanupAfterRestore(t *testing.T) {
+	var step StartProcessStep
+	require.NoError(t, yaml.Unmarshal([]byte("start_process: sleep 300 & sleep 300\nready_when: ''"), &step))
+	execCtx := NewTTPExecutionContext()
+	require.NoError(t, step.Validate(execCtx))
+	require.NoError(t, step.Template(execCtx))
+	result, err := step.Execute(execCtx)
+	require.NoError(t, err)
+	pid := step.pid
+
+	// a process with the same PID but another start
+	// time is not the process started by the step
+	var reused StartProcessStep
+	reused.restorePID(&ExecutionResult{ActResult: ActResult{
+		Outputs: map[string]interface{}{
+			"pid":        float64(pid),
+			"start_time": float64(result.Outputs["start_time"].(int64) - 60000),
+		},
+	}})
+	_, err = reused.GetDefaultCleanupAction().Execute(execCtx)
+	require.NoError(t, err)
+	select {
+	case <-step.exited:
+		t.Fatal("process was stopped although its start time does not match")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	// as if the step had been loaded from the journal
+	// of the run, with the PID from its JSON result
+	var restored StartProcessStep
+	restored.restorePID(&ExecutionResult{ActResult: ActResult{
+		Outputs: map[string]interface{}{
+			"pid":        float64(result.Outputs["pid"].(int)),
+			"start_time": float64(result.Outputs["start_time"].(int64)),
+		},
+	}})
+	require.Equal(t, pid, restored.pid)
+	require.NotZero(t, restored.startTime)
+
+	_, err = restored.GetDefaultCleanupAction().Execute(execCtx)
+	require.NoError(t, err)
+	select {
+	case <-step.exited:
+	case <-time.After(processGroupWaitDelay):
+		t.Fatal("process is still running after cleanup")
+	}
+
+	// cleaning up a process that has already exited succeeds
+	_, err = restored.GetDefaultCleanupAction().Execute(execCtx)
+	assert.NoError(t, err)
+}
+
+func TestStartProcessInTTP(t *testing.T) {
+	content := `name: start_process_ttp
+steps:
+  - name: listener
+    start_process: |
+      echo "listening"
+      sleep 300
+    ready_when: listening
+  - name: check
+    inline: kill -0 {[{ .Steps.listener.Outputs.pid }]} && echo "alive"
+`
+	var ttp TTP
+	require.NoError(t, yaml.Unmarshal([]byte(content), &ttp))
+	execCtx := NewTTPExecutionContext()
+	require.NoError(t, ttp.Validate(execCtx))
+	require.NoError(t, ttp.Execute(execCtx))
+	assert.Equal(t, "alive\n", execCtx.StepResults.ByName["check"].Stdout)
+
+	listener, ok := ttp.Steps[0].action.(*StartProcessStep)
+	require.True(t, ok)
+	select {
+	case <-listener.exited:
+		t.Fatal("process exited before cleanup")
+	default:
+	}
+	// the process is stopped even though the step has no `cleanup:`
+	require.NoError(t, ttp.RunCleanup(execCtx))
+	select {
+	case <-listener.exited:
+	case <-time.After(processGroupWaitDelay):
+		t.Fatal("process is still running after cleanup")
+	}
+}
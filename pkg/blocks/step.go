@@ -20,6 +20,7 @@ THE SOFTWARE.
 package blocks
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -35,6 +36,17 @@ import (
 type CommonStepFields struct {
 	Name   string         `yaml:"name,omitempty"`
 	Checks []checks.Check `yaml:"checks,omitempty"`
+	// If is a condition evaluated at run time - the step
+	// is skipped when it does not hold
+	If    string       `yaml:"if,omitempty"`
+	Retry *RetryPolicy `yaml:"retry,omitempty"`
+	// Timeout bounds the execution time of the step action -
+	// when it is zero, TTPExecutionConfig.StepTimeout is used
+	Timeout Timeout `yaml:"timeout,omitempty"`
+	// Loop (or its alias ForEach) repeats the step
+	// once for each of the specified items
+	Loop    *LoopSpec `yaml:"loop,omitempty"`
+	ForEach *LoopSpec `yaml:"for_each,omitempty"`
 
 	// CleanupSpec is exported so that UnmarshalYAML
 	// can see it - however, it should be considered
@@ -78,21 +90,67 @@ func isDefaultCleanup(cleanupNode *yaml.Node) (bool, error) {
 // However, certain step types (especially SubTTPs) need to run cleanup even if they fail
 func (s *Step) ShouldCleanupOnFailure() bool {
 	switch s.action.(type) {
-	case *SubTTPStep:
+	case *SubTTPStep, *ParallelStep, *loopAction:
 		return true
 	default:
 		return false
 	}
 }
 
+// childResults returns the results of the steps nested inside
+// a parallel, loop or sub-TTP step, in declaration order
+func (s *Step) childResults() []*ExecutionResult {
+	switch action := s.action.(type) {
+	case *ParallelStep:
+		return action.ChildResults()
+	case *loopAction:
+		return action.ChildResults()
+	case *SubTTPStep:
+		if action.subExecCtx != nil {
+			return action.subExecCtx.StepResults.ByIndex
+		}
+	}
+	return nil
+}
+
+// restoreResult re-associates a step with the result of
+// a previous execution of that step (for example one loaded
+// from a run journal) so that it can be cleaned up and
+// referenced by subsequent steps
+func (s *Step) restoreResult(execCtx TTPExecutionContext, result *ExecutionResult) {
+	switch action := s.action.(type) {
+	case *ParallelStep:
+		action.results = make([]*ExecutionResult, len(action.Steps))
+		for idx, child := range result.Children {
+			if idx >= len(action.Steps) || child == nil {
+				break
+			}
+			action.results[idx] = child
+			execCtx.StepResults.ByName[child.Name] = child
+			action.Steps[idx].restoreResult(execCtx, child)
+		}
+	case *loopAction:
+		action.restoreIterations(execCtx, result.Children)
+	case *StartProcessStep:
+		action.restorePID(result)
+	case *SubTTPStep:
+		if action.subExecCtx != nil {
+			action.subExecCtx.StepResults = NewStepResultsRecord()
+			action.ttp.restoreResults(*action.subExecCtx, result.Children)
+		}
+	}
+}
+
 // ShouldUseImplicitDefaultCleanup is a hack
 // to make subTTPs always run their default
 // cleanup process even when `cleanup: default` is
 // not explicitly specified - this is purely for backward
-// compatibility
+// compatibility. Parallel and loop steps follow the same convention
+// so that the cleanup of their children is never skipped, as do
+// start_process steps so that their processes are always stopped.
 func ShouldUseImplicitDefaultCleanup(action Action) bool {
 	switch action.(type) {
-	case *SubTTPStep:
+	case *SubTTPStep, *ParallelStep, *loopAction, *StartProcessStep:
 		return true
 	default:
 		return false
@@ -117,6 +175,10 @@ func (s *Step) UnmarshalYAML(node *yaml.Node) error {
 		return errors.New("no name specified for step")
 	}
 
+	if s.Loop != nil || s.ForEach != nil {
+		return s.unmarshalLoop(node)
+	}
+
 	// figure out what kind of action is
 	// associated with executing this step
 	s.action, err = s.ParseAction(node)
@@ -124,6 +186,15 @@ func (s *Step) UnmarshalYAML(node *yaml.Node) error {
 		return fmt.Errorf("could not parse action for step %q: %w", s.Name, err)
 	}
 
+	// a bare number of seconds in the `timeout:` of an expect
+	// step is the timeout of each prompt, as it was before
+	// step timeouts existed - it does not bound the whole step
+	if _, ok := s.action.(*ExpectStep); ok {
+		if _, legacy := legacyPromptTimeout(node); legacy {
+			s.Timeout = 0
+		}
+	}
+
 	// figure out what kind of action is
 	// associated with cleaning up this step
 	if csf.CleanupSpec.IsZero() {
@@ -155,6 +226,14 @@ func (s *Step) UnmarshalYAML(node *yaml.Node) error {
 // Validate checks that both the step action and cleanup
 // action are valid
 func (s *Step) Validate(execCtx TTPExecutionContext) error {
+	if s.Timeout < 0 {
+		return fmt.Errorf("timeout of step %q must not be negative", s.Name)
+	}
+	if s.Retry != nil {
+		if err := s.Retry.Validate(); err != nil {
+			return fmt.Errorf("invalid retry policy for step %q: %w", s.Name, err)
+		}
+	}
 	if err := s.action.Validate(execCtx); err != nil {
 		return err
 	}
@@ -174,7 +253,47 @@ func (s *Step) Template(execCtx TTPExecutionContext) error {
 	return nil
 }
 
-// Execute runs the action associated with this step and sends result/error to channels of the context
+// ShouldRun evaluates the `if:` condition of the step (if any)
+// and reports whether the step should be executed
+//
+// **Parameters:**
+//
+// execCtx: The current TTPExecutionContext
+//
+// **Returns:**
+//
+// bool: false if the step should be skipped
+// error: an error if the condition could not be evaluated
+func (s *Step) ShouldRun(execCtx TTPExecutionContext) (bool, error) {
+	if s.If == "" {
+		return true, nil
+	}
+	shouldRun, err := execCtx.evaluateCondition(s.If)
+	if err != nil {
+		return false, fmt.Errorf("could not evaluate `if` condition of step %q: %w", s.Name, err)
+	}
+	return shouldRun, nil
+}
+
+// contextWithTimeout returns a copy of the execution context whose
+// Context() expires once the timeout of this step (or the
+// default step timeout, if the step has none) elapses
+func (s *Step) contextWithTimeout(execCtx TTPExecutionContext) (TTPExecutionContext, context.CancelFunc) {
+	timeout := s.Timeout.Duration()
+	// the default timeout applies to each
+	// iteration of a loop, not to the whole loop
+	if _, isLoop := s.action.(*loopAction); timeout == 0 && !isLoop {
+		timeout = execCtx.Cfg.StepTimeout
+	}
+	if timeout == 0 {
+		ctx, cancel := context.WithCancel(execCtx.Context())
+		return execCtx.withContext(ctx), cancel
+	}
+	ctx, cancel := context.WithTimeout(execCtx.Context(), timeout)
+	return execCtx.withContext(ctx), cancel
+}
+
+// Execute runs the action associated with this step
 func (s *Step) Execute(execCtx TTPExecutionContext) (*ActResult, error) {
 	desc := s.action.GetDescription()
 	if desc != "" {
@@ -183,15 +302,19 @@ func (s *Step) Execute(execCtx TTPExecutionContext) (*ActResult, error) {
 	result, err := s.action.Execute(execCtx)
 	if err != nil {
 		logging.L().Errorf("Failed to execute step %v: %v", s.Name, err)
-		execCtx.errorsChan <- err
 	} else {
 		logging.L().Debugf("Successfully executed step %v", s.Name)
-		execCtx.actionResultsChan <- result
 	}
 
 	return result, err
 }
 
+// ActionType returns the type name of the action
+// associated with this step (such as `inline`)
+func (s *Step) ActionType() string {
+	return ActionTypeName(s.action)
+}
+
 // Cleanup runs the cleanup action associated with this step
 func (s *Step) Cleanup(execCtx TTPExecutionContext) (*ActResult, error) {
 	if s.cleanup != nil {
@@ -212,11 +335,12 @@ func (s *Step) Cleanup(execCtx TTPExecutionContext) (*ActResult, error) {
 // format into the appropriate struct
 func (s *Step) ParseAction(node *yaml.Node) (Action, error) {
 	var typeField struct {
-		Inline    string     `yaml:"inline"`
-		File      string     `yaml:"file"`
-		TTP       string     `yaml:"ttp"`
-		EditFile  string     `yaml:"edit_file"`
-		Responses []Response `yaml:"responses"`
+		Inline    string      `yaml:"inline"`
+		File      string      `yaml:"file"`
+		TTP       string      `yaml:"ttp"`
+		EditFile  string      `yaml:"edit_file"`
+		Responses []Response  `yaml:"responses"`
+		Parallel  []yaml.Node `yaml:"parallel"`
 	}
 
 	if err := node.Decode(&typeField); err != nil {
@@ -237,10 +361,23 @@ func (s *Step) ParseAction(node *yaml.Node) (Action, error) {
 	if typeField.EditFile != "" {
 		typesCount++
 	}
+	if len(typeField.Parallel) > 0 {
+		typesCount++
+	}
 	if typesCount > 1 {
 		return nil, fmt.Errorf("step %v has ambiguous type", s.Name)
 	}
 
+	// Decode parallel groups directly so that errors
+	// in the child steps are reported to the user
+	if len(typeField.Parallel) > 0 {
+		parallelStep := NewParallelStep()
+		if err := node.Decode(parallelStep); err != nil {
+			return nil, err
+		}
+		return parallelStep, nil
+	}
+
 	// Check for ExpectStep
 	if len(typeField.Responses) > 0 {
 		expectStep := NewExpectStep()
@@ -259,6 +396,8 @@ func (s *Step) ParseAction(node *yaml.Node) (Action, error) {
 		return basicStep, nil
 	}
 
+	// new action types must also be added to actionSchemaTypes
+	// so that they are described by the TTP schema
 	actionCandidates := []Action{
 		NewBasicStep(),
 		NewChangeDirectoryStep(),
@@ -267,12 +406,15 @@ func (s *Step) ParseAction(node *yaml.Node) (Action, error) {
 		NewEditStep(),
 		NewFetchURIStep(),
 		NewCreateFileStep(),
+		NewRenderTemplateStep(),
 		NewCopyPathStep(),
 		NewRemovePathAction(),
 		NewPrintStrAction(),
 		NewExpectStep(),
 		NewHTTPRequestStep(),
 		NewKillProcessStep(),
+		NewStartProcessStep(),
+		NewWaitForStep(),
 	}
 
 	var action Action
@@ -301,20 +443,25 @@ func (s *Step) ParseAction(node *yaml.Node) (Action, error) {
 	return action, nil
 }
 
-// VerifyChecks runs all checks and returns an error if any of them fail
-func (s *Step) VerifyChecks() error {
+// VerifyChecks runs all checks and returns an error if any of them fail.
+// The outcome of every check that was run is also returned so that
+// it can be recorded in the execution results.
+func (s *Step) VerifyChecks() ([]CheckResult, error) {
 	if len(s.Checks) == 0 {
 		logging.L().Debugf("No checks defined for step %v", s.Name)
-		return nil
+		return nil, nil
 	}
 	verificationCtx := checks.VerificationContext{
 		FileSystem: afero.NewOsFs(),
 	}
+	var results []CheckResult
 	for checkIdx, check := range s.Checks {
 		if err := check.Verify(verificationCtx); err != nil {
-			return fmt.Errorf("success check %d of step %q failed: %w", checkIdx+1, s.Name, err)
+			results = append(results, CheckResult{Msg: check.Msg, Error: err.Error()})
+			return results, fmt.Errorf("success check %d of step %q failed: %w", checkIdx+1, s.Name, err)
 		}
+		results = append(results, CheckResult{Msg: check.Msg, Passed: true})
 		logging.L().Debugf("Success check %d (%q) of step %q PASSED", checkIdx+1, check.Msg, s.Name)
 	}
-	return nil
+	return results, nil
 }
@@ -42,7 +42,7 @@ func TestStep(t *testing.T) {
 		expectedExecuteStdout string
 		wantCleanupError      bool
 		expectedCleanupStdout string
-		stepVars              map[string]string
+		stepVars              map[string]interface{}
 	}{
 		{
 			name: "Run inline command (no error)",
@@ -118,7 +118,7 @@ inline: echo {[{.StepVars.run_message}]}
 cleanup:
   inline: echo {[{.StepVars.cleanup_message}]}
 `,
-			stepVars: map[string]string{
+			stepVars: map[string]interface{}{
 				"run_message":     "this is a run",
 				"cleanup_message": "this is a cleanup",
 			},
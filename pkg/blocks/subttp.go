@@ -91,10 +91,14 @@ func (s *SubTTPStep) Template(execCtx TTPExecutionContext) error {
 
 // Execute runs each step of the TTP file associated with the SubTTPStep
 // and manages the outputs and cleanup steps.
-func (s *SubTTPStep) Execute(_ TTPExecutionContext) (*ActResult, error) {
+func (s *SubTTPStep) Execute(execCtx TTPExecutionContext) (*ActResult, error) {
 	logging.L().Infof("[*] Executing Sub TTP: %s", s.TtpRef)
+	// discard the results of any previous (failed) attempt
+	// so that they are not cleaned up a second time
+	s.subExecCtx.StepResults = NewStepResultsRecord()
 	logging.IncreaseIndentLevel()
-	runErr := s.ttp.RunSteps(*s.subExecCtx)
+	// the steps of the subTTP are bound by the timeout of this step
+	runErr := s.ttp.RunSteps(s.subExecCtx.withContext(execCtx.Context()))
 	if runErr != nil {
 		return &ActResult{}, runErr
 	}
@@ -121,6 +125,10 @@ func aggregateResults(results []*ActResult) *ActResult {
 	var subStdouts []string
 	var subStderrs []string
 	for _, result := range results {
+		// steps that were not cleaned up have no result
+		if result == nil {
+			continue
+		}
 		subStdouts = append(subStdouts, result.Stdout)
 		subStderrs = append(subStderrs, result.Stderr)
 	}
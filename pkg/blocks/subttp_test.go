@@ -75,7 +75,7 @@ func TestSubTTPExecution(t *testing.T) {
 		spec                 repos.Spec
 		fsys                 afero.Fs
 		stepYAML             string
-		stepVars             map[string]string
+		stepVars             map[string]interface{}
 		expectValidationErr  bool
 		expectTemplateError  bool
 		expectExecutionError bool
@@ -130,7 +130,7 @@ args:
   arg_number_one: "{[{.StepVars.arg1}]}"
   arg_number_two: world`,
 			expectedOutput: "hello world victory",
-			stepVars: map[string]string{
+			stepVars: map[string]interface{}{
 				"arg1": "hello",
 			},
 		},
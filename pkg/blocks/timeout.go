@@ -0,0 +1,61 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package blocks
+
+import (
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Timeout is a duration that can be written in YAML either as an
+// integer number of seconds (`timeout: 30`) or as a duration
+// string (`timeout: 1m30s`)
+type Timeout time.Duration
+
+// UnmarshalYAML decodes a Timeout from seconds or a duration string
+func (t *Timeout) UnmarshalYAML(node *yaml.Node) error {
+	var seconds int
+	if err := node.Decode(&seconds); err == nil {
+		*t = Timeout(time.Duration(seconds) * time.Second)
+		return nil
+	}
+	var durationStr string
+	if err := node.Decode(&durationStr); err != nil {
+		return fmt.Errorf("expected a number of seconds or a duration string: %w", err)
+	}
+	d, err := time.ParseDuration(durationStr)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q: %w", durationStr, err)
+	}
+	*t = Timeout(d)
+	return nil
+}
+
+// MarshalYAML encodes a Timeout as a duration string
+func (t Timeout) MarshalYAML() (interface{}, error) {
+	return time.Duration(t).String(), nil
+}
+
+// Duration returns the timeout as a time.Duration
+func (t Timeout) Duration() time.Duration {
+	return time.Duration(t)
+}
@@ -0,0 +1,286 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package blocks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestTimeoutUnmarshal(t *testing.T) {
+	testCases := []struct {
+		name            string
+		content         string
+		expectedTimeout time.Duration
+		wantError       bool
+	}{
+		{
+			name:            "Seconds",
+			content:         `timeout: 30`,
+			expectedTimeout: 30 * time.Second,
+		},
+		{
+			name:            "Duration String",
+			content:         `timeout: 1m30s`,
+			expectedTimeout: 90 * time.Second,
+		},
+		{
+			name:      "Invalid Duration",
+			content:   `timeout: forever`,
+			wantError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var container struct {
+				Timeout Timeout `yaml:"timeout"`
+			}
+			err := yaml.Unmarshal([]byte(tc.content), &container)
+			if tc.wantError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedTimeout, container.Timeout.Duration())
+		})
+	}
+}
+
+func TestExpectStepTimeout(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		timeoutFields         string
+		expectedPromptTimeout time.Duration
+		expectedStepTimeout   time.Duration
+	}{
+		{
+			name:                  "Legacy Timeout In Seconds",
+			timeoutFields:         "timeout: 30",
+			expectedPromptTimeout: 30 * time.Second,
+		},
+		{
+			name:                "Step Timeout Duration",
+			timeoutFields:       "timeout: 1m",
+			expectedStepTimeout: time.Minute,
+		},
+		{
+			name:                  "Prompt And Step Timeouts",
+			timeoutFields:         "timeout: 1m\nprompt_timeout: 10s",
+			expectedPromptTimeout: 10 * time.Second,
+			expectedStepTimeout:   time.Minute,
+		},
+		{
+			name:                  "Prompt Timeout In Seconds",
+			timeoutFields:         "prompt_timeout: 5",
+			expectedPromptTimeout: 5 * time.Second,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			content := `name: expect_step
+` + tc.timeoutFields + `
+expect:
+  inline: echo hello
+  responses:
+    - prompt: "hello"
+      response: "hi"`
+			var step Step
+			require.NoError(t, yaml.Unmarshal([]byte(content), &step))
+			expectStep, ok := step.action.(*ExpectStep)
+			require.True(t, ok)
+			assert.Equal(t, tc.expectedPromptTimeout, expectStep.PromptTimeout.Duration())
+			assert.Equal(t, tc.expectedStepTimeout, step.Timeout.Duration())
+		})
+	}
+}
+
+func TestStepTimeoutExecution(t *testing.T) {
+	testCases := []struct {
+		name               string
+		content            string
+		defaultTimeout     time.Duration
+		expectExecuteError bool
+		expectedStatus     StepStatus
+		expectedAttempts   int
+	}{
+		{
+			name: "Step Finishes Within Timeout",
+			content: `name: test
+steps:
+  - name: target
+    timeout: 5s
+    inline: echo done`,
+			expectedStatus:   StepStatusSucceeded,
+			expectedAttempts: 1,
+		},
+		{
+			name: "Step Timeout Kills Process Group",
+			content: `name: test
+steps:
+  - name: target
+    timeout: 300ms
+    inline: |
+      sleep 30 &
+      sleep 30
+    cleanup:
+      inline: echo should not run`,
+			expectExecuteError: true,
+			expectedStatus:     StepStatusTimeout,
+			expectedAttempts:   1,
+		},
+		{
+			name: "Default Step Timeout",
+			content: `name: test
+steps:
+  - name: target
+    inline: sleep 30`,
+			defaultTimeout:     300 * time.Millisecond,
+			expectExecuteError: true,
+			expectedStatus:     StepStatusTimeout,
+			expectedAttempts:   1,
+		},
+		{
+			name: "Retry On Timeout",
+			content: `name: test
+steps:
+  - name: target
+    timeout: 200ms
+    inline: sleep 30
+    retry:
+      attempts: 2
+      retry_on: timeout`,
+			expectExecuteError: true,
+			expectedStatus:     StepStatusTimeout,
+			expectedAttempts:   2,
+		},
+		{
+			name: "Expect Step Timeout Bounds Whole Command",
+			content: `name: test
+steps:
+  - name: target
+    timeout: 1s
+    expect:
+      inline: |
+        echo "ready?"
+        read answer
+        sleep 30
+      responses:
+        - prompt: "ready"
+          response: "yes"`,
+			expectExecuteError: true,
+			expectedStatus:     StepStatusTimeout,
+			expectedAttempts:   1,
+		},
+		{
+			name: "Expect Step Outlasts Prompt Timeout",
+			content: `name: test
+steps:
+  - name: target
+    timeout: 1m
+    prompt_timeout: 1
+    expect:
+      inline: |
+        sleep 0.6
+        echo "first?"
+        read first
+        sleep 0.6
+        echo "second?"
+        read second
+      responses:
+        - prompt: "first"
+          response: "one"
+        - prompt: "second"
+          response: "two"`,
+			expectedStatus:   StepStatusSucceeded,
+			expectedAttempts: 1,
+		},
+		{
+			name: "Legacy Expect Timeout Bounds Each Prompt",
+			content: `name: test
+steps:
+  - name: target
+    timeout: 1
+    expect:
+      inline: |
+        sleep 0.6
+        echo "first?"
+        read first
+        sleep 0.6
+        echo "second?"
+        read second
+      responses:
+        - prompt: "first"
+          response: "one"
+        - prompt: "second"
+          response: "two"`,
+			expectedStatus:   StepStatusSucceeded,
+			expectedAttempts: 1,
+		},
+		{
+			name: "Timeout In Parallel Child",
+			content: `name: test
+steps:
+  - name: group
+    parallel:
+      - name: target
+        timeout: 300ms
+        inline: sleep 30
+      - name: sibling
+        inline: echo sibling`,
+			expectExecuteError: true,
+			expectedStatus:     StepStatusTimeout,
+			expectedAttempts:   1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var ttp TTP
+			require.NoError(t, yaml.Unmarshal([]byte(tc.content), &ttp))
+			execCtx := NewTTPExecutionContext()
+			execCtx.Cfg.StepTimeout = tc.defaultTimeout
+			require.NoError(t, ttp.Validate(execCtx))
+
+			// without killing the whole process group, the
+			// background `sleep` would keep the output pipes open
+			// and the step would not return until WaitDelay expires
+			start := time.Now()
+			err := ttp.RunSteps(execCtx)
+			assert.Less(t, time.Since(start), 3*time.Second)
+			if tc.expectExecuteError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			result := execCtx.StepResults.ByName["target"]
+			require.NotNil(t, result)
+			assert.Equal(t, tc.expectedStatus, result.Status)
+			assert.Len(t, result.Attempts, tc.expectedAttempts)
+			assert.False(t, result.NeedsCleanup() && tc.expectedStatus == StepStatusTimeout)
+		})
+	}
+}
@@ -21,6 +21,7 @@ package blocks
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os"
 	"runtime"
@@ -28,6 +29,7 @@ import (
 
 	"github.com/facebookincubator/ttpforge/pkg/checks"
 	"github.com/facebookincubator/ttpforge/pkg/logging"
+	"github.com/facebookincubator/ttpforge/pkg/mitre"
 	"github.com/facebookincubator/ttpforge/pkg/platforms"
 	"gopkg.in/yaml.v3"
 )
@@ -37,15 +39,23 @@ import (
 //
 // **Attributes:**
 //
-// Environment: A map of environment variables to be set for the TTP.
+// Environment: A map of environment variables to be set for every step of the TTP.
+// EnvFile: A .env file (relative to the TTP) from which to read environment variables.
+// InheritEnv: Which variables of the environment of TTPForge are passed on to steps.
 // Steps: An slice of steps to be executed for the TTP.
 // WorkDir: The working directory for the TTP.
 type TTP struct {
 	PreambleFields `yaml:",inline"`
 	Environment    map[string]string `yaml:"env,flow,omitempty"`
+	EnvFile        string            `yaml:"env_file,omitempty"`
+	InheritEnv     InheritEnv        `yaml:"inherit_env,omitempty"`
 	Steps          []Step            `yaml:"steps,omitempty,flow"`
 	// Omit WorkDir, but expose for testing.
 	WorkDir string `yaml:"-"`
+
+	// rendered holds the YAML of the TTP after template rendering,
+	// which is persisted so that interrupted runs can be resumed
+	rendered []byte
 }
 
 // MitreAttack represents mappings to the MITRE ATT&CK framework.
@@ -61,6 +71,18 @@ type MitreAttack struct {
 	SubTechniques []string `yaml:"subtechniques,omitempty"`
 }
 
+// Validate checks the mapping against the MITRE ATT&CK catalogue:
+// every entry must start with a known ID (such as "T1055" or
+// "T1055 Process Injection"), each technique must belong to one of
+// the listed tactics and the parent of each sub-technique must be listed.
+//
+// **Returns:**
+//
+// error: an error listing every invalid entry
+func (ma *MitreAttack) Validate() error {
+	return mitre.Default().ValidateMapping(ma.Tactics, ma.Techniques, ma.SubTechniques)
+}
+
 // MarshalYAML is a custom marshalling implementation for the TTP structure.
 // It encodes a TTP object into a formatted YAML string, handling the
 // indentation and structure of the output YAML.
@@ -160,60 +182,56 @@ func (t *TTP) RunSteps(execCtx TTPExecutionContext) error {
 	var verifyError error
 	var shutdownFlag bool
 
-	// actually run all the steps
-	for stepIdx, step := range t.Steps {
+	// actually run all the steps - steps that already have results
+	// (because they completed before the run was resumed) are not run again
+	for stepIdx := len(execCtx.StepResults.ByIndex); stepIdx < len(t.Steps); stepIdx++ {
+		step := t.Steps[stepIdx]
 		logging.DividerThin()
 		logging.L().Infof("Executing Step #%d: %q", stepIdx+1, step.Name)
-		// core execution - run the step action
-		go func(step Step) {
-			err := step.Template((execCtx))
-			if err != nil {
-				logging.L().Errorf("Error templating step %s: %v", step.Name, err)
-			}
-			_, err = step.Execute(execCtx)
-			if err != nil {
-				// This error was logged by the step itself
-				logging.L().Debugf("Error executing step %s: %v", step.Name, err)
-			}
-		}(step)
+		execResult := &ExecutionResult{
+			Name:       step.Name,
+			ActionType: step.ActionType(),
+			StartTime:  time.Now(),
+		}
 
-		// await one of three outcomes:
-		// 1. step execution successful
-		// 2. step execution failed
-		// 3. shutdown signal received
-		select {
-		case stepResult := <-execCtx.actionResultsChan:
-			// step execution successful - record results
-			execResult := &ExecutionResult{
-				ActResult: *stepResult,
+		// evaluate the `if:` condition (if any) now that
+		// the results of the previous steps are available
+		shouldRun, err := step.ShouldRun(execCtx)
+		if err != nil || !shouldRun {
+			execResult.EndTime = time.Now()
+			if err != nil {
+				logging.L().Errorf("%v", err)
+				stepError = err
+				execResult.Status = StepStatusFailed
+				execResult.Error = err.Error()
+				execResult.ExitCode = exitCodeFromError(err)
+			} else {
+				logging.L().Infof("Skipping step %q because its condition is false: %v", step.Name, step.If)
+				execResult.Status = StepStatusSkipped
 			}
 			execCtx.StepResults.ByName[step.Name] = execResult
 			execCtx.StepResults.ByIndex = append(execCtx.StepResults.ByIndex, execResult)
-
-		case stepError = <-execCtx.errorsChan:
-			// this part is tricky - SubTTP steps
-			// must be cleaned up even on failure
-			// (because substeps may have succeeded)
-			// so in those cases, we need to save the result
-			// even if nil
-			if step.ShouldCleanupOnFailure() {
-				logging.L().Infof("[+] Cleaning up failed step %s", step.Name)
-				logging.L().Infof("[+] Full Cleanup will Run Afterward")
-				_, cleanupErr := step.Cleanup(execCtx)
-				if cleanupErr != nil {
-					logging.L().Errorf("Error cleaning up failed step %v: %v", step.Name, cleanupErr)
-				}
+			execCtx.Journal.record(t, execCtx)
+			if stepError != nil {
+				logging.L().Debug("[*] Stopping TTP Early")
+				break
 			}
-
-		case shutdownFlag = <-execCtx.shutdownChan:
-			// TODO[nesusvet]: We should propagate signal to child processes if any
-			logging.L().Warn("Shutting down due to signal received")
+			continue
 		}
 
-		// if the user specified custom success checks, run them now
-		if !execCtx.Cfg.NoChecks {
-			verifyError = step.VerifyChecks()
-		}
+		// core execution - run the step action,
+		// retrying it if the step has a retry policy
+		shutdownFlag = step.Retry.runAttempts(execCtx, step.Name, execResult, func() bool {
+			var shutdown bool
+			stepError, verifyError, shutdown = t.executeStep(execCtx, step, execResult)
+			return shutdown
+		})
+		execResult.EndTime = time.Now()
+		execResult.Children = step.childResults()
+
+		execCtx.StepResults.ByName[step.Name] = execResult
+		execCtx.StepResults.ByIndex = append(execCtx.StepResults.ByIndex, execResult)
+		execCtx.Journal.record(t, execCtx)
 
 		if stepError != nil || verifyError != nil || shutdownFlag {
 			logging.L().Debug("[*] Stopping TTP Early")
@@ -237,6 +255,131 @@ func (t *TTP) RunSteps(execCtx TTPExecutionContext) error {
 	return nil
 }
 
+// actionCancelGracePeriod is how long we wait for an action
+// to return after the timeout of its step has expired
+const actionCancelGracePeriod = 10 * time.Second
+
+// executeStep runs a single attempt at executing the step
+// (including its checks) and records the outcome in execResult
+func (t *TTP) executeStep(execCtx TTPExecutionContext, step Step, execResult *ExecutionResult) (stepError error, verifyError error, shutdownFlag bool) {
+	// discard the outcome of any previous attempt
+	execResult.ActResult = ActResult{}
+	execResult.Error = ""
+	execResult.Checks = nil
+
+	// bound the execution of the step by its timeout
+	stepCtx, cancel := step.contextWithTimeout(execCtx)
+	defer cancel()
+
+	// these channels are specific to this attempt so that
+	// a timed-out action that finishes later cannot
+	// be mistaken for the result of a subsequent step
+	actionResultsChan := make(chan *ActResult, 1)
+	failedResultsChan := make(chan *ActResult, 1)
+	errorsChan := make(chan error, 1)
+	go func(step Step) {
+		err := step.Template(stepCtx)
+		if err != nil {
+			logging.L().Errorf("Error templating step %s: %v", step.Name, err)
+		}
+		result, err := step.Execute(stepCtx)
+		if err != nil {
+			// This error was logged by the step itself
+			logging.L().Debugf("Error executing step %s: %v", step.Name, err)
+			// some actions (such as wait_for) describe
+			// why they failed in a result of their own
+			if result != nil {
+				failedResultsChan <- result
+			}
+			errorsChan <- err
+			return
+		}
+		actionResultsChan <- result
+	}(step)
+
+	// await one of four outcomes:
+	// 1. step execution successful
+	// 2. step execution failed
+	// 3. step timed out
+	// 4. shutdown signal received
+	var stepResult *ActResult
+	select {
+	case stepResult = <-actionResultsChan:
+	case stepError = <-errorsChan:
+	case <-stepCtx.Context().Done():
+		// most actions return as soon as their context expires - wait
+		// briefly for them so that they can be safely cleaned up
+		select {
+		case stepResult = <-actionResultsChan:
+		case stepError = <-errorsChan:
+		case <-time.After(actionCancelGracePeriod):
+			logging.L().Warnf("Step %q is still running after its timeout expired", step.Name)
+		}
+	case shutdownFlag = <-execCtx.shutdownChan:
+	}
+
+	switch {
+	case shutdownFlag:
+		// TODO[nesusvet]: We should propagate signal to child processes if any
+		logging.L().Warn("Shutting down due to signal received")
+		execResult.Status = StepStatusFailed
+		execResult.Error = "shutdown signal received"
+		return stepError, verifyError, shutdownFlag
+
+	case stepResult != nil:
+		// step execution successful - record results
+		execResult.ActResult = *stepResult
+		execResult.Status = StepStatusSucceeded
+
+	default:
+		execResult.Status = StepStatusFailed
+		// the result of a failed action (if any) is
+		// sent before its error, so it is ready by now
+		select {
+		case failedResult := <-failedResultsChan:
+			execResult.ActResult = *failedResult
+		default:
+		}
+		if stepCtx.Context().Err() == context.DeadlineExceeded {
+			execResult.Status = StepStatusTimeout
+			timeoutErr := fmt.Errorf("step %q timed out", step.Name)
+			if stepError != nil {
+				timeoutErr = fmt.Errorf("%w: %v", timeoutErr, stepError)
+			}
+			stepError = timeoutErr
+			logging.L().Errorf("%v", stepError)
+		} else if stepError == nil {
+			stepError = fmt.Errorf("step %q was cancelled: %w", step.Name, stepCtx.Context().Err())
+		}
+		execResult.Error = stepError.Error()
+		// this part is tricky - SubTTP steps
+		// must be cleaned up even on failure
+		// (because substeps may have succeeded)
+		// so we clean them up right away
+		if step.ShouldCleanupOnFailure() {
+			logging.L().Infof("[+] Cleaning up failed step %s", step.Name)
+			logging.L().Infof("[+] Full Cleanup will Run Afterward")
+			cleanupResult, cleanupErr := step.Cleanup(execCtx)
+			execResult.Cleanup = cleanupResult
+			if cleanupErr != nil {
+				logging.L().Errorf("Error cleaning up failed step %v: %v", step.Name, cleanupErr)
+				execResult.CleanupError = cleanupErr.Error()
+			}
+		}
+	}
+	execResult.ExitCode = exitCodeFromError(stepError)
+
+	// if the user specified custom success checks, run them now
+	if !execCtx.Cfg.NoChecks && execResult.Status == StepStatusSucceeded {
+		execResult.Checks, verifyError = step.VerifyChecks()
+		if verifyError != nil {
+			execResult.Status = StepStatusChecksFailed
+			execResult.Error = verifyError.Error()
+		}
+	}
+	return stepError, verifyError, shutdownFlag
+}
+
 // RunCleanup executes all required cleanup for steps in the given TTP.
 func (t *TTP) RunCleanup(execCtx TTPExecutionContext) error {
 	if execCtx.Cfg.NoCleanup {
@@ -250,17 +393,8 @@ func (t *TTP) RunCleanup(execCtx TTPExecutionContext) error {
 	}
 
 	// TODO[nesusvet]: We also should catch signals in clean ups
-	cleanupResults, err := t.startCleanupForCompletedSteps(execCtx)
-	if err != nil {
-		return err
-	}
-	// since ByIndex and ByName both contain pointers to
-	// the same underlying struct, this will update both
-	for cleanupIdx, cleanupResult := range cleanupResults {
-		execCtx.StepResults.ByIndex[cleanupIdx].Cleanup = cleanupResult
-	}
-
-	return nil
+	_, err := t.startCleanupForCompletedSteps(execCtx)
+	return err
 }
 
 func (t *TTP) chdir() (func(), error) {
@@ -309,16 +443,28 @@ func (t *TTP) startCleanupForCompletedSteps(execCtx TTPExecutionContext) ([]*Act
 	cleanupResults := make([]*ActResult, n)
 	for cleanupIdx := n - 1; cleanupIdx >= 0; cleanupIdx-- {
 		stepToCleanup := t.Steps[cleanupIdx]
+		execResult := execCtx.StepResults.ByIndex[cleanupIdx]
+		if !execResult.NeedsCleanup() {
+			logging.L().Debugf("Skipping cleanup of step %q with status %v", stepToCleanup.Name, execResult.Status)
+			continue
+		}
 		logging.DividerThin()
 		logging.L().Infof("Cleaning Up Step #%d: %q", cleanupIdx+1, stepToCleanup.Name)
 		cleanupResult, err := stepToCleanup.Cleanup(execCtx)
-		// must be careful to put these in step order, not in execution (reverse) order
+		// must be careful to put these in step order, not in execution (reverse) order.
+		// since ByIndex and ByName both contain pointers to
+		// the same underlying struct, this will update both
 		cleanupResults[cleanupIdx] = cleanupResult
+		execResult.Cleanup = cleanupResult
 		if err != nil {
+			execResult.CleanupError = err.Error()
 			logging.L().Errorf("error cleaning up step: %v", err)
 			logging.L().Errorf("will continue to try to cleanup other steps")
-			continue
+		} else {
+			execResult.CleanupError = ""
+			execResult.CleanedUp = true
 		}
+		execCtx.Journal.record(t, execCtx)
 	}
 	logging.DividerThin()
 	logging.L().Info("Finished Cleanup Successfully ✅")
@@ -20,6 +20,7 @@ THE SOFTWARE.
 package blocks
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -369,6 +370,143 @@ steps:
 	}
 }
 
+func TestConditionalSteps(t *testing.T) {
+	testCases := []struct {
+		name               string
+		content            string
+		args               map[string]interface{}
+		expectedStatuses   map[string]StepStatus
+		expectedStdout     string
+		expectExecuteError bool
+	}{
+		{
+			name: "Conditions On Step Results",
+			content: `name: test
+steps:
+  - name: step1
+    inline: echo -n yes
+    outputvar: answer
+  - name: skipped_step
+    if: eq .StepVars.answer "no"
+    inline: echo -n skipped
+    cleanup:
+      inline: echo -n skipped_cleanup
+  - name: executed_step
+    if: eq "$forge.steps.step1.stdout" "yes"
+    inline: echo -n executed
+    cleanup:
+      inline: echo -n executed_cleanup`,
+			expectedStatuses: map[string]StepStatus{
+				"step1":         StepStatusSucceeded,
+				"skipped_step":  StepStatusSkipped,
+				"executed_step": StepStatusSucceeded,
+			},
+			expectedStdout: "yesexecutedexecuted_cleanup",
+		},
+		{
+			name: "Conditions On Step Results With Newlines And Quotes",
+			content: `name: test
+steps:
+  - name: step1
+    inline: echo 'say "hi"'
+  - name: count
+    inline: echo -n 3
+  - name: inject
+    inline: echo -n 'x" "x'
+  - name: quoted_ref
+    if: eq "$forge.steps.step1.stdout" "say \"hi\"\n"
+    inline: echo -n quoted
+  - name: embedded_ref
+    if: eq "said $forge.steps.step1.stdout" "said say \"hi\"\n"
+    inline: echo -n embedded
+  - name: bare_ref
+    if: hasPrefix "say" $forge.steps.step1.stdout
+    inline: echo -n bare
+  - name: numeric_ref
+    if: gt $forge.steps.count.stdout 2
+    inline: echo -n numeric
+  - name: injected_ref
+    if: eq "$forge.steps.inject.stdout" "x"
+    inline: echo -n injected`,
+			expectedStatuses: map[string]StepStatus{
+				"step1":        StepStatusSucceeded,
+				"count":        StepStatusSucceeded,
+				"inject":       StepStatusSucceeded,
+				"quoted_ref":   StepStatusSucceeded,
+				"embedded_ref": StepStatusSucceeded,
+				"bare_ref":     StepStatusSucceeded,
+				"numeric_ref":  StepStatusSucceeded,
+				"injected_ref": StepStatusSkipped,
+			},
+			expectedStdout: "say \"hi\"\n3x\" \"xquotedembeddedbarenumeric",
+		},
+		{
+			name: "Conditions On Args And Platform",
+			content: `name: test
+steps:
+  - name: arg_enabled
+    if: .Args.enabled
+    inline: echo -n arg_enabled
+  - name: arg_disabled
+    if: not .Args.enabled
+    inline: echo -n arg_disabled
+  - name: wrong_platform
+    if: eq .Platform.OS "plan9"
+    inline: echo -n wrong_platform`,
+			args: map[string]interface{}{
+				"enabled": true,
+			},
+			expectedStatuses: map[string]StepStatus{
+				"arg_enabled":    StepStatusSucceeded,
+				"arg_disabled":   StepStatusSkipped,
+				"wrong_platform": StepStatusSkipped,
+			},
+			expectedStdout: "arg_enabled",
+		},
+		{
+			name: "Invalid Condition",
+			content: `name: test
+steps:
+  - name: step1
+    if: eq .StepVars.missing "foo"
+    inline: echo -n never`,
+			expectedStatuses: map[string]StepStatus{
+				"step1": StepStatusFailed,
+			},
+			expectExecuteError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var ttp TTP
+			require.NoError(t, yaml.Unmarshal([]byte(tc.content), &ttp))
+
+			var stdout bytes.Buffer
+			execCtx := NewTTPExecutionContext()
+			execCtx.Cfg.Stdout = &stdout
+			if tc.args != nil {
+				execCtx.Vars.Args = tc.args
+			}
+			require.NoError(t, ttp.Validate(execCtx))
+
+			err := ttp.Execute(execCtx)
+			if tc.expectExecuteError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.NoError(t, ttp.RunCleanup(execCtx))
+
+			require.Len(t, execCtx.StepResults.ByIndex, len(tc.expectedStatuses))
+			for name, status := range tc.expectedStatuses {
+				assert.Equal(t, status, execCtx.StepResults.ByName[name].Status, "status of step %v", name)
+			}
+			assert.Equal(t, tc.expectedStdout, stdout.String())
+		})
+	}
+}
+
 func TestMitreAttackMapping(t *testing.T) {
 	testCases := []struct {
 		name      string
@@ -382,12 +520,13 @@ name: TestTTP
 description: Test description
 mitre:
   tactics:
-    - Initial Access
-    - Execution
+    - TA0001 Initial Access
+    - TA0002 Execution
   techniques:
-    - Spearphishing Link
+    - T1566 Phishing
+    - T1204
   subtechniques:
-    - Attachment
+    - "T1566.001 Phishing: Spearphishing Attachment"
 `,
 			wantError: false,
 		},
@@ -397,8 +536,62 @@ mitre:
 name: TestTTP
 description: Test description
 mitre:
+  techniques:
+    - T1566 Phishing
+`,
+			wantError: true,
+		},
+		{
+			name: "Invalid MITRE Mapping - Names Instead Of IDs",
+			content: `
+name: TestTTP
+description: Test description
+mitre:
+  tactics:
+    - Initial Access
   techniques:
     - Spearphishing Link
+`,
+			wantError: true,
+		},
+		{
+			name: "Invalid MITRE Mapping - Unknown Technique",
+			content: `
+name: TestTTP
+description: Test description
+mitre:
+  tactics:
+    - TA0001
+  techniques:
+    - T1599.999
+`,
+			wantError: true,
+		},
+		{
+			name: "Invalid MITRE Mapping - Technique Outside Tactics",
+			content: `
+name: TestTTP
+description: Test description
+mitre:
+  tactics:
+    - TA0001 Initial Access
+  techniques:
+    - T1055 Process Injection
+`,
+			wantError: true,
+		},
+		{
+			name: "Invalid MITRE Mapping - Parent Technique Not Listed",
+			content: `
+name: TestTTP
+description: Test description
+mitre:
+  tactics:
+    - TA0001 Initial Access
+  techniques:
+    - T1566
+  subtechniques:
+    - T1195.002
 `,
 			wantError: true,
 		},
@@ -0,0 +1,243 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package blocks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/facebookincubator/ttpforge/pkg/checks"
+	"github.com/facebookincubator/ttpforge/pkg/logging"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// DefaultWaitInterval is how often wait_for steps
+	// check their condition by default
+	DefaultWaitInterval = time.Second
+
+	// DefaultWaitTimeout bounds how long wait_for steps wait
+	// for their condition when they have no timeout of their own
+	DefaultWaitTimeout = 5 * time.Minute
+)
+
+// WaitCondition is the condition of a wait_for step, in any of
+// the forms accepted by checks (such as `path_exists: /foo`).
+// It is kept as YAML until the step is templated, so that the
+// fields of the condition may contain step templates.
+type WaitCondition struct {
+	node yaml.Node
+}
+
+// UnmarshalYAML stores the condition for parsing once it is templated
+func (wc *WaitCondition) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("wait_for must be a condition such as `path_exists: /path/to/file`")
+	}
+	wc.node = *node
+	return nil
+}
+
+// MarshalYAML encodes the condition as it was written
+func (wc WaitCondition) MarshalYAML() (interface{}, error) {
+	return &wc.node, nil
+}
+
+// IsZero reports whether no condition was specified
+func (wc WaitCondition) IsZero() bool {
+	return wc.node.IsZero()
+}
+
+// parse templates (a copy of) the condition and decodes it
+func (wc *WaitCondition) parse(execCtx TTPExecutionContext) (checks.Condition, error) {
+	node := copyNode(&wc.node)
+	if err := templateScalars(node, execCtx); err != nil {
+		return nil, err
+	}
+	condition, err := checks.ParseCondition(node)
+	if err != nil {
+		return nil, fmt.Errorf("invalid wait_for condition: %w", err)
+	}
+	return condition, nil
+}
+
+// containsStepTemplating reports whether any value
+// of the condition contains step templates
+func (wc *WaitCondition) containsStepTemplating(execCtx TTPExecutionContext) bool {
+	var found bool
+	var walk func(node *yaml.Node)
+	walk = func(node *yaml.Node) {
+		if node.Kind == yaml.ScalarNode && execCtx.containsStepTemplating(node.Value) {
+			found = true
+		}
+		for _, child := range node.Content {
+			walk(child)
+		}
+	}
+	walk(&wc.node)
+	return found
+}
+
+// copyNode returns a deep copy of a YAML node tree
+func copyNode(node *yaml.Node) *yaml.Node {
+	c := *node
+	c.Content = make([]*yaml.Node, len(node.Content))
+	for idx, child := range node.Content {
+		c.Content[idx] = copyNode(child)
+	}
+	return &c
+}
+
+// WaitForStep polls a condition (of any of the types
+// supported by checks) until it holds - or, with Negate,
+// until it no longer holds - so that TTPs can wait for
+// the state of the host to change without resorting to
+// `sleep`. The step fails if its timeout expires first.
+type WaitForStep struct {
+	actionDefaults `yaml:",inline"`
+	Condition      WaitCondition `yaml:"wait_for,omitempty"`
+	Interval       Timeout       `yaml:"interval,omitempty"`
+	Negate         bool          `yaml:"negate,omitempty"`
+	FileSystem     afero.Fs      `yaml:"-,omitempty"`
+
+	condition checks.Condition
+}
+
+// NewWaitForStep creates a new WaitForStep instance and returns a pointer to it.
+func NewWaitForStep() *WaitForStep {
+	return &WaitForStep{}
+}
+
+// IsNil checks if the step is nil or empty and returns a boolean value.
+func (s *WaitForStep) IsNil() bool {
+	return s.Condition.IsZero()
+}
+
+// Validate validates the step, checking for the necessary attributes and dependencies.
+func (s *WaitForStep) Validate(execCtx TTPExecutionContext) error {
+	if s.Condition.IsZero() {
+		return errors.New("wait_for must specify a condition")
+	}
+	if s.Interval < 0 {
+		return errors.New("interval must not be negative")
+	}
+	// conditions with step templates can only
+	// be parsed once the step is templated
+	if !s.Condition.containsStepTemplating(execCtx) {
+		condition, err := s.Condition.parse(execCtx)
+		if err != nil {
+			return err
+		}
+		s.condition = condition
+	}
+	return nil
+}
+
+// Template takes each applicable field in the step and replaces any template strings with their resolved values.
+//
+// **Returns:**
+//
+// error: error if template resolution fails, nil otherwise
+func (s *WaitForStep) Template(execCtx TTPExecutionContext) error {
+	condition, err := s.Condition.parse(execCtx)
+	if err != nil {
+		return err
+	}
+	s.condition = condition
+	return nil
+}
+
+// Execute polls the condition until it reaches the desired state
+// and records the final state of the condition in the step outputs.
+func (s *WaitForStep) Execute(execCtx TTPExecutionContext) (*ActResult, error) {
+	if s.condition == nil {
+		return nil, errors.New("wait_for condition could not be parsed")
+	}
+	interval := s.Interval.Duration()
+	if interval == 0 {
+		interval = DefaultWaitInterval
+	}
+	ctx := execCtx.Context()
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, DefaultWaitTimeout)
+		defer cancel()
+	}
+
+	fsys := s.FileSystem
+	if fsys == nil {
+		fsys = afero.NewOsFs()
+	}
+	verificationCtx := checks.VerificationContext{
+		Platform:   execCtx.Vars.Platform,
+		FileSystem: fsys,
+	}
+
+	desired := "hold"
+	if s.Negate {
+		desired = "stop holding"
+	}
+	logging.L().Infof("Waiting for condition to %v (checking every %v)", desired, interval)
+
+	start := time.Now()
+	for attempt := 1; ; attempt++ {
+		verifyErr := s.condition.Verify(verificationCtx)
+		holds := verifyErr == nil
+		if holds != s.Negate {
+			result := waitResult(holds, verifyErr, attempt, start)
+			logging.L().Infof("Wait complete after %v (%d checks): %v", result.Outputs["elapsed"], attempt, result.Outputs["state"])
+			return result, nil
+		}
+		logging.L().Debugf("Condition check %d: holds=%v", attempt, holds)
+
+		select {
+		case <-ctx.Done():
+			// the final state of the condition explains why the step failed
+			result := waitResult(holds, verifyErr, attempt, start)
+			if holds {
+				return result, fmt.Errorf("condition still held after %v (%d checks)", result.Outputs["elapsed"], attempt)
+			}
+			return result, fmt.Errorf("condition did not hold after %v (%d checks): %w", result.Outputs["elapsed"], attempt, verifyErr)
+		case <-time.After(interval):
+		}
+	}
+}
+
+// waitResult records the state of the condition of a wait_for
+// step after its last check in the outputs of the step
+func waitResult(holds bool, verifyErr error, attempt int, start time.Time) *ActResult {
+	elapsed := time.Since(start).Round(time.Millisecond)
+	state := "condition holds"
+	if !holds {
+		state = fmt.Sprintf("condition does not hold: %v", verifyErr)
+	}
+	return &ActResult{
+		Stdout: state + "\n",
+		Outputs: map[string]interface{}{
+			"holds":   holds,
+			"state":   state,
+			"checks":  attempt,
+			"elapsed": elapsed.String(),
+		},
+	}
+}
@@ -0,0 +1,231 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package blocks
+
+import (
+	"testing"
+	"time"
+
+	"github.com/facebookincubator/ttpforge/pkg/testutils"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestWaitForValidate(t *testing.T) {
+	testCases := []struct {
+		name      string
+		content   string
+		wantError bool
+	}{
+		{
+			name:    "path condition",
+			content: "wait_for:\n  path_exists: /tmp/marker\ninterval: 2s",
+		},
+		{
+			name:    "command condition",
+			content: "wait_for:\n  command: pgrep agent\n  expect_exit_code: 1",
+		},
+		{
+			name:    "templated condition",
+			content: "wait_for:\n  path_exists: \"{[{.StepVars.marker}]}\"",
+		},
+		{
+			name:      "unknown condition",
+			content:   "wait_for:\n  port_open: 80",
+			wantError: true,
+		},
+		{
+			name:      "ambiguous condition",
+			content:   "wait_for:\n  path_exists: /tmp/marker\n  command: \"true\"",
+			wantError: true,
+		},
+		{
+			name:      "negative interval",
+			content:   "wait_for:\n  path_exists: /tmp/marker\ninterval: -1",
+			wantError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var step WaitForStep
+			require.NoError(t, yaml.Unmarshal([]byte(tc.content), &step))
+			err := step.Validate(NewTTPExecutionContext())
+			if tc.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestWaitForExecute(t *testing.T) {
+	testCases := []struct {
+		name            string
+		content         string
+		fsysContents    map[string][]byte
+		stepVars        map[string]interface{}
+		createAfter     string
+		removeAfter     string
+		timeout         time.Duration
+		expectedOutputs map[string]interface{}
+		wantError       bool
+	}{
+		{
+			name:         "condition already holds",
+			content:      "wait_for:\n  path_exists: /tmp/marker",
+			fsysContents: map[string][]byte{"/tmp/marker": []byte("x")},
+			expectedOutputs: map[string]interface{}{
+				"holds":  true,
+				"state":  "condition holds",
+				"checks": 1,
+			},
+		},
+		{
+			name:        "condition starts to hold",
+			content:     "wait_for:\n  path_exists: /tmp/marker\ninterval: 0.01s",
+			createAfter: "/tmp/marker",
+			expectedOutputs: map[string]interface{}{
+				"holds": true,
+				"state": "condition holds",
+			},
+		},
+		{
+			name:         "condition with step templates",
+			content:      "wait_for:\n  path_exists: \"/tmp/{[{.StepVars.marker}]}\"",
+			fsysContents: map[string][]byte{"/tmp/marker": []byte("x")},
+			stepVars:     map[string]interface{}{"marker": "marker"},
+			expectedOutputs: map[string]interface{}{
+				"holds": true,
+			},
+		},
+		{
+			name:         "negated condition stops holding",
+			content:      "wait_for:\n  path_exists: /tmp/marker\ninterval: 0.01s\nnegate: true",
+			fsysContents: map[string][]byte{"/tmp/marker": []byte("x")},
+			removeAfter:  "/tmp/marker",
+			expectedOutputs: map[string]interface{}{
+				"holds": false,
+				"state": `condition does not hold: file "/tmp/marker" does not exist`,
+			},
+		},
+		{
+			name:    "condition never holds",
+			content: "wait_for:\n  path_exists: /tmp/marker\ninterval: 0.01s",
+			timeout: 100 * time.Millisecond,
+			expectedOutputs: map[string]interface{}{
+				"holds": false,
+				"state": `condition does not hold: file "/tmp/marker" does not exist`,
+			},
+			wantError: true,
+		},
+		{
+			name:         "negated condition never stops holding",
+			content:      "wait_for:\n  path_exists: /tmp/marker\ninterval: 0.01s\nnegate: true",
+			fsysContents: map[string][]byte{"/tmp/marker": []byte("x")},
+			timeout:      100 * time.Millisecond,
+			expectedOutputs: map[string]interface{}{
+				"holds": true,
+				"state": "condition holds",
+			},
+			wantError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var step WaitForStep
+			require.NoError(t, yaml.Unmarshal([]byte(tc.content), &step))
+
+			fsys := afero.NewMemMapFs()
+			if tc.fsysContents != nil {
+				var err error
+				fsys, err = testutils.MakeAferoTestFs(tc.fsysContents)
+				require.NoError(t, err)
+			}
+			step.FileSystem = fsys
+
+			execCtx := NewTTPExecutionContext()
+			if tc.stepVars != nil {
+				execCtx.Vars.StepVars = tc.stepVars
+			}
+			if tc.timeout != 0 {
+				stepCtx, cancel := (&Step{CommonStepFields: CommonStepFields{Timeout: Timeout(tc.timeout)}}).contextWithTimeout(execCtx)
+				defer cancel()
+				execCtx = stepCtx
+			}
+			require.NoError(t, step.Validate(execCtx))
+			require.NoError(t, step.Template(execCtx))
+
+			// change the state of the host while the step waits
+			go func() {
+				time.Sleep(50 * time.Millisecond)
+				if tc.createAfter != "" {
+					assert.NoError(t, afero.WriteFile(fsys, tc.createAfter, []byte("x"), 0644))
+				}
+				if tc.removeAfter != "" {
+					assert.NoError(t, fsys.Remove(tc.removeAfter))
+				}
+			}()
+
+			// the final state of the condition is
+			// recorded whether or not the wait succeeds
+			result, err := step.Execute(execCtx)
+			if tc.wantError {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			require.NotNil(t, result)
+			for key, value := range tc.expectedOutputs {
+				assert.Equal(t, value, result.Outputs[key], key)
+			}
+			assert.Contains(t, result.Outputs, "checks")
+			assert.Contains(t, result.Outputs, "elapsed")
+		})
+	}
+}
+
+func TestWaitForTimeoutResult(t *testing.T) {
+	content := `name: test
+steps:
+  - name: wait
+    wait_for:
+      path_exists: /nonexistent/wait_for/marker
+    interval: 0.01s
+    timeout: 0.1s`
+	var ttp TTP
+	require.NoError(t, yaml.Unmarshal([]byte(content), &ttp))
+	execCtx := NewTTPExecutionContext()
+	require.NoError(t, ttp.Validate(execCtx))
+	require.Error(t, ttp.Execute(execCtx))
+
+	// the failed step records the state of the condition after its last check
+	result := execCtx.StepResults.ByName["wait"]
+	require.NotNil(t, result)
+	assert.Equal(t, StepStatusTimeout, result.Status)
+	assert.Equal(t, false, result.Outputs["holds"])
+	assert.Equal(t, `condition does not hold: file "/nonexistent/wait_for/marker" does not exist`, result.Outputs["state"])
+	assert.Greater(t, result.Outputs["checks"], 1)
+	assert.Contains(t, result.Outputs, "elapsed")
+}
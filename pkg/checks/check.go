@@ -51,6 +51,16 @@ func (c *Check) Verify(ctx VerificationContext) error {
 	return c.condition.Verify(ctx)
 }
 
+// conditionTypes lists every type of condition together
+// with the YAML key that identifies conditions of that type
+var conditionTypes = []struct {
+	key          string
+	newCondition func() Condition
+}{
+	{"path_exists", func() Condition { return &PathExists{} }},
+	{"command", func() Condition { return &CommandCheck{} }},
+}
+
 // UnmarshalYAML implements custom deserialization
 // process to ensure that the check is decoded
 // into the correct struct type
@@ -69,27 +79,52 @@ func (c *Check) UnmarshalYAML(node *yaml.Node) error {
 		return errors.New("no msg specified for check")
 	}
 
-	candidateTypeInstances := []Condition{
-		&PathExists{},
-		&CommandCheck{},
+	c.condition, err = ParseCondition(node)
+	switch {
+	case errors.Is(err, errAmbiguousCondition):
+		return fmt.Errorf("check %q has ambiguous type", c.Msg)
+	case errors.Is(err, errUnknownCondition):
+		return fmt.Errorf("condition with msg %q did not match any valid condition type", c.Msg)
 	}
-	for _, candidateTypeInstance := range candidateTypeInstances {
+	return err
+}
+
+var (
+	errAmbiguousCondition = errors.New("condition has ambiguous type")
+	errUnknownCondition   = errors.New("condition did not match any valid condition type")
+)
+
+// ParseCondition decodes a condition of any type from YAML - such as
+// the condition of a check, without the `msg` of the check
+//
+// **Parameters:**
+//
+// node: the YAML node that specifies the condition
+//
+// **Returns:**
+//
+// Condition: the decoded condition
+// error: an error if the node does not specify exactly one condition
+func ParseCondition(node *yaml.Node) (Condition, error) {
+	var condition Condition
+	for _, conditionType := range conditionTypes {
+		candidateTypeInstance := conditionType.newCondition()
 		err := node.Decode(candidateTypeInstance)
 		if err == nil && !candidateTypeInstance.IsNil() {
-			if c.condition != nil {
+			if condition != nil {
 				// Must catch conditions with ambiguous types, such as:
 				// - path_exists: foo
 				//   command_succeeds: bar
 				//
 				// This is a problem because we can't tell into
 				// which concrete type we should decode
-				return fmt.Errorf("check %q has ambiguous type", c.Msg)
+				return nil, errAmbiguousCondition
 			}
-			c.condition = candidateTypeInstance
+			condition = candidateTypeInstance
 		}
 	}
-	if c.condition == nil {
-		return fmt.Errorf("condition with msg %q did not match any valid condition type", c.Msg)
+	if condition == nil {
+		return nil, errUnknownCondition
 	}
-	return nil
+	return condition, nil
 }
@@ -182,3 +182,47 @@ content_contains: "foo"`,
 	}
 
 }
+
+func TestParseCondition(t *testing.T) {
+	testCases := []struct {
+		name         string
+		contentStr   string
+		expectedType Condition
+		wantError    bool
+	}{
+		{
+			name:         "path_exists",
+			contentStr:   "path_exists: /tmp/foo\ncontent_contains: bar",
+			expectedType: &PathExists{},
+		},
+		{
+			name:         "command",
+			contentStr:   "command: \"true\"",
+			expectedType: &CommandCheck{},
+		},
+		{
+			name:       "ambiguous",
+			contentStr: "path_exists: /tmp/foo\ncommand: \"true\"",
+			wantError:  true,
+		},
+		{
+			name:       "unknown",
+			contentStr: "port_open: 80",
+			wantError:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var node yaml.Node
+			require.NoError(t, yaml.Unmarshal([]byte(tc.contentStr), &node))
+			condition, err := ParseCondition(node.Content[0])
+			if tc.wantError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.IsType(t, tc.expectedType, condition)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package checks
+
+import (
+	"github.com/facebookincubator/ttpforge/pkg/schema"
+)
+
+// Schema returns the JSON Schema of a check,
+// with one alternative for each type of condition
+//
+// **Parameters:**
+//
+// r: the reflector used to describe the fields of each condition type
+//
+// **Returns:**
+//
+// *schema.Schema: the schema of a check
+func Schema(r *schema.Reflector) *schema.Schema {
+	common := r.Reflect(CommonCheckFields{})
+	var alternatives []*schema.Schema
+	for _, conditionType := range conditionTypes {
+		condition := schema.Merge(common, r.Reflect(conditionType.newCondition()))
+		condition.Required = []string{"msg", conditionType.key}
+		alternatives = append(alternatives, condition)
+	}
+	return schema.AnyOf(alternatives...)
+}
+
+// ConditionSchema returns the JSON Schema of a condition
+// (as parsed by ParseCondition), with one alternative
+// for each type of condition
+//
+// **Parameters:**
+//
+// r: the reflector used to describe the fields of each condition type
+//
+// **Returns:**
+//
+// *schema.Schema: the schema of a condition
+func ConditionSchema(r *schema.Reflector) *schema.Schema {
+	var alternatives []*schema.Schema
+	for _, conditionType := range conditionTypes {
+		condition := r.Reflect(conditionType.newCondition())
+		condition.Required = []string{conditionType.key}
+		alternatives = append(alternatives, condition)
+	}
+	return schema.AnyOf(alternatives...)
+}
@@ -0,0 +1,236 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+// Package coverage aggregates the MITRE ATT&CK mappings of a
+// library of TTPs, to describe which techniques the library
+// covers and on which platforms.
+package coverage
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/facebookincubator/ttpforge/pkg/mitre"
+	"github.com/facebookincubator/ttpforge/pkg/parseutils"
+)
+
+// AnyPlatform is the platform column counting the TTPs
+// that do not restrict the operating systems they run on
+const AnyPlatform = "any"
+
+// platformOrder lists the platforms that
+// come first in the platform columns
+var platformOrder = []string{"linux", "darwin", "windows"}
+
+// Technique records the TTPs that cover a technique or sub-technique
+type Technique struct {
+	ID string
+	// Name is the name of the technique in the MITRE ATT&CK
+	// catalogue, or empty if the technique is not in it
+	Name string
+	// TTPs holds the references of the TTPs that cover the technique
+	TTPs []string
+	// Platforms counts the TTPs that cover the technique on each
+	// platform - TTPs that run on any platform are counted under AnyPlatform
+	Platforms map[string]int
+}
+
+// CountForPlatform returns the number of TTPs that cover the
+// technique on a platform, including those that run on any platform
+func (t *Technique) CountForPlatform(platform string) int {
+	if platform == AnyPlatform {
+		return t.Platforms[AnyPlatform]
+	}
+	return t.Platforms[platform] + t.Platforms[AnyPlatform]
+}
+
+// Coverage aggregates the MITRE ATT&CK mappings of TTPs
+type Coverage struct {
+	catalogue  *mitre.Catalogue
+	techniques map[string]*Technique
+	platforms  map[string]bool
+	numTTPs    int
+}
+
+// New creates an empty Coverage that looks up the
+// names of techniques in the provided catalogue
+func New(catalogue *mitre.Catalogue) *Coverage {
+	return &Coverage{
+		catalogue:  catalogue,
+		techniques: make(map[string]*Technique),
+		platforms:  make(map[string]bool),
+	}
+}
+
+// Add records the techniques and sub-techniques covered by a TTP
+//
+// **Parameters:**
+//
+// ttpRef: the reference of the TTP
+// ttp: the parsed preamble of the TTP
+func (c *Coverage) Add(ttpRef string, ttp parseutils.TTP) {
+	c.numTTPs++
+	var ttpPlatforms []string
+	for _, platform := range ttp.Requirements.Platforms {
+		if platform.OS == "" {
+			// platforms that only specify an architecture
+			// do not restrict the operating system
+			ttpPlatforms = nil
+			break
+		}
+		if !slices.Contains(ttpPlatforms, platform.OS) {
+			ttpPlatforms = append(ttpPlatforms, platform.OS)
+		}
+	}
+	if len(ttpPlatforms) == 0 {
+		ttpPlatforms = []string{AnyPlatform}
+	}
+
+	entries := append(slices.Clone(ttp.Mitre.Techniques), ttp.Mitre.Subtechniques...)
+	var seen []string
+	for _, entry := range entries {
+		id, _ := mitre.ParseEntry(entry)
+		if id == "" || slices.Contains(seen, id) {
+			continue
+		}
+		seen = append(seen, id)
+		technique, ok := c.techniques[id]
+		if !ok {
+			technique = &Technique{
+				ID:        id,
+				Name:      c.catalogue.Name(id),
+				Platforms: make(map[string]int),
+			}
+			c.techniques[id] = technique
+		}
+		technique.TTPs = append(technique.TTPs, ttpRef)
+		for _, platform := range ttpPlatforms {
+			technique.Platforms[platform]++
+			c.platforms[platform] = true
+		}
+	}
+}
+
+// NumTTPs returns the number of TTPs added to the coverage
+func (c *Coverage) NumTTPs() int {
+	return c.numTTPs
+}
+
+// Techniques returns the covered techniques, sorted by ID
+func (c *Coverage) Techniques() []*Technique {
+	techniques := make([]*Technique, 0, len(c.techniques))
+	for _, technique := range c.techniques {
+		techniques = append(techniques, technique)
+	}
+	sort.Slice(techniques, func(i, j int) bool {
+		return techniques[i].ID < techniques[j].ID
+	})
+	return techniques
+}
+
+// Platforms returns the platforms of the covered techniques:
+// the most common platforms first, then the other platforms by
+// name and finally AnyPlatform (if any TTP runs on any platform)
+func (c *Coverage) Platforms() []string {
+	var platforms []string
+	for _, platform := range platformOrder {
+		if c.platforms[platform] {
+			platforms = append(platforms, platform)
+		}
+	}
+	var others []string
+	for platform := range c.platforms {
+		if platform != AnyPlatform && !slices.Contains(platformOrder, platform) {
+			others = append(others, platform)
+		}
+	}
+	sort.Strings(others)
+	platforms = append(platforms, others...)
+	if c.platforms[AnyPlatform] {
+		platforms = append(platforms, AnyPlatform)
+	}
+	return platforms
+}
+
+// matrix returns the technique x platform matrix as rows of strings,
+// starting with a header row - the platform columns count the TTPs
+// that cover each technique on that platform
+func (c *Coverage) matrix() [][]string {
+	platforms := c.Platforms()
+	header := append([]string{"technique", "name"}, platforms...)
+	rows := [][]string{append(header, "ttps")}
+	for _, technique := range c.Techniques() {
+		row := []string{technique.ID, technique.Name}
+		for _, platform := range platforms {
+			row = append(row, strconv.Itoa(technique.Platforms[platform]))
+		}
+		rows = append(rows, append(row, strconv.Itoa(len(technique.TTPs))))
+	}
+	return rows
+}
+
+// WriteCSV writes the technique x platform matrix as CSV
+//
+// **Parameters:**
+//
+// w: the writer to write the CSV to
+//
+// **Returns:**
+//
+// error: an error if the CSV could not be written
+func (c *Coverage) WriteCSV(w io.Writer) error {
+	csvWriter := csv.NewWriter(w)
+	if err := csvWriter.WriteAll(c.matrix()); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// WriteMarkdown writes the technique x platform matrix as a Markdown table
+//
+// **Parameters:**
+//
+// w: the writer to write the table to
+//
+// **Returns:**
+//
+// error: an error if the table could not be written
+func (c *Coverage) WriteMarkdown(w io.Writer) error {
+	rows := c.matrix()
+	var b strings.Builder
+	fmt.Fprintf(&b, "# MITRE ATT&CK Coverage\n\n%d technique(s) covered by %d TTP(s) (MITRE ATT&CK version %v).\n\n",
+		len(rows)-1, c.numTTPs, c.catalogue.Version)
+	for i, row := range rows {
+		cells := make([]string, len(row))
+		for j, cell := range row {
+			cells[j] = strings.ReplaceAll(cell, "|", `\|`)
+		}
+		b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+		if i == 0 {
+			b.WriteString("|" + strings.Repeat(" --- |", len(row)) + "\n")
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
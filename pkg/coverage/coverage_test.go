@@ -0,0 +1,198 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package coverage
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/facebookincubator/ttpforge/pkg/mitre"
+	"github.com/facebookincubator/ttpforge/pkg/parseutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const linuxTTP = `name: linux
+requirements:
+  platforms:
+    - os: linux
+mitre:
+  tactics:
+    - TA0002 Execution
+  techniques:
+    - T1059 Command and Scripting Interpreter
+  subtechniques:
+    - T1059.004 Unix Shell
+steps:
+  - name: step
+    print_str: hello
+`
+
+const crossPlatformTTP = `name: cross-platform
+requirements:
+  platforms:
+    - os: linux
+    - os: darwin
+      arch: arm64
+    - os: darwin
+      arch: amd64
+mitre:
+  tactics:
+    - TA0002 Execution
+  techniques:
+    - T1059
+    - T1059
+`
+
+const anyPlatformTTP = `name: any
+mitre:
+  tactics:
+    - TA0007 Discovery
+  techniques:
+    - T1082 System Information Discovery
+`
+
+const unmappedTTP = `name: unmapped
+steps:
+  - name: step
+    print_str: hello
+`
+
+func newTestCoverage(t *testing.T) *Coverage {
+	c := New(mitre.Default())
+	for _, ttp := range []struct {
+		ref     string
+		content string
+	}{
+		{ref: "repo//linux.yaml", content: linuxTTP},
+		{ref: "repo//cross-platform.yaml", content: crossPlatformTTP},
+		{ref: "repo//any.yaml", content: anyPlatformTTP},
+		{ref: "repo//unmapped.yaml", content: unmappedTTP},
+	} {
+		parsed, err := parseutils.ParseTTP([]byte(ttp.content), ttp.ref)
+		require.NoError(t, err)
+		c.Add(ttp.ref, parsed)
+	}
+	return c
+}
+
+func TestAdd(t *testing.T) {
+	c := newTestCoverage(t)
+	assert.Equal(t, 4, c.NumTTPs())
+	assert.Equal(t, []string{"linux", "darwin", "any"}, c.Platforms())
+
+	techniques := c.Techniques()
+	require.Len(t, techniques, 3)
+
+	assert.Equal(t, "T1059", techniques[0].ID)
+	assert.Equal(t, "Command and Scripting Interpreter", techniques[0].Name)
+	assert.Equal(t, []string{"repo//linux.yaml", "repo//cross-platform.yaml"}, techniques[0].TTPs)
+	assert.Equal(t, map[string]int{"linux": 2, "darwin": 1}, techniques[0].Platforms)
+
+	assert.Equal(t, "T1059.004", techniques[1].ID)
+	assert.Equal(t, "Command and Scripting Interpreter: Unix Shell", techniques[1].Name)
+	assert.Equal(t, map[string]int{"linux": 1}, techniques[1].Platforms)
+
+	assert.Equal(t, "T1082", techniques[2].ID)
+	assert.Equal(t, map[string]int{"any": 1}, techniques[2].Platforms)
+	assert.Equal(t, 1, techniques[2].CountForPlatform("windows"))
+	assert.Equal(t, 1, techniques[2].CountForPlatform(AnyPlatform))
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, newTestCoverage(t).WriteCSV(&buf))
+	expected := `technique,name,linux,darwin,any,ttps
+T1059,Command and Scripting Interpreter,2,1,0,2
+T1059.004,Command and Scripting Interpreter: Unix Shell,1,0,0,1
+T1082,System Information Discovery,0,0,1,1
+`
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, newTestCoverage(t).WriteMarkdown(&buf))
+	output := buf.String()
+	assert.Contains(t, output, "3 technique(s) covered by 4 TTP(s)")
+	assert.Contains(t, output, "| technique | name | linux | darwin | any | ttps |\n| --- | --- | --- | --- | --- | --- |\n")
+	assert.Contains(t, output, "| T1059.004 | Command and Scripting Interpreter: Unix Shell | 1 | 0 | 0 | 1 |\n")
+}
+
+func TestNavigatorLayer(t *testing.T) {
+	testCases := []struct {
+		name              string
+		platform          string
+		expectedPlatforms []string
+		expectedScores    map[string]int
+		expectedMaxValue  int
+		wantError         bool
+	}{
+		{
+			name:              "All Platforms",
+			expectedPlatforms: []string{"Linux", "macOS", "Windows"},
+			expectedScores:    map[string]int{"T1059": 2, "T1059.004": 1, "T1082": 1},
+			expectedMaxValue:  2,
+		},
+		{
+			name:              "Darwin",
+			platform:          "darwin",
+			expectedPlatforms: []string{"macOS"},
+			expectedScores:    map[string]int{"T1059": 1, "T1082": 1},
+			expectedMaxValue:  1,
+		},
+		{
+			name:              "Windows",
+			platform:          "windows",
+			expectedPlatforms: []string{"Windows"},
+			expectedScores:    map[string]int{"T1082": 1},
+			expectedMaxValue:  1,
+		},
+		{
+			name:      "Unsupported Platform",
+			platform:  "plan9",
+			wantError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			layer, err := newTestCoverage(t).NavigatorLayer("test layer", tc.platform)
+			if tc.wantError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "test layer", layer.Name)
+			assert.Equal(t, "enterprise-attack", layer.Domain)
+			assert.Equal(t, "15", layer.Versions.Attack)
+			assert.Equal(t, tc.expectedPlatforms, layer.Filters.Platforms)
+			assert.Equal(t, tc.expectedMaxValue, layer.Gradient.MaxValue)
+
+			scores := make(map[string]int)
+			for _, technique := range layer.Techniques {
+				scores[technique.TechniqueID] = technique.Score
+				assert.True(t, technique.Enabled)
+				assert.Equal(t, technique.TechniqueID == "T1059", technique.ShowSubtechniques)
+			}
+			assert.Equal(t, tc.expectedScores, scores)
+		})
+	}
+}
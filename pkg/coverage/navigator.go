@@ -0,0 +1,189 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package coverage
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Versions of the ATT&CK Navigator and of its layer
+// format that generated layers are compatible with
+const (
+	navigatorVersion      = "4.9.1"
+	navigatorLayerVersion = "4.5"
+)
+
+// navigatorPlatforms maps TTPForge platforms
+// to the names of ATT&CK Navigator platforms
+var navigatorPlatforms = map[string]string{
+	"linux":   "Linux",
+	"darwin":  "macOS",
+	"windows": "Windows",
+}
+
+// Layer is an ATT&CK Navigator layer
+// (https://github.com/mitre-attack/attack-navigator)
+type Layer struct {
+	Name                          string           `json:"name"`
+	Versions                      LayerVersions    `json:"versions"`
+	Domain                        string           `json:"domain"`
+	Description                   string           `json:"description"`
+	Filters                       LayerFilters     `json:"filters"`
+	Sorting                       int              `json:"sorting"`
+	HideDisabled                  bool             `json:"hideDisabled"`
+	Techniques                    []LayerTechnique `json:"techniques"`
+	Gradient                      LayerGradient    `json:"gradient"`
+	LegendItems                   []interface{}    `json:"legendItems"`
+	Metadata                      []LayerMetadata  `json:"metadata"`
+	ShowTacticRowBackground       bool             `json:"showTacticRowBackground"`
+	SelectTechniquesAcrossTactics bool             `json:"selectTechniquesAcrossTactics"`
+	SelectSubtechniquesWithParent bool             `json:"selectSubtechniquesWithParent"`
+}
+
+// LayerVersions holds the versions that a layer is compatible with
+type LayerVersions struct {
+	Attack    string `json:"attack"`
+	Navigator string `json:"navigator"`
+	Layer     string `json:"layer"`
+}
+
+// LayerFilters restricts the matrix shown by the Navigator
+type LayerFilters struct {
+	Platforms []string `json:"platforms"`
+}
+
+// LayerTechnique annotates a technique of the matrix
+type LayerTechnique struct {
+	TechniqueID       string          `json:"techniqueID"`
+	Score             int             `json:"score"`
+	Comment           string          `json:"comment,omitempty"`
+	Enabled           bool            `json:"enabled"`
+	Metadata          []LayerMetadata `json:"metadata,omitempty"`
+	ShowSubtechniques bool            `json:"showSubtechniques"`
+}
+
+// LayerGradient maps scores to colors
+type LayerGradient struct {
+	Colors   []string `json:"colors"`
+	MinValue int      `json:"minValue"`
+	MaxValue int      `json:"maxValue"`
+}
+
+// LayerMetadata is a name/value pair shown by the Navigator
+type LayerMetadata struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// NavigatorLayer builds an ATT&CK Navigator layer in which each
+// covered technique is scored by the number of TTPs that cover it.
+// If a platform is specified, only the TTPs that run on that
+// platform are counted and the matrix is filtered accordingly.
+//
+// **Parameters:**
+//
+// name: the name of the layer
+// platform: the platform (such as linux) to score, or empty for all platforms
+//
+// **Returns:**
+//
+// *Layer: the layer
+// error: an error if the platform is not supported by the Navigator
+func (c *Coverage) NavigatorLayer(name string, platform string) (*Layer, error) {
+	filterPlatforms := []string{"Linux", "macOS", "Windows"}
+	description := fmt.Sprintf("Techniques covered by %d TTP(s), scored by the number of TTPs that cover them", c.numTTPs)
+	if platform != "" {
+		navigatorPlatform, ok := navigatorPlatforms[platform]
+		if !ok {
+			return nil, fmt.Errorf("platform %q is not supported by the ATT&CK Navigator", platform)
+		}
+		filterPlatforms = []string{navigatorPlatform}
+		description += " on " + platform
+	}
+
+	attackVersion, _, _ := strings.Cut(c.catalogue.Version, ".")
+	layer := &Layer{
+		Name: name,
+		Versions: LayerVersions{
+			Attack:    attackVersion,
+			Navigator: navigatorVersion,
+			Layer:     navigatorLayerVersion,
+		},
+		Domain:      "enterprise-attack",
+		Description: description,
+		Filters:     LayerFilters{Platforms: filterPlatforms},
+		Techniques:  []LayerTechnique{},
+		LegendItems: []interface{}{},
+		Metadata: []LayerMetadata{
+			{Name: "generated_by", Value: "ttpforge enum coverage"},
+			{Name: "ttps", Value: strconv.Itoa(c.numTTPs)},
+		},
+	}
+
+	maxScore := 1
+	platforms := c.Platforms()
+	for _, technique := range c.Techniques() {
+		score := len(technique.TTPs)
+		if platform != "" {
+			score = technique.CountForPlatform(platform)
+		}
+		if score == 0 {
+			continue
+		}
+		maxScore = max(maxScore, score)
+
+		var metadata []LayerMetadata
+		for _, p := range platforms {
+			if count := technique.Platforms[p]; count > 0 {
+				metadata = append(metadata, LayerMetadata{Name: p, Value: strconv.Itoa(count)})
+			}
+		}
+		for _, ttpRef := range technique.TTPs {
+			metadata = append(metadata, LayerMetadata{Name: "ttp", Value: ttpRef})
+		}
+		layer.Techniques = append(layer.Techniques, LayerTechnique{
+			TechniqueID:       technique.ID,
+			Score:             score,
+			Comment:           fmt.Sprintf("Covered by %d TTP(s)", len(technique.TTPs)),
+			Enabled:           true,
+			Metadata:          metadata,
+			ShowSubtechniques: c.hasCoveredSubTechniques(technique.ID),
+		})
+	}
+	layer.Gradient = LayerGradient{
+		Colors:   []string{"#ffffff", "#66b1ff"},
+		MinValue: 0,
+		MaxValue: maxScore,
+	}
+	return layer, nil
+}
+
+// hasCoveredSubTechniques returns true if any
+// sub-technique of the given technique is covered
+func (c *Coverage) hasCoveredSubTechniques(id string) bool {
+	for covered := range c.techniques {
+		if strings.HasPrefix(covered, id+".") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,261 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/facebookincubator/ttpforge/pkg/parseutils"
+	"github.com/facebookincubator/ttpforge/pkg/repos"
+	"gopkg.in/yaml.v3"
+)
+
+// Severity describes how serious a finding is
+type Severity string
+
+const (
+	// SeverityError marks findings that will cause
+	// the TTP to fail or behave incorrectly
+	SeverityError Severity = "error"
+	// SeverityWarning marks findings that go against
+	// best practices but do not break the TTP
+	SeverityWarning Severity = "warning"
+)
+
+// Finding is a single problem reported by a lint rule
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	TTP      string   `json:"ttp"`
+	File     string   `json:"file"`
+	Line     int      `json:"line"`
+	Message  string   `json:"message"`
+}
+
+// String formats the finding in the conventional
+// `location: severity: message` style
+func (f Finding) String() string {
+	return fmt.Sprintf("%v:%d: %v: %v (%v)", f.TTP, f.Line, f.Severity, f.Message, f.Rule)
+}
+
+// File is a TTP file to be linted
+type File struct {
+	// Ref is the TTP reference (repo//path/to/ttp.yaml)
+	Ref string
+	// Path is the absolute path of the TTP file
+	Path string
+	// Repo is the repository containing the TTP, used
+	// to resolve the references of sub-TTP steps
+	Repo     repos.Repo
+	Contents []byte
+
+	lines    []string
+	root     *yaml.Node
+	parseErr error
+}
+
+// parse parses the file as YAML without rendering
+// it - see parseutils.StripTemplates
+func (f *File) parse() {
+	f.lines = strings.Split(string(f.Contents), "\n")
+	var doc yaml.Node
+	if err := yaml.Unmarshal(parseutils.StripTemplates(f.Contents), &doc); err != nil {
+		f.parseErr = err
+		return
+	}
+	if len(doc.Content) > 0 && doc.Content[0].Kind == yaml.MappingNode {
+		f.root = doc.Content[0]
+	} else {
+		f.root = &yaml.Node{Kind: yaml.MappingNode}
+	}
+}
+
+// isTemplated returns true if the given (1-based)
+// line of the file contains a template action
+func (f *File) isTemplated(line int) bool {
+	return line > 0 && line <= len(f.lines) && strings.Contains(f.lines[line-1], "{{")
+}
+
+// Run lints the target TTP files. Every file in corpus is
+// taken into account when looking for duplicate UUIDs, so that
+// a TTP can be checked against all installed repositories.
+//
+// **Parameters:**
+//
+// targets: the TTP files to lint
+// corpus: all known TTP files (may include the targets)
+//
+// **Returns:**
+//
+// []Finding: the findings that have not been suppressed,
+// sorted by TTP and line
+func Run(targets []*File, corpus []*File) []Finding {
+	l := &linter{uuids: make(map[string][]*File)}
+	seen := make(map[string]bool)
+	for _, f := range append(append([]*File{}, targets...), corpus...) {
+		// the same TTP may be both a target and part of the corpus
+		if seen[f.Path] {
+			continue
+		}
+		seen[f.Path] = true
+		f.parse()
+		if f.parseErr != nil {
+			continue
+		}
+		if _, uuid := mappingValue(f.root, "uuid"); uuid != nil && uuid.Value != "" {
+			l.uuids[uuid.Value] = append(l.uuids[uuid.Value], f)
+		}
+	}
+
+	var findings []Finding
+	for _, f := range targets {
+		if f.lines == nil {
+			f.parse()
+		}
+		if f.parseErr != nil {
+			findings = append(findings, Finding{
+				Rule:     "invalid-yaml",
+				Severity: SeverityError,
+				TTP:      f.Ref,
+				File:     f.Path,
+				Line:     1,
+				Message:  fmt.Sprintf("failed to parse TTP: %v", f.parseErr),
+			})
+			continue
+		}
+		suppressions := parseSuppressions(f.lines)
+		for _, rule := range Rules {
+			for _, p := range rule.check(l, f) {
+				if suppressions.suppressed(rule.Name, p.line) {
+					continue
+				}
+				findings = append(findings, Finding{
+					Rule:     rule.Name,
+					Severity: rule.Severity,
+					TTP:      f.Ref,
+					File:     f.Path,
+					Line:     p.line,
+					Message:  p.message,
+				})
+			}
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].TTP != findings[j].TTP {
+			return findings[i].TTP < findings[j].TTP
+		}
+		return findings[i].Line < findings[j].Line
+	})
+	return findings
+}
+
+// linter holds the state shared by all rules
+type linter struct {
+	// uuids maps each UUID to the files that declare it
+	uuids map[string][]*File
+}
+
+// problem is a finding reported by a rule check,
+// which Run completes with the details of the rule
+type problem struct {
+	line    int
+	message string
+}
+
+// suppressionRegexp matches suppression comments such as
+// `# ttpforge-lint-ignore missing-cleanup,unused-arg`
+var suppressionRegexp = regexp.MustCompile(`#\s*ttpforge-lint-ignore(-file)?\b([^#]*)`)
+
+// suppressions records the rules disabled by suppression
+// comments - the empty rule name stands for all rules
+type suppressions struct {
+	file  map[string]bool
+	lines map[int]map[string]bool
+}
+
+// parseSuppressions finds the suppression comments of a file.
+// A comment at the end of a line suppresses findings on that
+// line, while a comment on a line of its own suppresses
+// findings on the next line. `ttpforge-lint-ignore-file`
+// suppresses findings anywhere in the file.
+func parseSuppressions(lines []string) suppressions {
+	s := suppressions{
+		file:  make(map[string]bool),
+		lines: make(map[int]map[string]bool),
+	}
+	for i, line := range lines {
+		match := suppressionRegexp.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		rules := strings.FieldsFunc(match[2], func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		})
+		if len(rules) == 0 {
+			rules = []string{""}
+		}
+		target := s.file
+		if match[1] == "" {
+			// line numbers are 1-based
+			lineNum := i + 1
+			if strings.HasPrefix(strings.TrimSpace(line), "#") {
+				lineNum++
+			}
+			if s.lines[lineNum] == nil {
+				s.lines[lineNum] = make(map[string]bool)
+			}
+			target = s.lines[lineNum]
+		}
+		for _, rule := range rules {
+			target[rule] = true
+		}
+	}
+	return s
+}
+
+func (s suppressions) suppressed(rule string, line int) bool {
+	return s.file[""] || s.file[rule] || s.lines[line][""] || s.lines[line][rule]
+}
+
+// mappingValue looks up a key in a YAML mapping node
+//
+// **Parameters:**
+//
+// node: the mapping node
+// key: the key to look up
+//
+// **Returns:**
+//
+// *yaml.Node: the key node, or nil if the key is not present
+// *yaml.Node: the value node, or nil if the key is not present
+func mappingValue(node *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i], node.Content[i+1]
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,314 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package lint
+
+import (
+	"testing"
+
+	"github.com/facebookincubator/ttpforge/pkg/repos"
+	"github.com/facebookincubator/ttpforge/pkg/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// lintedTTP is the preamble of a TTP that has
+// no findings, to which test cases add steps
+const lintedTTP = `---
+api_version: 2.0
+uuid: 6d2e1f55-2b8f-4a8a-b0b5-1f3c2a9e8d10
+name: test
+description: a test TTP
+requirements:
+  platforms:
+    - os: linux
+`
+
+func makeTestRepo(t *testing.T) repos.Repo {
+	fsys, err := testutils.MakeAferoTestFs(map[string][]byte{
+		"repo/" + repos.RepoConfigFileName: []byte("ttp_search_paths: [\"ttps\"]\ntemplate_search_paths: [\"templates\"]"),
+		"repo/ttps/sub.yaml":               []byte(lintedTTP + "steps:\n  - name: s\n    print_str: hi\n"),
+		"repo/templates/config.tmpl":       []byte(`{"host": "{{ .Args.used_in_template }}"}`),
+	})
+	require.NoError(t, err)
+	repo, err := (&repos.Spec{Name: "repo", Path: "repo"}).Load(fsys, "")
+	require.NoError(t, err)
+	return repo
+}
+
+type expectedFinding struct {
+	rule string
+	line int
+}
+
+func TestRun(t *testing.T) {
+	testCases := []struct {
+		name     string
+		contents string
+		corpus   map[string]string
+		expected []expectedFinding
+	}{
+		{
+			name: "No Findings",
+			contents: lintedTTP + `mitre:
+  tactics:
+    - TA0002 Execution
+  techniques:
+    - T1059
+  subtechniques:
+    - T1059.004 Unix Shell
+args:
+  - name: used
+  - name: used_with_index
+steps:
+  - name: create
+    create_file: /tmp/{{ .Args.used }}
+    contents: {{ index .Args "used_with_index" }}
+    cleanup: default
+  - name: sub
+    ttp: //sub.yaml
+`,
+		},
+		{
+			name: "Missing Description And Platforms",
+			contents: `---
+name: test
+steps:
+  - name: s
+    print_str: hi
+`,
+			expected: []expectedFinding{
+				{rule: "missing-description", line: 2},
+				{rule: "missing-platforms", line: 2},
+			},
+		},
+		{
+			name: "Empty Platforms",
+			contents: `---
+name: test
+description: a test TTP
+requirements:
+  superuser: true
+steps:
+  - name: s
+    print_str: hi
+`,
+			expected: []expectedFinding{
+				{rule: "missing-platforms", line: 5},
+			},
+		},
+		{
+			name: "Duplicate UUID",
+			contents: lintedTTP + `steps:
+  - name: s
+    print_str: hi
+`,
+			corpus: map[string]string{
+				"repo//other.yaml": "uuid: 6d2e1f55-2b8f-4a8a-b0b5-1f3c2a9e8d10\n",
+			},
+			expected: []expectedFinding{
+				{rule: "duplicate-uuid", line: 3},
+			},
+		},
+		{
+			name: "Invalid MITRE IDs",
+			contents: lintedTTP + `mitre:
+  tactics:
+    - Execution
+  techniques:
+    - T1059.004
+  subtechniques:
+    - T1059
+steps:
+  - name: s
+    print_str: hi
+`,
+			expected: []expectedFinding{
+				{rule: "invalid-mitre", line: 11},
+				{rule: "invalid-mitre", line: 13},
+				{rule: "invalid-mitre", line: 15},
+			},
+		},
+		{
+			name: "Inconsistent MITRE Mapping",
+			contents: lintedTTP + `mitre:
+  tactics:
+    - TA0002 Execution
+  techniques:
+    - T1059
+    - T1552 Unsecured Credentials
+  subtechniques:
+    - T1555.001 Keychain
+steps:
+  - name: s
+    print_str: hi
+`,
+			expected: []expectedFinding{
+				{rule: "invalid-mitre", line: 14},
+				{rule: "invalid-mitre", line: 16},
+			},
+		},
+		{
+			name: "Unused Arg",
+			contents: lintedTTP + `args:
+  - name: used
+  - name: unused
+  - name: used_in_step_template
+  - name: used_in_template
+steps:
+  - name: s
+    print_str: {{ .Args.used }}
+  - name: t
+    if: eq .Args.used_in_step_template "yes"
+    print_str: hi
+  - name: r
+    render_template: config.tmpl
+    location: /tmp/config
+    cleanup: default
+`,
+			expected: []expectedFinding{
+				{rule: "unused-arg", line: 11},
+			},
+		},
+		{
+			name: "Unresolved TTP Reference",
+			contents: lintedTTP + `steps:
+  - name: missing
+    ttp: //missing.yaml
+  - name: parallel
+    parallel:
+      - name: also_missing
+        ttp: //also-missing.yaml
+  - name: templated
+    ttp: //{{ .Args.ttp }}.yaml
+`,
+			expected: []expectedFinding{
+				{rule: "unresolved-ttp-ref", line: 11},
+				{rule: "unresolved-ttp-ref", line: 15},
+			},
+		},
+		{
+			name: "Missing Cleanup",
+			contents: lintedTTP + `steps:
+  - name: create
+    create_file: /tmp/a
+  - name: copy
+    copy_path: /tmp/a
+    to: /tmp/b
+    cleanup: default
+  - name: fetch
+    fetch_uri: https://example.com
+    location: /tmp/c
+  - name: render
+    render_template: config.tmpl
+    location: /tmp/d
+  - name: listen
+    start_process: nc -l 4444
+`,
+			expected: []expectedFinding{
+				{rule: "missing-cleanup", line: 11},
+				{rule: "missing-cleanup", line: 17},
+				{rule: "missing-cleanup", line: 20},
+			},
+		},
+		{
+			name: "Templated Lines",
+			contents: lintedTTP + `args:
+  - name: create
+    type: bool
+steps:
+  {{ if .Args.create }}
+  - name: create
+    create_file: /tmp/a
+    cleanup: default
+  {{ end }}
+  - name: s
+    print_str: {{ .Args.create }}
+`,
+		},
+		{
+			name: "Suppressions",
+			contents: lintedTTP + `# ttpforge-lint-ignore-file invalid-mitre
+mitre:
+  tactics:
+    - Execution
+args:
+  - name: unused # ttpforge-lint-ignore unused-arg
+  # ttpforge-lint-ignore
+  - name: also_unused
+  - name: not_suppressed # ttpforge-lint-ignore missing-cleanup
+steps:
+  - name: create
+    # ttpforge-lint-ignore missing-cleanup, unused-arg
+    create_file: /tmp/a
+`,
+			expected: []expectedFinding{
+				{rule: "unused-arg", line: 17},
+			},
+		},
+		{
+			name:     "Invalid YAML",
+			contents: "name: [\n",
+			expected: []expectedFinding{
+				{rule: "invalid-yaml", line: 1},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := makeTestRepo(t)
+			target := &File{
+				Ref:      "repo//test.yaml",
+				Path:     "/repo/ttps/test.yaml",
+				Repo:     repo,
+				Contents: []byte(tc.contents),
+			}
+			corpus := []*File{target}
+			for ref, contents := range tc.corpus {
+				corpus = append(corpus, &File{
+					Ref:      ref,
+					Path:     "/" + ref,
+					Repo:     repo,
+					Contents: []byte(contents),
+				})
+			}
+
+			findings := Run([]*File{target}, corpus)
+			var actual []expectedFinding
+			for _, finding := range findings {
+				assert.Equal(t, target.Ref, finding.TTP)
+				assert.NotEmpty(t, finding.Message)
+				actual = append(actual, expectedFinding{rule: finding.Rule, line: finding.Line})
+			}
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestFindingString(t *testing.T) {
+	finding := Finding{
+		Rule:     "unused-arg",
+		Severity: SeverityWarning,
+		TTP:      "repo//test.yaml",
+		Line:     12,
+		Message:  `argument "foo" is never used`,
+	}
+	assert.Equal(t, `repo//test.yaml:12: warning: argument "foo" is never used (unused-arg)`, finding.String())
+}
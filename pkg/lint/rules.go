@@ -0,0 +1,289 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/facebookincubator/ttpforge/pkg/mitre"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+)
+
+// Rule is a check run against every linted TTP
+type Rule struct {
+	Name        string
+	Severity    Severity
+	Description string
+	check       func(l *linter, f *File) []problem
+}
+
+// Rules lists every lint rule, in the order in which they run
+var Rules = []Rule{
+	{
+		Name:        "missing-description",
+		Severity:    SeverityWarning,
+		Description: "TTPs should have a description",
+		check:       checkMissingDescription,
+	},
+	{
+		Name:        "missing-platforms",
+		Severity:    SeverityWarning,
+		Description: "TTPs should declare the platforms they support under requirements.platforms",
+		check:       checkMissingPlatforms,
+	},
+	{
+		Name:        "duplicate-uuid",
+		Severity:    SeverityError,
+		Description: "TTP UUIDs must be unique across all repositories",
+		check:       checkDuplicateUUID,
+	},
+	{
+		Name:        "invalid-mitre",
+		Severity:    SeverityError,
+		Description: "MITRE ATT&CK mappings must reference valid, consistent tactic and technique IDs",
+		check:       checkInvalidMitre,
+	},
+	{
+		Name:        "unused-arg",
+		Severity:    SeverityWarning,
+		Description: "arguments should be referenced somewhere in the TTP",
+		check:       checkUnusedArgs,
+	},
+	{
+		Name:        "unresolved-ttp-ref",
+		Severity:    SeverityError,
+		Description: "sub-TTP steps must reference TTPs that exist",
+		check:       checkUnresolvedTTPRefs,
+	},
+	{
+		Name:        "missing-cleanup",
+		Severity:    SeverityWarning,
+		Description: "steps that create files should clean them up",
+		check:       checkMissingCleanup,
+	},
+}
+
+// artefactActions lists the actions that leave files
+// behind on the target unless they are cleaned up
+var artefactActions = []string{"create_file", "render_template", "copy_path", "fetch_uri", "edit_file"}
+
+// nameLine returns the line on which problems that concern
+// the whole TTP are reported: the line of its name, if any
+func nameLine(f *File) int {
+	if key, _ := mappingValue(f.root, "name"); key != nil {
+		return key.Line
+	}
+	return 1
+}
+
+func checkMissingDescription(_ *linter, f *File) []problem {
+	_, description := mappingValue(f.root, "description")
+	if description != nil && strings.TrimSpace(description.Value) != "" {
+		return nil
+	}
+	return []problem{{line: nameLine(f), message: "TTP has no description"}}
+}
+
+func checkMissingPlatforms(_ *linter, f *File) []problem {
+	_, requirements := mappingValue(f.root, "requirements")
+	_, platforms := mappingValue(requirements, "platforms")
+	if platforms != nil && len(platforms.Content) > 0 {
+		return nil
+	}
+	line := nameLine(f)
+	if requirements != nil {
+		line = requirements.Line
+	}
+	return []problem{{line: line, message: "TTP does not declare its supported platforms (requirements.platforms)"}}
+}
+
+func checkDuplicateUUID(l *linter, f *File) []problem {
+	_, uuid := mappingValue(f.root, "uuid")
+	if uuid == nil || uuid.Value == "" {
+		return nil
+	}
+	var others []string
+	for _, other := range l.uuids[uuid.Value] {
+		if other.Path != f.Path {
+			others = append(others, other.Ref)
+		}
+	}
+	if len(others) == 0 {
+		return nil
+	}
+	sort.Strings(others)
+	return []problem{{
+		line:    uuid.Line,
+		message: fmt.Sprintf("UUID %v is also used by %v", uuid.Value, strings.Join(others, ", ")),
+	}}
+}
+
+func checkInvalidMitre(_ *linter, f *File) []problem {
+	_, mitreMapping := mappingValue(f.root, "mitre")
+	if mitreMapping == nil {
+		return nil
+	}
+	entryNodes := make(map[string][]*yaml.Node)
+	entries := make(map[string][]string)
+	for _, section := range []string{mitre.SectionTactics, mitre.SectionTechniques, mitre.SectionSubTechniques} {
+		_, list := mappingValue(mitreMapping, section)
+		if list == nil {
+			continue
+		}
+		for _, entry := range list.Content {
+			// templated entries cannot be checked before rendering
+			if f.isTemplated(entry.Line) {
+				continue
+			}
+			entryNodes[section] = append(entryNodes[section], entry)
+			entries[section] = append(entries[section], entry.Value)
+		}
+	}
+
+	var problems []problem
+	mappingErrors := mitre.Default().CheckMapping(entries[mitre.SectionTactics], entries[mitre.SectionTechniques], entries[mitre.SectionSubTechniques])
+	for _, mappingError := range mappingErrors {
+		problems = append(problems, problem{
+			line:    entryNodes[mappingError.Section][mappingError.Index].Line,
+			message: fmt.Sprintf("invalid MITRE ATT&CK mapping: %v", mappingError),
+		})
+	}
+	return problems
+}
+
+func checkUnusedArgs(_ *linter, f *File) []problem {
+	_, args := mappingValue(f.root, "args")
+	if args == nil {
+		return nil
+	}
+	// arguments may also be used only by the
+	// templates of render_template steps
+	contents := string(f.Contents) + f.renderedTemplates()
+	var problems []problem
+	for _, arg := range args.Content {
+		_, name := mappingValue(arg, "name")
+		if name == nil || name.Value == "" {
+			continue
+		}
+		quoted := regexp.QuoteMeta(name.Value)
+		used := regexp.MustCompile(`\.Args\.` + quoted + `\b|index\s+\.Args\s+"` + quoted + `"`)
+		if used.MatchString(contents) {
+			continue
+		}
+		problems = append(problems, problem{
+			line:    name.Line,
+			message: fmt.Sprintf("argument %q is never used", name.Value),
+		})
+	}
+	return problems
+}
+
+// renderedTemplates returns the contents of the template files
+// rendered by the render_template steps of the TTP, so far as
+// they can be found without rendering the TTP itself
+func (f *File) renderedTemplates() string {
+	if f.Repo == nil {
+		return ""
+	}
+	var contents strings.Builder
+	for _, step := range allSteps(f.root) {
+		_, tmpl := mappingValue(step, "render_template")
+		if tmpl == nil || tmpl.Kind != yaml.ScalarNode || f.isTemplated(tmpl.Line) {
+			continue
+		}
+		path, err := f.Repo.FindTemplate(tmpl.Value)
+		if err != nil {
+			continue
+		}
+		data, err := afero.ReadFile(f.Repo.GetFs(), path)
+		if err != nil {
+			continue
+		}
+		contents.WriteString("\n")
+		contents.Write(data)
+	}
+	return contents.String()
+}
+
+func checkUnresolvedTTPRefs(_ *linter, f *File) []problem {
+	if f.Repo == nil {
+		return nil
+	}
+	var problems []problem
+	for _, step := range allSteps(f.root) {
+		_, ref := mappingValue(step, "ttp")
+		if ref == nil || ref.Kind != yaml.ScalarNode || f.isTemplated(ref.Line) {
+			continue
+		}
+		if _, err := f.Repo.FindTTP(ref.Value); err != nil {
+			problems = append(problems, problem{
+				line:    ref.Line,
+				message: fmt.Sprintf("sub-TTP %q could not be found: %v", ref.Value, err),
+			})
+		}
+	}
+	return problems
+}
+
+func checkMissingCleanup(_ *linter, f *File) []problem {
+	var problems []problem
+	for _, step := range allSteps(f.root) {
+		if key, _ := mappingValue(step, "cleanup"); key != nil {
+			continue
+		}
+		for _, action := range artefactActions {
+			if key, _ := mappingValue(step, action); key != nil {
+				problems = append(problems, problem{
+					line:    key.Line,
+					message: fmt.Sprintf("%v step has no cleanup (consider `cleanup: default`)", action),
+				})
+				break
+			}
+		}
+	}
+	return problems
+}
+
+// allSteps returns the steps of a TTP, including
+// the steps nested within parallel steps
+func allSteps(root *yaml.Node) []*yaml.Node {
+	_, steps := mappingValue(root, "steps")
+	return collectSteps(steps)
+}
+
+func collectSteps(steps *yaml.Node) []*yaml.Node {
+	if steps == nil || steps.Kind != yaml.SequenceNode {
+		return nil
+	}
+	var result []*yaml.Node
+	for _, step := range steps.Content {
+		if step.Kind != yaml.MappingNode {
+			continue
+		}
+		result = append(result, step)
+		_, parallel := mappingValue(step, "parallel")
+		result = append(result, collectSteps(parallel)...)
+	}
+	return result
+}
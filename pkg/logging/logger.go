@@ -92,18 +92,27 @@ func (e *indentedEncoder) EncodeEntry(entry zapcore.Entry, fields []zapcore.Fiel
 	// Add indentations for subTTPs
 	buf.AppendString(strings.Repeat("\t", indentLevel))
 
+	// Mask secret values wherever they appear in the entry
+	if hasSecrets() {
+		entry, fields = redactEntry(entry, fields)
+	}
+
 	consolebuf, err := e.Encoder.EncodeEntry(entry, fields)
 	if err != nil {
 		return nil, err
 	}
-
-	_, err = buf.Write(consolebuf.Bytes())
-	if err != nil {
+	if _, err = buf.Write(consolebuf.Bytes()); err != nil {
 		return nil, err
 	}
 	return buf, nil
 }
 
+// AddString masks secret values in the fields
+// that are added to the context of a logger
+func (e *indentedEncoder) AddString(key, value string) {
+	e.Encoder.AddString(key, Redact(value))
+}
+
 func (e *indentedEncoder) Clone() zapcore.Encoder {
 	// Needed to handle indents in structured logging
 	return &indentedEncoder{
@@ -20,6 +20,7 @@ THE SOFTWARE.
 package logging
 
 import (
+	"errors"
 	"os"
 	"sync"
 	"testing"
@@ -91,6 +92,36 @@ func TestInitLog(t *testing.T) {
 				assert.NotContains(t, logFileContents, "\x1b[0m")
 			},
 		},
+		{
+			name:   "redacts-secrets",
+			config: Config{},
+			logFunc: func(t *testing.T, testLogger *zap.SugaredLogger) {
+				RegisterSecret("hunter2")
+				t.Cleanup(ClearSecrets)
+				testLogger.Infow("the password is hunter2", "password", "hunter2")
+			},
+			checkFunc: func(t *testing.T, logFileContents string) {
+				assert.NotContains(t, logFileContents, "hunter2")
+				assert.Contains(t, logFileContents, "the password is "+RedactedPlaceholder)
+			},
+		},
+		{
+			name:   "redacts-short-secrets",
+			config: Config{},
+			logFunc: func(t *testing.T, testLogger *zap.SugaredLogger) {
+				RegisterSecret("0")
+				t.Cleanup(ClearSecrets)
+				testLogger.With("ctx", "pin 0").Infow("the pin is 0", "pin", "0", "err", errors.New("bad pin 0"))
+			},
+			checkFunc: func(t *testing.T, logFileContents string) {
+				// the colour codes around the level are left intact
+				assert.Contains(t, logFileContents, "\x1b[34mINFO\x1b[0m")
+				assert.Contains(t, logFileContents, "the pin is "+RedactedPlaceholder)
+				assert.Contains(t, logFileContents, `"pin": "`+RedactedPlaceholder+`"`)
+				assert.Contains(t, logFileContents, `"ctx": "pin `+RedactedPlaceholder+`"`)
+				assert.Contains(t, logFileContents, `"err": "bad pin `+RedactedPlaceholder+`"`)
+			},
+		},
 	}
 
 	for _, tc := range tests {
@@ -0,0 +1,156 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// RedactedPlaceholder replaces secret values in logs and reports
+const RedactedPlaceholder = "********"
+
+// MinSecretLength is the length below which secret values are
+// likely to occur by chance in unrelated text - which is then
+// masked along with the secret
+const MinSecretLength = 4
+
+var (
+	secretsMu sync.RWMutex
+	secrets   []string
+)
+
+// RegisterSecret marks a value as secret so that it is
+// masked wherever it appears in log messages and in the
+// output of Redact. Empty values are ignored.
+//
+// **Parameters:**
+//
+// value: the secret value
+func RegisterSecret(value string) {
+	if value == "" {
+		return
+	}
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	for _, s := range secrets {
+		if s == value {
+			return
+		}
+	}
+	secrets = append(secrets, value)
+	// replace longer secrets first so that secrets which
+	// contain other secrets are masked completely
+	sort.Slice(secrets, func(i, j int) bool {
+		return len(secrets[i]) > len(secrets[j])
+	})
+}
+
+// ClearSecrets forgets all registered secret values
+func ClearSecrets() {
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	secrets = nil
+}
+
+// Redact masks every registered secret value in the provided string
+//
+// **Parameters:**
+//
+// s: the string to redact
+//
+// **Returns:**
+//
+// string: the string with every secret replaced by RedactedPlaceholder
+func Redact(s string) string {
+	secretsMu.RLock()
+	defer secretsMu.RUnlock()
+	if len(secrets) == 0 {
+		return s
+	}
+	// replace every secret in a single pass so that
+	// secrets are never matched inside a placeholder
+	oldNew := make([]string, 0, 2*len(secrets))
+	for _, secret := range secrets {
+		oldNew = append(oldNew, secret, RedactedPlaceholder)
+	}
+	return strings.NewReplacer(oldNew...).Replace(s)
+}
+
+// hasSecrets returns true if any secret value is registered
+func hasSecrets() bool {
+	secretsMu.RLock()
+	defer secretsMu.RUnlock()
+	return len(secrets) > 0
+}
+
+// redactEntry masks every registered secret value in the message
+// and field values of a log entry - the entry is redacted before
+// it is encoded so that secrets cannot match (and mangle) the
+// level names, colour codes and other text added by the encoder
+func redactEntry(entry zapcore.Entry, fields []zapcore.Field) (zapcore.Entry, []zapcore.Field) {
+	entry.Message = Redact(entry.Message)
+	entry.Stack = Redact(entry.Stack)
+	redacted := make([]zapcore.Field, len(fields))
+	for idx, field := range fields {
+		redacted[idx] = redactField(field)
+	}
+	return entry, redacted
+}
+
+// redactField masks every registered secret value in the value of a field
+func redactField(field zapcore.Field) zapcore.Field {
+	var text string
+	switch field.Type {
+	case zapcore.StringType:
+		field.String = Redact(field.String)
+		return field
+	case zapcore.ByteStringType:
+		if b, ok := field.Interface.([]byte); ok {
+			text = string(b)
+		}
+	case zapcore.ErrorType:
+		if err, ok := field.Interface.(error); ok {
+			text = err.Error()
+		}
+	case zapcore.StringerType:
+		if s, ok := field.Interface.(fmt.Stringer); ok {
+			text = s.String()
+		}
+	case zapcore.ReflectType:
+		b, err := json.Marshal(field.Interface)
+		if err != nil {
+			return field
+		}
+		text = string(b)
+	default:
+		return field
+	}
+	if redacted := Redact(text); redacted != text {
+		return zap.String(field.Key, redacted)
+	}
+	return field
+}
@@ -0,0 +1,75 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package logging
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedact(t *testing.T) {
+	testCases := []struct {
+		name     string
+		secrets  []string
+		input    string
+		expected string
+	}{
+		{
+			name:     "No Secrets",
+			input:    "nothing to hide",
+			expected: "nothing to hide",
+		},
+		{
+			name:     "Every Occurrence",
+			secrets:  []string{"s3cr3t"},
+			input:    "s3cr3t and s3cr3t again",
+			expected: RedactedPlaceholder + " and " + RedactedPlaceholder + " again",
+		},
+		{
+			name:     "Overlapping Secrets",
+			secrets:  []string{"token", "token-suffix"},
+			input:    "value: token-suffix",
+			expected: "value: " + RedactedPlaceholder,
+		},
+		{
+			name:     "Secret Inside Placeholder",
+			secrets:  []string{"pw", "*"},
+			input:    "pw *",
+			expected: RedactedPlaceholder + " " + RedactedPlaceholder,
+		},
+		{
+			name:     "Empty Secret Ignored",
+			secrets:  []string{""},
+			input:    "unchanged",
+			expected: "unchanged",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Cleanup(ClearSecrets)
+			for _, secret := range tc.secrets {
+				RegisterSecret(secret)
+			}
+			assert.Equal(t, tc.expected, Redact(tc.input))
+		})
+	}
+}
@@ -0,0 +1,280 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+// Package mitre provides a catalogue of the tactics, techniques
+// and sub-techniques of the MITRE ATT&CK Enterprise matrix, which is
+// used to validate the MITRE ATT&CK mappings of TTPs and to look up
+// the names of the IDs that they reference.
+package mitre
+
+import (
+	"bytes"
+	// needed for embedded filesystem
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+//go:embed enterprise-attack.json
+var embeddedCatalogue []byte
+
+// Tactic is a MITRE ATT&CK tactic, such as TA0005 (Defense Evasion)
+type Tactic struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// ShortName identifies the tactic in the
+	// kill chain phases of STIX attack patterns
+	ShortName string `json:"shortname"`
+}
+
+// Technique is a MITRE ATT&CK technique, such as T1055
+// (Process Injection), or a sub-technique, such as T1055.011
+// (Extra Window Memory Injection)
+type Technique struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Tactics holds the IDs of the tactics that the technique belongs to
+	Tactics []string `json:"tactics"`
+}
+
+// IsSubTechnique returns true if the technique is a sub-technique
+func (t Technique) IsSubTechnique() bool {
+	return strings.Contains(t.ID, ".")
+}
+
+// ParentID returns the ID of the parent technique of a
+// sub-technique, or an empty string for other techniques
+func (t Technique) ParentID() string {
+	parentID, _, found := strings.Cut(t.ID, ".")
+	if !found {
+		return ""
+	}
+	return parentID
+}
+
+// Catalogue holds the tactics and techniques of a version of the
+// MITRE ATT&CK Enterprise matrix
+type Catalogue struct {
+	Version    string      `json:"version"`
+	Tactics    []Tactic    `json:"tactics"`
+	Techniques []Technique `json:"techniques"`
+
+	tacticsByID    map[string]Tactic
+	techniquesByID map[string]Technique
+}
+
+// index builds the lookup tables of the catalogue
+func (c *Catalogue) index() {
+	c.tacticsByID = make(map[string]Tactic, len(c.Tactics))
+	for _, tactic := range c.Tactics {
+		c.tacticsByID[tactic.ID] = tactic
+	}
+	c.techniquesByID = make(map[string]Technique, len(c.Techniques))
+	for _, technique := range c.Techniques {
+		c.techniquesByID[technique.ID] = technique
+	}
+}
+
+// Tactic looks up a tactic by ID
+//
+// **Parameters:**
+//
+// id: the ID of the tactic (such as TA0005)
+//
+// **Returns:**
+//
+// Tactic: the tactic
+// bool: whether the tactic is in the catalogue
+func (c *Catalogue) Tactic(id string) (Tactic, bool) {
+	tactic, ok := c.tacticsByID[strings.ToUpper(id)]
+	return tactic, ok
+}
+
+// Technique looks up a technique or sub-technique by ID
+//
+// **Parameters:**
+//
+// id: the ID of the technique (such as T1055 or T1055.011)
+//
+// **Returns:**
+//
+// Technique: the technique
+// bool: whether the technique is in the catalogue
+func (c *Catalogue) Technique(id string) (Technique, bool) {
+	technique, ok := c.techniquesByID[strings.ToUpper(id)]
+	return technique, ok
+}
+
+// Kinds of the IDs in the catalogue, as returned by Kind
+const (
+	KindTactic       = "tactic"
+	KindTechnique    = "technique"
+	KindSubTechnique = "sub-technique"
+)
+
+// Kind returns whether an ID is that of a tactic, technique or
+// sub-technique, or an empty string if it is not in the catalogue
+func (c *Catalogue) Kind(id string) string {
+	if _, ok := c.Tactic(id); ok {
+		return KindTactic
+	}
+	technique, ok := c.Technique(id)
+	switch {
+	case !ok:
+		return ""
+	case technique.IsSubTechnique():
+		return KindSubTechnique
+	default:
+		return KindTechnique
+	}
+}
+
+// Name returns the name of the tactic or technique with
+// the given ID, or an empty string if it is not in the catalogue.
+// The names of sub-techniques are prefixed with the name of
+// their parent technique, as in the ATT&CK website.
+func (c *Catalogue) Name(id string) string {
+	if tactic, ok := c.Tactic(id); ok {
+		return tactic.Name
+	}
+	technique, ok := c.Technique(id)
+	if !ok {
+		return ""
+	}
+	if parent, ok := c.Technique(technique.ParentID()); ok {
+		return parent.Name + ": " + technique.Name
+	}
+	return technique.Name
+}
+
+// Describe formats a mapping entry such as "T1055" or
+// "T1055 Process Injection" as its ID followed by the name
+// of the technique in the catalogue - entries whose ID is
+// not in the catalogue are returned as is.
+func (c *Catalogue) Describe(entry string) string {
+	id, _ := ParseEntry(entry)
+	name := c.Name(id)
+	if name == "" {
+		return entry
+	}
+	return strings.ToUpper(id) + " " + name
+}
+
+// Parse loads a catalogue in the format written by Write
+//
+// **Parameters:**
+//
+// data: the JSON encoded catalogue
+//
+// **Returns:**
+//
+// *Catalogue: the catalogue
+// error: an error if the catalogue is malformed
+func Parse(data []byte) (*Catalogue, error) {
+	var c Catalogue
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to parse MITRE ATT&CK catalogue: %w", err)
+	}
+	if len(c.Tactics) == 0 || len(c.Techniques) == 0 {
+		return nil, fmt.Errorf("MITRE ATT&CK catalogue does not contain any tactics or techniques")
+	}
+	c.index()
+	return &c, nil
+}
+
+// Write writes the catalogue as JSON with one tactic or
+// technique per line, which keeps changes easy to review
+//
+// **Parameters:**
+//
+// w: the writer to write the catalogue to
+//
+// **Returns:**
+//
+// error: an error if the catalogue could not be written
+func (c *Catalogue) Write(w io.Writer) error {
+	var b bytes.Buffer
+	version, err := json.Marshal(c.Version)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(&b, "{\n  \"version\": %s,\n  \"tactics\": [\n", version)
+	for i, tactic := range c.Tactics {
+		if err := writeLine(&b, tactic, i == len(c.Tactics)-1); err != nil {
+			return err
+		}
+	}
+	b.WriteString("  ],\n  \"techniques\": [\n")
+	for i, technique := range c.Techniques {
+		if err := writeLine(&b, technique, i == len(c.Techniques)-1); err != nil {
+			return err
+		}
+	}
+	b.WriteString("  ]\n}\n")
+	_, err = w.Write(b.Bytes())
+	return err
+}
+
+func writeLine(b *bytes.Buffer, v interface{}, last bool) error {
+	var line bytes.Buffer
+	encoder := json.NewEncoder(&line)
+	// keep names such as "AutoHotKey & AutoIT" readable
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return err
+	}
+	b.WriteString("    ")
+	b.Write(bytes.TrimSuffix(line.Bytes(), []byte("\n")))
+	if !last {
+		b.WriteByte(',')
+	}
+	b.WriteByte('\n')
+	return nil
+}
+
+var (
+	defaultMu        sync.Mutex
+	defaultCatalogue *Catalogue
+)
+
+// Default returns the catalogue used to validate TTPs - the
+// catalogue embedded in TTPForge unless SetDefault was called
+func Default() *Catalogue {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if defaultCatalogue == nil {
+		c, err := Parse(embeddedCatalogue)
+		if err != nil {
+			panic(fmt.Sprintf("embedded MITRE ATT&CK catalogue is invalid: %v", err))
+		}
+		defaultCatalogue = c
+	}
+	return defaultCatalogue
+}
+
+// SetDefault replaces the catalogue returned by Default,
+// such as with a catalogue refreshed from a newer ATT&CK release
+func SetDefault(c *Catalogue) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultCatalogue = c
+}
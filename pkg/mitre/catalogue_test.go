@@ -0,0 +1,125 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package mitre
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultCatalogue(t *testing.T) {
+	c := Default()
+	assert.NotEmpty(t, c.Version)
+	assert.Len(t, c.Tactics, 14)
+
+	tactic, ok := c.Tactic("TA0005")
+	require.True(t, ok)
+	assert.Equal(t, "Defense Evasion", tactic.Name)
+
+	technique, ok := c.Technique("t1055.011")
+	require.True(t, ok)
+	assert.True(t, technique.IsSubTechnique())
+	assert.Equal(t, "T1055", technique.ParentID())
+	assert.Equal(t, []string{"TA0004", "TA0005"}, technique.Tactics)
+
+	// every sub-technique must have a parent technique
+	for _, technique := range c.Techniques {
+		if technique.IsSubTechnique() {
+			_, ok := c.Technique(technique.ParentID())
+			assert.True(t, ok, "parent of %v not found", technique.ID)
+		}
+		for _, tacticID := range technique.Tactics {
+			_, ok := c.Tactic(tacticID)
+			assert.True(t, ok, "tactic %v of %v not found", tacticID, technique.ID)
+		}
+	}
+}
+
+func TestNameAndDescribe(t *testing.T) {
+	c := Default()
+	testCases := []struct {
+		entry            string
+		expectedName     string
+		expectedDescribe string
+	}{
+		{
+			entry:            "TA0006",
+			expectedName:     "Credential Access",
+			expectedDescribe: "TA0006 Credential Access",
+		},
+		{
+			entry:            "T1552 Unsecured Credentials",
+			expectedName:     "Unsecured Credentials",
+			expectedDescribe: "T1552 Unsecured Credentials",
+		},
+		{
+			entry:            "t1552.001",
+			expectedName:     "Unsecured Credentials: Credentials In Files",
+			expectedDescribe: "T1552.001 Unsecured Credentials: Credentials In Files",
+		},
+		{
+			entry:            "T9999 Made Up",
+			expectedDescribe: "T9999 Made Up",
+		},
+		{
+			entry:            "Execution",
+			expectedDescribe: "Execution",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.entry, func(t *testing.T) {
+			id, _ := ParseEntry(tc.entry)
+			assert.Equal(t, tc.expectedName, c.Name(id))
+			assert.Equal(t, tc.expectedDescribe, c.Describe(tc.entry))
+		})
+	}
+}
+
+func TestWriteAndParse(t *testing.T) {
+	var b bytes.Buffer
+	require.NoError(t, Default().Write(&b))
+	assert.Equal(t, string(embeddedCatalogue), b.String(), "the embedded catalogue should be in the format written by Write")
+
+	c, err := Parse(b.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, Default().Tactics, c.Tactics)
+	assert.Equal(t, Default().Techniques, c.Techniques)
+
+	_, err = Parse([]byte(`{"version": "1"}`))
+	assert.Error(t, err)
+	_, err = Parse([]byte(`not json`))
+	assert.Error(t, err)
+}
+
+func TestSetDefault(t *testing.T) {
+	original := Default()
+	defer SetDefault(original)
+
+	c, err := Parse([]byte(`{"version":"test","tactics":[{"id":"TA0002","name":"Execution","shortname":"execution"}],"techniques":[{"id":"T1059","name":"Command and Scripting Interpreter","tactics":["TA0002"]}]}`))
+	require.NoError(t, err)
+	SetDefault(c)
+	assert.Equal(t, "test", Default().Version)
+	_, ok := Default().Technique("T1055")
+	assert.False(t, ok)
+}
@@ -0,0 +1,158 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package mitre
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+)
+
+// attackSourceName identifies the external references and
+// kill chains of STIX objects that belong to MITRE ATT&CK
+const attackSourceName = "mitre-attack"
+
+// stixObject holds the fields of the STIX objects of
+// an ATT&CK bundle that are needed to build a catalogue
+type stixObject struct {
+	Type               string   `json:"type"`
+	ID                 string   `json:"id"`
+	Name               string   `json:"name"`
+	Revoked            bool     `json:"revoked"`
+	Deprecated         bool     `json:"x_mitre_deprecated"`
+	ShortName          string   `json:"x_mitre_shortname"`
+	Version            string   `json:"x_mitre_version"`
+	TacticRefs         []string `json:"tactic_refs"`
+	ExternalReferences []struct {
+		SourceName string `json:"source_name"`
+		ExternalID string `json:"external_id"`
+	} `json:"external_references"`
+	KillChainPhases []struct {
+		KillChainName string `json:"kill_chain_name"`
+		PhaseName     string `json:"phase_name"`
+	} `json:"kill_chain_phases"`
+}
+
+// attackID returns the ATT&CK ID (such as T1055) of the object
+func (o stixObject) attackID() string {
+	for _, ref := range o.ExternalReferences {
+		if ref.SourceName == attackSourceName {
+			return ref.ExternalID
+		}
+	}
+	return ""
+}
+
+// ParseSTIXBundle builds a catalogue from a MITRE ATT&CK STIX bundle, such as
+// enterprise-attack.json from https://github.com/mitre-attack/attack-stix-data.
+// Revoked and deprecated techniques are left out of the catalogue.
+//
+// **Parameters:**
+//
+// data: the JSON encoded STIX bundle
+//
+// **Returns:**
+//
+// *Catalogue: the catalogue
+// error: an error if the bundle is malformed or does not contain ATT&CK data
+func ParseSTIXBundle(data []byte) (*Catalogue, error) {
+	var bundle struct {
+		Type    string       `json:"type"`
+		Objects []stixObject `json:"objects"`
+	}
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, fmt.Errorf("failed to parse STIX bundle: %w", err)
+	}
+	if bundle.Type != "bundle" {
+		return nil, fmt.Errorf("file is not a STIX bundle (type is %q)", bundle.Type)
+	}
+
+	c := &Catalogue{}
+	tacticsBySTIXID := make(map[string]Tactic)
+	tacticIDsByShortName := make(map[string]string)
+	var tacticOrder []string
+	for _, obj := range bundle.Objects {
+		if obj.Revoked || obj.Deprecated {
+			continue
+		}
+		switch obj.Type {
+		case "x-mitre-collection":
+			c.Version = obj.Version
+		case "x-mitre-matrix":
+			tacticOrder = append(tacticOrder, obj.TacticRefs...)
+		case "x-mitre-tactic":
+			tactic := Tactic{ID: obj.attackID(), Name: obj.Name, ShortName: obj.ShortName}
+			tacticsBySTIXID[obj.ID] = tactic
+			tacticIDsByShortName[tactic.ShortName] = tactic.ID
+		}
+	}
+
+	// tactics are listed in the order of the matrix (as in the
+	// ATT&CK website) if the bundle describes it, by ID otherwise
+	for _, ref := range tacticOrder {
+		if tactic, ok := tacticsBySTIXID[ref]; ok && !slices.ContainsFunc(c.Tactics, func(t Tactic) bool { return t.ID == tactic.ID }) {
+			c.Tactics = append(c.Tactics, tactic)
+		}
+	}
+	if len(c.Tactics) != len(tacticsBySTIXID) {
+		c.Tactics = nil
+		for _, tactic := range tacticsBySTIXID {
+			c.Tactics = append(c.Tactics, tactic)
+		}
+		sort.Slice(c.Tactics, func(i, j int) bool { return c.Tactics[i].ID < c.Tactics[j].ID })
+	}
+	tacticOrderByID := make(map[string]int, len(c.Tactics))
+	for i, tactic := range c.Tactics {
+		tacticOrderByID[tactic.ID] = i
+	}
+
+	for _, obj := range bundle.Objects {
+		if obj.Type != "attack-pattern" || obj.Revoked || obj.Deprecated {
+			continue
+		}
+		id := obj.attackID()
+		if id == "" {
+			continue
+		}
+		technique := Technique{ID: id, Name: obj.Name}
+		for _, phase := range obj.KillChainPhases {
+			tacticID, ok := tacticIDsByShortName[phase.PhaseName]
+			if phase.KillChainName != attackSourceName || !ok || slices.Contains(technique.Tactics, tacticID) {
+				continue
+			}
+			technique.Tactics = append(technique.Tactics, tacticID)
+		}
+		sort.Slice(technique.Tactics, func(i, j int) bool {
+			return tacticOrderByID[technique.Tactics[i]] < tacticOrderByID[technique.Tactics[j]]
+		})
+		c.Techniques = append(c.Techniques, technique)
+	}
+	sort.Slice(c.Techniques, func(i, j int) bool { return c.Techniques[i].ID < c.Techniques[j].ID })
+
+	if len(c.Tactics) == 0 || len(c.Techniques) == 0 {
+		return nil, fmt.Errorf("STIX bundle does not contain any MITRE ATT&CK tactics or techniques")
+	}
+	if c.Version == "" {
+		c.Version = "unknown"
+	}
+	c.index()
+	return c, nil
+}
@@ -0,0 +1,91 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package mitre
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSTIXBundle = `{
+  "type": "bundle",
+  "id": "bundle--1",
+  "objects": [
+    {"type": "x-mitre-collection", "id": "x-mitre-collection--1", "name": "Enterprise ATT&CK", "x_mitre_version": "99.0"},
+    {"type": "x-mitre-matrix", "id": "x-mitre-matrix--1", "tactic_refs": ["x-mitre-tactic--exec", "x-mitre-tactic--priv", "x-mitre-tactic--def"]},
+    {"type": "x-mitre-tactic", "id": "x-mitre-tactic--def", "name": "Defense Evasion", "x_mitre_shortname": "defense-evasion",
+     "external_references": [{"source_name": "mitre-attack", "external_id": "TA0005"}]},
+    {"type": "x-mitre-tactic", "id": "x-mitre-tactic--exec", "name": "Execution", "x_mitre_shortname": "execution",
+     "external_references": [{"source_name": "mitre-attack", "external_id": "TA0002"}]},
+    {"type": "x-mitre-tactic", "id": "x-mitre-tactic--priv", "name": "Privilege Escalation", "x_mitre_shortname": "privilege-escalation",
+     "external_references": [{"source_name": "mitre-attack", "external_id": "TA0004"}]},
+    {"type": "attack-pattern", "id": "attack-pattern--1", "name": "Process Injection",
+     "external_references": [{"source_name": "mitre-attack", "external_id": "T1055"}, {"source_name": "capec", "external_id": "CAPEC-640"}],
+     "kill_chain_phases": [{"kill_chain_name": "mitre-attack", "phase_name": "defense-evasion"}, {"kill_chain_name": "mitre-attack", "phase_name": "privilege-escalation"}]},
+    {"type": "attack-pattern", "id": "attack-pattern--2", "name": "Extra Window Memory Injection", "x_mitre_is_subtechnique": true,
+     "external_references": [{"source_name": "mitre-attack", "external_id": "T1055.011"}],
+     "kill_chain_phases": [{"kill_chain_name": "mitre-attack", "phase_name": "defense-evasion"}, {"kill_chain_name": "mitre-attack", "phase_name": "privilege-escalation"}]},
+    {"type": "attack-pattern", "id": "attack-pattern--3", "name": "Revoked Technique", "revoked": true,
+     "external_references": [{"source_name": "mitre-attack", "external_id": "T1000"}],
+     "kill_chain_phases": [{"kill_chain_name": "mitre-attack", "phase_name": "execution"}]},
+    {"type": "attack-pattern", "id": "attack-pattern--4", "name": "Deprecated Technique", "x_mitre_deprecated": true,
+     "external_references": [{"source_name": "mitre-attack", "external_id": "T1001"}],
+     "kill_chain_phases": [{"kill_chain_name": "mitre-attack", "phase_name": "execution"}]},
+    {"type": "relationship", "id": "relationship--1", "relationship_type": "subtechnique-of",
+     "source_ref": "attack-pattern--2", "target_ref": "attack-pattern--1"}
+  ]
+}`
+
+func TestParseSTIXBundle(t *testing.T) {
+	c, err := ParseSTIXBundle([]byte(testSTIXBundle))
+	require.NoError(t, err)
+
+	assert.Equal(t, "99.0", c.Version)
+	assert.Equal(t, []Tactic{
+		{ID: "TA0002", Name: "Execution", ShortName: "execution"},
+		{ID: "TA0004", Name: "Privilege Escalation", ShortName: "privilege-escalation"},
+		{ID: "TA0005", Name: "Defense Evasion", ShortName: "defense-evasion"},
+	}, c.Tactics)
+	assert.Equal(t, []Technique{
+		{ID: "T1055", Name: "Process Injection", Tactics: []string{"TA0004", "TA0005"}},
+		{ID: "T1055.011", Name: "Extra Window Memory Injection", Tactics: []string{"TA0004", "TA0005"}},
+	}, c.Techniques)
+	assert.Equal(t, "Process Injection: Extra Window Memory Injection", c.Name("T1055.011"))
+}
+
+func TestParseSTIXBundleErrors(t *testing.T) {
+	testCases := []struct {
+		name    string
+		content string
+	}{
+		{name: "Invalid JSON", content: "{"},
+		{name: "Not A Bundle", content: `{"type": "attack-pattern"}`},
+		{name: "Empty Bundle", content: `{"type": "bundle", "objects": []}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseSTIXBundle([]byte(tc.content))
+			assert.Error(t, err)
+		})
+	}
+}
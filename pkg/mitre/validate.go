@@ -0,0 +1,199 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package mitre
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Mapping sections, as named in the `mitre:` section of TTPs
+const (
+	SectionTactics       = "tactics"
+	SectionTechniques    = "techniques"
+	SectionSubTechniques = "subtechniques"
+)
+
+// entryIDRegexp matches the ID at the start of a mapping entry
+var entryIDRegexp = regexp.MustCompile(`^(?i)(TA\d{4}|T\d{4}(\.\d{3})?)\b`)
+
+// ParseEntry splits an entry of a MITRE ATT&CK mapping,
+// such as "T1055 Process Injection", into its ID and name
+//
+// **Parameters:**
+//
+// entry: the mapping entry
+//
+// **Returns:**
+//
+// string: the ID, or an empty string if the entry does not start with one
+// string: the rest of the entry
+func ParseEntry(entry string) (string, string) {
+	entry = strings.TrimSpace(entry)
+	id := entryIDRegexp.FindString(entry)
+	return strings.ToUpper(id), strings.TrimSpace(entry[len(id):])
+}
+
+// MappingError describes a problem with
+// an entry of a MITRE ATT&CK mapping
+type MappingError struct {
+	// Section is the section of the mapping
+	// that contains the entry (such as "techniques")
+	Section string
+	// Index is the index of the entry in its section
+	Index   int
+	Entry   string
+	Message string
+}
+
+// Error implements the error interface
+func (e *MappingError) Error() string {
+	return fmt.Sprintf("%v entry %q: %v", e.Section, e.Entry, e.Message)
+}
+
+// CheckMapping checks that every entry of a MITRE ATT&CK mapping starts
+// with the ID of a tactic, technique or sub-technique (as appropriate for
+// its section) and that the listed IDs are consistent with each other: each
+// technique must belong to one of the listed tactics and the parent
+// technique of each sub-technique must be listed.
+//
+// **Parameters:**
+//
+// tactics: the tactic entries of the mapping
+// techniques: the technique entries of the mapping
+// subTechniques: the sub-technique entries of the mapping
+//
+// **Returns:**
+//
+// []*MappingError: the problems found, in the order of the entries
+func (c *Catalogue) CheckMapping(tactics, techniques, subTechniques []string) []*MappingError {
+	var problems []*MappingError
+	report := func(section string, index int, entry string, format string, a ...interface{}) {
+		problems = append(problems, &MappingError{
+			Section: section,
+			Index:   index,
+			Entry:   entry,
+			Message: fmt.Sprintf(format, a...),
+		})
+	}
+
+	var tacticIDs []string
+	for i, entry := range tactics {
+		id, _ := ParseEntry(entry)
+		if _, ok := c.Tactic(id); !ok {
+			report(SectionTactics, i, entry, "%v", c.unknownEntryMessage(id, entry, KindTactic))
+			continue
+		}
+		tacticIDs = append(tacticIDs, id)
+	}
+
+	var techniqueIDs []string
+	for i, entry := range techniques {
+		id, _ := ParseEntry(entry)
+		technique, ok := c.Technique(id)
+		switch {
+		case !ok:
+			report(SectionTechniques, i, entry, "%v", c.unknownEntryMessage(id, entry, KindTechnique))
+			continue
+		case technique.IsSubTechnique():
+			report(SectionTechniques, i, entry, "%v is a sub-technique and must be listed under %v", id, SectionSubTechniques)
+			continue
+		}
+		techniqueIDs = append(techniqueIDs, id)
+		if len(tacticIDs) > 0 && !slices.ContainsFunc(technique.Tactics, func(t string) bool {
+			return slices.Contains(tacticIDs, t)
+		}) {
+			report(SectionTechniques, i, entry, "technique %v does not belong to any of the listed tactics (it belongs to %v)", id, c.describeTactics(technique.Tactics))
+		}
+	}
+
+	for i, entry := range subTechniques {
+		id, _ := ParseEntry(entry)
+		technique, ok := c.Technique(id)
+		switch {
+		case !ok:
+			report(SectionSubTechniques, i, entry, "%v", c.unknownEntryMessage(id, entry, KindSubTechnique))
+		case !technique.IsSubTechnique():
+			report(SectionSubTechniques, i, entry, "%v is not a sub-technique and must be listed under %v", id, SectionTechniques)
+		case !slices.Contains(techniqueIDs, technique.ParentID()):
+			report(SectionSubTechniques, i, entry, "the parent technique of %v (%v) must be listed under %v", id, c.Describe(technique.ParentID()), SectionTechniques)
+		}
+	}
+	return problems
+}
+
+// ValidateMapping checks a MITRE ATT&CK mapping as described in
+// CheckMapping, returning an error that lists every problem found
+//
+// **Parameters:**
+//
+// tactics: the tactic entries of the mapping
+// techniques: the technique entries of the mapping
+// subTechniques: the sub-technique entries of the mapping
+//
+// **Returns:**
+//
+// error: an error if the mapping is invalid
+func (c *Catalogue) ValidateMapping(tactics, techniques, subTechniques []string) error {
+	var errs []error
+	for _, problem := range c.CheckMapping(tactics, techniques, subTechniques) {
+		errs = append(errs, problem)
+	}
+	return errors.Join(errs...)
+}
+
+// unknownEntryMessage explains why an entry is not in
+// the catalogue, suggesting the right ID if the entry
+// is the name of a tactic or technique
+func (c *Catalogue) unknownEntryMessage(id string, entry string, kind string) string {
+	if id != "" {
+		return fmt.Sprintf("unknown %v %v (MITRE ATT&CK version %v)", kind, id, c.Version)
+	}
+	name := strings.TrimSpace(entry)
+	for _, tactic := range c.Tactics {
+		if strings.EqualFold(tactic.Name, name) {
+			return fmt.Sprintf("entry must start with the ID of the %v (%v %v)", kind, tactic.ID, tactic.Name)
+		}
+	}
+	for _, technique := range c.Techniques {
+		if strings.EqualFold(technique.Name, name) || strings.EqualFold(c.Name(technique.ID), name) {
+			return fmt.Sprintf("entry must start with the ID of the %v (%v)", kind, c.Describe(technique.ID))
+		}
+	}
+	return fmt.Sprintf("entry must start with the ID of the %v (such as %v)", kind, exampleIDs[kind])
+}
+
+// describeTactics formats a list of tactic IDs along with their names
+func (c *Catalogue) describeTactics(ids []string) string {
+	descriptions := make([]string, len(ids))
+	for i, id := range ids {
+		descriptions[i] = c.Describe(id)
+	}
+	return strings.Join(descriptions, ", ")
+}
+
+var exampleIDs = map[string]string{
+	KindTactic:       "TA0005",
+	KindTechnique:    "T1055",
+	KindSubTechnique: "T1055.011",
+}
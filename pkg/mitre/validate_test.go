@@ -0,0 +1,145 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package mitre
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEntry(t *testing.T) {
+	testCases := []struct {
+		entry        string
+		expectedID   string
+		expectedName string
+	}{
+		{entry: "TA0005 Defense Evasion", expectedID: "TA0005", expectedName: "Defense Evasion"},
+		{entry: "T1055", expectedID: "T1055"},
+		{entry: " t1055.011  Extra Window Memory Injection", expectedID: "T1055.011", expectedName: "Extra Window Memory Injection"},
+		{entry: "T10555", expectedName: "T10555"},
+		{entry: "Execution", expectedName: "Execution"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.entry, func(t *testing.T) {
+			id, name := ParseEntry(tc.entry)
+			assert.Equal(t, tc.expectedID, id)
+			assert.Equal(t, tc.expectedName, name)
+		})
+	}
+}
+
+func TestCheckMapping(t *testing.T) {
+	testCases := []struct {
+		name          string
+		tactics       []string
+		techniques    []string
+		subTechniques []string
+		expected      []MappingError
+	}{
+		{
+			name:          "Valid Mapping",
+			tactics:       []string{"TA0006 Credential Access"},
+			techniques:    []string{"T1552 Unsecured Credentials"},
+			subTechniques: []string{"T1552.001 Unsecured Credentials: Credentials In Files"},
+		},
+		{
+			name:       "Technique In Any Listed Tactic",
+			tactics:    []string{"TA0002", "TA0005"},
+			techniques: []string{"T1055", "T1059"},
+		},
+		{
+			name:    "Unknown IDs",
+			tactics: []string{"TA0099", "TA0002"},
+			techniques: []string{
+				"T9999 Made Up",
+				"T1059",
+			},
+			subTechniques: []string{"T1059.999"},
+			expected: []MappingError{
+				{Section: SectionTactics, Index: 0, Entry: "TA0099"},
+				{Section: SectionTechniques, Index: 0, Entry: "T9999 Made Up"},
+				{Section: SectionSubTechniques, Index: 0, Entry: "T1059.999"},
+			},
+		},
+		{
+			name:          "Names Instead Of IDs",
+			tactics:       []string{"Execution"},
+			techniques:    []string{"Command and Scripting Interpreter"},
+			subTechniques: []string{"Attachment"},
+			expected: []MappingError{
+				{Section: SectionTactics, Index: 0, Entry: "Execution", Message: "entry must start with the ID of the tactic (TA0002 Execution)"},
+				{Section: SectionTechniques, Index: 0, Entry: "Command and Scripting Interpreter", Message: "entry must start with the ID of the technique (T1059 Command and Scripting Interpreter)"},
+				{Section: SectionSubTechniques, Index: 0, Entry: "Attachment", Message: "entry must start with the ID of the sub-technique (such as T1055.011)"},
+			},
+		},
+		{
+			name:          "Wrong Sections",
+			tactics:       []string{"TA0002"},
+			techniques:    []string{"T1059.004"},
+			subTechniques: []string{"T1059"},
+			expected: []MappingError{
+				{Section: SectionTechniques, Index: 0, Entry: "T1059.004", Message: "T1059.004 is a sub-technique and must be listed under subtechniques"},
+				{Section: SectionSubTechniques, Index: 0, Entry: "T1059", Message: "T1059 is not a sub-technique and must be listed under techniques"},
+			},
+		},
+		{
+			name:       "Technique Outside Listed Tactics",
+			tactics:    []string{"TA0002 Execution"},
+			techniques: []string{"T1059", "T1552"},
+			expected: []MappingError{
+				{Section: SectionTechniques, Index: 1, Entry: "T1552", Message: "technique T1552 does not belong to any of the listed tactics (it belongs to TA0006 Credential Access)"},
+			},
+		},
+		{
+			name:          "Missing Parent Technique",
+			tactics:       []string{"TA0006"},
+			techniques:    []string{"T1555"},
+			subTechniques: []string{"T1555.001", "T1552.001"},
+			expected: []MappingError{
+				{Section: SectionSubTechniques, Index: 1, Entry: "T1552.001", Message: "the parent technique of T1552.001 (T1552 Unsecured Credentials) must be listed under techniques"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			problems := Default().CheckMapping(tc.tactics, tc.techniques, tc.subTechniques)
+			require.Len(t, problems, len(tc.expected))
+			for i, expected := range tc.expected {
+				assert.Equal(t, expected.Section, problems[i].Section)
+				assert.Equal(t, expected.Index, problems[i].Index)
+				assert.Equal(t, expected.Entry, problems[i].Entry)
+				if expected.Message != "" {
+					assert.Equal(t, expected.Message, problems[i].Message)
+				}
+			}
+
+			err := Default().ValidateMapping(tc.tactics, tc.techniques, tc.subTechniques)
+			if len(tc.expected) == 0 {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.expected[0].Entry)
+			}
+		})
+	}
+}
@@ -20,8 +20,12 @@ THE SOFTWARE.
 package outputs
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/tidwall/gjson"
 	"gopkg.in/yaml.v3"
@@ -37,16 +41,16 @@ import (
 //
 // **Returns:**
 //
-// map[string]string: the output keys and values
+// map[string]interface{}: the output keys and their typed values
 // error: an error if there is a problem
-func Parse(specs map[string]Spec, inStr string) (map[string]string, error) {
-	outputs := make(map[string]string)
+func Parse(specs map[string]Spec, inStr string) (map[string]interface{}, error) {
+	outputs := make(map[string]interface{})
 	for name, spec := range specs {
-		outStr, err := spec.Apply(inStr)
+		value, err := spec.Value(inStr)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("failed to extract output %q: %w", name, err)
 		}
-		outputs[name] = outStr
+		outputs[name] = value
 	}
 	return outputs, nil
 }
@@ -55,8 +59,31 @@ func Parse(specs map[string]Spec, inStr string) (map[string]string, error) {
 // a given step's stdout should be scanned
 type Spec struct {
 	Filters []Filter `yaml:"filters"`
+	// Type determines how the filtered string
+	// is converted into the value of the output
+	Type ValueType `yaml:"type,omitempty"`
 }
 
+// ValueType is the type of the value of an output
+type ValueType string
+
+const (
+	// TypeString leaves the filtered string as-is (the default)
+	TypeString ValueType = "string"
+	// TypeInt parses the filtered string as an integer
+	TypeInt ValueType = "int"
+	// TypeFloat parses the filtered string as a floating-point number
+	TypeFloat ValueType = "float"
+	// TypeBool parses the filtered string as a boolean
+	TypeBool ValueType = "bool"
+	// TypeJSON parses the filtered string as a JSON document
+	TypeJSON ValueType = "json"
+	// TypeYAML parses the filtered string as a YAML document
+	TypeYAML ValueType = "yaml"
+	// TypeLines splits the filtered string into a list of its non-empty lines
+	TypeLines ValueType = "lines"
+)
+
 // Filter can be used to extract an output value
 // from the provided string using Apply(...)
 type Filter interface {
@@ -77,16 +104,77 @@ func (s *Spec) Apply(inStr string) (string, error) {
 	return curStr, nil
 }
 
+// Value applies all filters in this output spec to the
+// target string and converts the result to the type of the spec
+func (s *Spec) Value(inStr string) (interface{}, error) {
+	outStr, err := s.Apply(inStr)
+	if err != nil {
+		return nil, err
+	}
+	return s.Type.convert(outStr)
+}
+
+// convert converts a string into a value of this type
+func (t ValueType) convert(inStr string) (interface{}, error) {
+	switch t {
+	case "", TypeString:
+		return inStr, nil
+	case TypeInt:
+		return strconv.Atoi(strings.TrimSpace(inStr))
+	case TypeFloat:
+		return strconv.ParseFloat(strings.TrimSpace(inStr), 64)
+	case TypeBool:
+		return strconv.ParseBool(strings.TrimSpace(inStr))
+	case TypeJSON:
+		var value interface{}
+		if err := json.Unmarshal([]byte(inStr), &value); err != nil {
+			return nil, fmt.Errorf("failed to parse json: %w", err)
+		}
+		return value, nil
+	case TypeYAML:
+		var value interface{}
+		if err := yaml.Unmarshal([]byte(inStr), &value); err != nil {
+			return nil, fmt.Errorf("failed to parse yaml: %w", err)
+		}
+		return jsonCompatible(value), nil
+	case TypeLines:
+		lines := []interface{}{}
+		for _, line := range strings.Split(inStr, "\n") {
+			line = strings.TrimSuffix(line, "\r")
+			if strings.TrimSpace(line) != "" {
+				lines = append(lines, line)
+			}
+		}
+		return lines, nil
+	default:
+		return nil, fmt.Errorf("unknown output type %q", t)
+	}
+}
+
 // JSONFilter will parse a JSON string
 // and extract the value at the provided path (like jq)
 type JSONFilter struct {
 	Path string `yaml:"json_path"`
 }
 
+// filterTypes maps the key that identifies each
+// filter type in YAML to a constructor for that type
+var filterTypes = map[string]func() Filter{
+	"json_path": func() Filter { return &JSONFilter{} },
+	"yaml_path": func() Filter { return &YAMLFilter{} },
+	"xpath":     func() Filter { return &XPathFilter{} },
+	"regex":     func() Filter { return &RegexFilter{} },
+	"line":      func() Filter { return &LineFilter{} },
+	"split":     func() Filter { return &SplitFilter{} },
+	"trim":      func() Filter { return &TrimFilter{} },
+	"kv":        func() Filter { return &KVFilter{} },
+}
+
 // UnmarshalYAML is used to load specs from yaml files
 func (s *Spec) UnmarshalYAML(node *yaml.Node) error {
 	type SpecTmp struct {
 		FilterNodes []yaml.Node `yaml:"filters"`
+		Type        ValueType   `yaml:"type"`
 	}
 
 	var tmp SpecTmp
@@ -96,25 +184,51 @@ func (s *Spec) UnmarshalYAML(node *yaml.Node) error {
 
 	var filters []Filter
 	for _, fn := range tmp.FilterNodes {
-		filterTypes := []Filter{&JSONFilter{}}
-		var alreadyFound bool
-		for _, ft := range filterTypes {
-			if err := fn.Decode(ft); err == nil {
-				if alreadyFound {
-					return errors.New("output spec contains filter with ambiguous type")
-				}
-				filters = append(filters, ft)
-				alreadyFound = true
-			}
+		filter, err := decodeFilter(&fn)
+		if err != nil {
+			return err
 		}
+		filters = append(filters, filter)
 	}
-	if len(filters) == 0 {
+	// an output without filters is only useful
+	// if it converts the entire string to a type
+	if len(filters) == 0 && tmp.Type == "" {
 		return errors.New("no valid filters found in output spec")
 	}
+	if tmp.Type != "" && !slices.Contains(valueTypes, tmp.Type) {
+		return fmt.Errorf("line %d: unknown output type %q", node.Line, tmp.Type)
+	}
 	s.Filters = filters
+	s.Type = tmp.Type
 	return nil
 }
 
+// decodeFilter decodes a single filter, whose
+// type is determined by the keys that it contains
+func decodeFilter(node *yaml.Node) (Filter, error) {
+	if node.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("line %d: output filter must be a mapping", node.Line)
+	}
+	var filter Filter
+	for idx := 0; idx+1 < len(node.Content); idx += 2 {
+		newFilter, ok := filterTypes[node.Content[idx].Value]
+		if !ok {
+			continue
+		}
+		if filter != nil {
+			return nil, errors.New("output spec contains filter with ambiguous type")
+		}
+		filter = newFilter()
+	}
+	if filter == nil {
+		return nil, fmt.Errorf("line %d: unknown output filter type", node.Line)
+	}
+	if err := node.Decode(filter); err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
+
 // Apply applies this filters to the target string
 // and produces a new string
 func (f *JSONFilter) Apply(inStr string) (string, error) {
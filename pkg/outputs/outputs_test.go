@@ -101,3 +101,103 @@ func TestParse(t *testing.T) {
 	assert.Equal(t, "baz", results["first"], "first output should be correct")
 	assert.Equal(t, "b", results["second"], "second output should be correct")
 }
+
+func TestSpecValue(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          string
+		spec           string
+		expectedValue  interface{}
+		wantLoadError  bool
+		wantApplyError bool
+	}{
+		{
+			name:          "Default String",
+			input:         `{"port":22}`,
+			spec:          "filters:\n  - json_path: port",
+			expectedValue: "22",
+		},
+		{
+			name:          "Int",
+			input:         `{"port":22}`,
+			spec:          "filters:\n  - json_path: port\ntype: int",
+			expectedValue: 22,
+		},
+		{
+			name:          "Float",
+			input:         "load 0.75\n",
+			spec:          "filters:\n  - split: \"\"\n    index: 1\ntype: float",
+			expectedValue: 0.75,
+		},
+		{
+			name:          "Bool",
+			input:         "enabled=true",
+			spec:          "filters:\n  - kv: enabled\ntype: bool",
+			expectedValue: true,
+		},
+		{
+			name:  "JSON Without Filters",
+			input: `{"hosts":[{"ip":"10.0.0.1"}]}`,
+			spec:  "type: json",
+			expectedValue: map[string]interface{}{
+				"hosts": []interface{}{map[string]interface{}{"ip": "10.0.0.1"}},
+			},
+		},
+		{
+			name:          "JSON Path Then JSON",
+			input:         `{"pids":[1,2]}`,
+			spec:          "filters:\n  - json_path: pids\ntype: json",
+			expectedValue: []interface{}{float64(1), float64(2)},
+		},
+		{
+			name:          "YAML",
+			input:         "- a\n- b\n",
+			spec:          "type: yaml",
+			expectedValue: []interface{}{"a", "b"},
+		},
+		{
+			name:          "Lines",
+			input:         "101\r\n\n202\n",
+			spec:          "type: lines",
+			expectedValue: []interface{}{"101", "202"},
+		},
+		{
+			name:           "Invalid Int",
+			input:          "abc",
+			spec:           "type: int",
+			wantApplyError: true,
+		},
+		{
+			name:          "Unknown Type",
+			input:         "abc",
+			spec:          "type: uuid",
+			wantLoadError: true,
+		},
+		{
+			name:          "No Filters Or Type",
+			input:         "abc",
+			spec:          "filters: []",
+			wantLoadError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var spec Spec
+			err := yaml.Unmarshal([]byte(tc.spec), &spec)
+			if tc.wantLoadError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			value, err := spec.Value(tc.input)
+			if tc.wantApplyError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedValue, value)
+		})
+	}
+}
@@ -0,0 +1,70 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package outputs
+
+import (
+	"sort"
+
+	"github.com/facebookincubator/ttpforge/pkg/schema"
+)
+
+// valueTypes lists the valid values of the `type:` field of an output
+var valueTypes = []ValueType{TypeString, TypeInt, TypeFloat, TypeBool, TypeJSON, TypeYAML, TypeLines}
+
+// SpecSchema returns the JSON Schema of an output specification,
+// with one alternative for each type of filter
+//
+// **Parameters:**
+//
+// r: the reflector used to describe the fields of each filter type
+//
+// **Returns:**
+//
+// *schema.Schema: the schema of an output specification
+func SpecSchema(r *schema.Reflector) *schema.Schema {
+	keys := make([]string, 0, len(filterTypes))
+	for key := range filterTypes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var filters []*schema.Schema
+	for _, key := range keys {
+		var filter *schema.Schema
+		if _, isTrim := filterTypes[key]().(*TrimFilter); isTrim {
+			// trim is either `true` or a set of characters
+			filter = schema.Object(map[string]*schema.Schema{
+				"trim": schema.AnyOf(&schema.Schema{Type: "boolean"}, &schema.Schema{Type: "string"}),
+			})
+		} else {
+			filter = r.Reflect(filterTypes[key]())
+		}
+		filter.Required = []string{key}
+		filters = append(filters, filter)
+	}
+
+	types := make([]interface{}, len(valueTypes))
+	for idx, t := range valueTypes {
+		types[idx] = string(t)
+	}
+	return schema.Object(map[string]*schema.Schema{
+		"filters": {Type: "array", Items: schema.AnyOf(filters...)},
+		"type":    {Type: "string", Enum: types},
+	})
+}
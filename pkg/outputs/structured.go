@@ -0,0 +1,374 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package outputs
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+	"gopkg.in/yaml.v3"
+)
+
+// YAMLFilter will parse a YAML string and extract the value
+// at the provided path, using the same syntax as JSONFilter
+type YAMLFilter struct {
+	Path string `yaml:"yaml_path"`
+}
+
+// Apply parses the YAML and extracts the value at the path
+func (f *YAMLFilter) Apply(inStr string) (string, error) {
+	var doc interface{}
+	if err := yaml.Unmarshal([]byte(inStr), &doc); err != nil {
+		return "", fmt.Errorf("failed to parse yaml: %w", err)
+	}
+	jsonBytes, err := json.Marshal(jsonCompatible(doc))
+	if err != nil {
+		return "", fmt.Errorf("failed to convert yaml to json: %w", err)
+	}
+	result := gjson.GetBytes(jsonBytes, f.Path)
+	if !result.Exists() {
+		return "", fmt.Errorf("yaml path not found: %v", f.Path)
+	}
+	return result.String(), nil
+}
+
+// jsonCompatible converts the maps with non-string keys
+// that YAML documents may contain into maps with string keys
+func jsonCompatible(val interface{}) interface{} {
+	switch v := val.(type) {
+	case map[string]interface{}:
+		for k, elem := range v {
+			v[k] = jsonCompatible(elem)
+		}
+		return v
+	case map[interface{}]interface{}:
+		converted := make(map[string]interface{}, len(v))
+		for k, elem := range v {
+			converted[fmt.Sprint(k)] = jsonCompatible(elem)
+		}
+		return converted
+	case []interface{}:
+		for idx, elem := range v {
+			v[idx] = jsonCompatible(elem)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// XPathFilter will parse an XML string and extract the text of the
+// first node that matches the provided path. A subset of XPath is
+// supported: child (`/`) and descendant (`//`) steps, `*` wildcards,
+// `[n]` (1-based) and `[@attr]`/`[@attr='value']` predicates, and
+// a final `@attr` or `text()` step.
+type XPathFilter struct {
+	Path string `yaml:"xpath"`
+
+	steps []xpathStep
+}
+
+// xpathStep is a single step of a parsed XPath expression
+type xpathStep struct {
+	descendant bool
+	name       string
+	predicates []xpathPredicate
+}
+
+// xpathPredicate filters the nodes selected by a step either
+// by position (if index is set) or by an attribute
+type xpathPredicate struct {
+	index int
+	attr  string
+	value *string
+}
+
+// xmlNode is an element of a parsed XML document
+type xmlNode struct {
+	name     string
+	attrs    []xml.Attr
+	children []*xmlNode
+	ownText  strings.Builder
+	allText  strings.Builder
+}
+
+// UnmarshalYAML parses the XPath expression
+// so that invalid paths are reported early
+func (f *XPathFilter) UnmarshalYAML(node *yaml.Node) error {
+	type rawXPathFilter XPathFilter
+	if err := node.Decode((*rawXPathFilter)(f)); err != nil {
+		return err
+	}
+	steps, err := parseXPath(f.Path)
+	if err != nil {
+		return err
+	}
+	f.steps = steps
+	return nil
+}
+
+// Apply parses the XML and extracts the text of the first matching node
+func (f *XPathFilter) Apply(inStr string) (string, error) {
+	if f.steps == nil {
+		steps, err := parseXPath(f.Path)
+		if err != nil {
+			return "", err
+		}
+		f.steps = steps
+	}
+	root, err := parseXML(inStr)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse xml: %w", err)
+	}
+
+	nodes := []*xmlNode{root}
+	for idx, step := range f.steps {
+		last := idx == len(f.steps)-1
+		switch {
+		case strings.HasPrefix(step.name, "@"):
+			if !last {
+				return "", fmt.Errorf("xpath %q: attribute must be the last step", f.Path)
+			}
+			for _, n := range nodes {
+				if val, ok := n.attr(step.name[1:]); ok {
+					return val, nil
+				}
+			}
+			return "", fmt.Errorf("xpath not found: %v", f.Path)
+		case step.name == "text()":
+			if !last {
+				return "", fmt.Errorf("xpath %q: text() must be the last step", f.Path)
+			}
+			if len(nodes) == 0 {
+				return "", fmt.Errorf("xpath not found: %v", f.Path)
+			}
+			return nodes[0].ownText.String(), nil
+		}
+		nodes = step.apply(nodes)
+	}
+	if len(nodes) == 0 || nodes[0] == root {
+		return "", fmt.Errorf("xpath not found: %v", f.Path)
+	}
+	return nodes[0].allText.String(), nil
+}
+
+// parseXPath splits an XPath expression into its steps
+func parseXPath(path string) ([]xpathStep, error) {
+	if strings.TrimSpace(path) == "" {
+		return nil, errors.New("xpath must not be empty")
+	}
+	var steps []xpathStep
+	rest := path
+	for rest != "" {
+		var step xpathStep
+		switch {
+		case strings.HasPrefix(rest, "//"):
+			step.descendant = true
+			rest = rest[2:]
+		case strings.HasPrefix(rest, "/"):
+			rest = rest[1:]
+		case len(steps) > 0:
+			return nil, fmt.Errorf("invalid xpath %q", path)
+		}
+
+		end := strings.IndexAny(rest, "/[")
+		if end < 0 {
+			end = len(rest)
+		}
+		step.name = rest[:end]
+		rest = rest[end:]
+		if step.name == "" {
+			return nil, fmt.Errorf("invalid xpath %q: empty step", path)
+		}
+
+		for strings.HasPrefix(rest, "[") {
+			end := closingBracket(rest)
+			if end < 0 {
+				return nil, fmt.Errorf("invalid xpath %q: unterminated predicate", path)
+			}
+			pred, err := parseXPathPredicate(rest[1:end])
+			if err != nil {
+				return nil, fmt.Errorf("invalid xpath %q: %w", path, err)
+			}
+			step.predicates = append(step.predicates, pred)
+			rest = rest[end+1:]
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// closingBracket returns the index of the bracket that closes
+// the predicate at the start of s, ignoring quoted brackets
+func closingBracket(s string) int {
+	var quote rune
+	for idx, r := range s {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == ']':
+			return idx
+		}
+	}
+	return -1
+}
+
+// parseXPathPredicate parses the contents of a [...] predicate
+func parseXPathPredicate(expr string) (xpathPredicate, error) {
+	expr = strings.TrimSpace(expr)
+	if !strings.HasPrefix(expr, "@") {
+		idx, err := strconv.Atoi(expr)
+		if err != nil || idx < 1 {
+			return xpathPredicate{}, fmt.Errorf("unsupported predicate [%s]", expr)
+		}
+		return xpathPredicate{index: idx}, nil
+	}
+	attr, value, found := strings.Cut(expr[1:], "=")
+	pred := xpathPredicate{attr: strings.TrimSpace(attr)}
+	if found {
+		value = strings.TrimSpace(value)
+		if len(value) < 2 || (value[0] != '\'' && value[0] != '"') || value[len(value)-1] != value[0] {
+			return xpathPredicate{}, fmt.Errorf("predicate value must be quoted: [%s]", expr)
+		}
+		value = value[1 : len(value)-1]
+		pred.value = &value
+	}
+	return pred, nil
+}
+
+// apply selects the nodes matched by the step in each of the context nodes
+func (s xpathStep) apply(nodes []*xmlNode) []*xmlNode {
+	var selected []*xmlNode
+	for _, n := range nodes {
+		var candidates []*xmlNode
+		if s.descendant {
+			candidates = n.descendants()
+		} else {
+			candidates = n.children
+		}
+		var matched []*xmlNode
+		for _, c := range candidates {
+			if s.matches(c) {
+				matched = append(matched, c)
+			}
+		}
+		for _, pred := range s.predicates {
+			matched = pred.apply(matched)
+		}
+		selected = append(selected, matched...)
+	}
+	return selected
+}
+
+// matches reports whether the node has the name selected
+// by the step (ignoring any namespace prefix)
+func (s xpathStep) matches(n *xmlNode) bool {
+	if s.name == "*" {
+		return true
+	}
+	name := s.name
+	if idx := strings.LastIndex(name, ":"); idx >= 0 {
+		name = name[idx+1:]
+	}
+	return n.name == name
+}
+
+// apply filters the nodes using the predicate
+func (p xpathPredicate) apply(nodes []*xmlNode) []*xmlNode {
+	if p.index > 0 {
+		if p.index > len(nodes) {
+			return nil
+		}
+		return []*xmlNode{nodes[p.index-1]}
+	}
+	var filtered []*xmlNode
+	for _, n := range nodes {
+		val, ok := n.attr(p.attr)
+		if ok && (p.value == nil || val == *p.value) {
+			filtered = append(filtered, n)
+		}
+	}
+	return filtered
+}
+
+// attr returns the value of the named attribute of the node
+func (n *xmlNode) attr(name string) (string, bool) {
+	for _, a := range n.attrs {
+		if a.Name.Local == name {
+			return a.Value, true
+		}
+	}
+	return "", false
+}
+
+// descendants returns all descendants of the node in document order
+func (n *xmlNode) descendants() []*xmlNode {
+	var nodes []*xmlNode
+	for _, c := range n.children {
+		nodes = append(nodes, c)
+		nodes = append(nodes, c.descendants()...)
+	}
+	return nodes
+}
+
+// parseXML parses an XML document into a tree of
+// nodes under a root node representing the document
+func parseXML(inStr string) (*xmlNode, error) {
+	root := &xmlNode{}
+	stack := []*xmlNode{root}
+	decoder := xml.NewDecoder(strings.NewReader(inStr))
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			n := &xmlNode{name: t.Name.Local, attrs: t.Attr}
+			parent := stack[len(stack)-1]
+			parent.children = append(parent.children, n)
+			stack = append(stack, n)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			stack[len(stack)-1].ownText.Write(t)
+			for _, n := range stack {
+				n.allText.Write(t)
+			}
+		}
+	}
+	if len(root.children) == 0 {
+		return nil, errors.New("document contains no elements")
+	}
+	return root, nil
+}
@@ -0,0 +1,182 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package outputs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+const testXML = `<?xml version="1.0"?>
+<inventory>
+  <host name="web" os="linux">
+    <ip>10.0.0.1</ip>
+    <port proto="tcp">80</port>
+    <port proto="udp">53</port>
+  </host>
+  <host name="db" os="windows">
+    <ip>10.0.0.2</ip>
+  </host>
+</inventory>`
+
+func TestYAMLFilter(t *testing.T) {
+	input := `
+metadata:
+  name: test
+  labels:
+    app: web
+ports:
+  - 80
+  - 443
+1: numeric-key
+`
+	testCases := []struct {
+		name           string
+		path           string
+		result         string
+		wantApplyError bool
+	}{
+		{
+			name:   "Nested Key",
+			path:   "metadata.labels.app",
+			result: "web",
+		},
+		{
+			name:   "List Index",
+			path:   "ports.1",
+			result: "443",
+		},
+		{
+			name:   "Numeric Key",
+			path:   "1",
+			result: "numeric-key",
+		},
+		{
+			name:           "Missing Key",
+			path:           "metadata.missing",
+			wantApplyError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var spec Spec
+			err := yaml.Unmarshal([]byte("filters:\n  - yaml_path: "+tc.path), &spec)
+			require.NoError(t, err)
+
+			result, err := spec.Apply(input)
+			if tc.wantApplyError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tc.result, result)
+		})
+	}
+}
+
+func TestXPathFilter(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          string
+		path           string
+		result         string
+		wantLoadError  bool
+		wantApplyError bool
+	}{
+		{
+			name:   "Absolute Path",
+			input:  testXML,
+			path:   "/inventory/host/ip",
+			result: "10.0.0.1",
+		},
+		{
+			name:   "Descendant With Index",
+			input:  testXML,
+			path:   "//host[2]/ip",
+			result: "10.0.0.2",
+		},
+		{
+			name:   "Attribute Predicate",
+			input:  testXML,
+			path:   "//host[@os='windows']/@name",
+			result: "db",
+		},
+		{
+			name:   "Attribute Existence And Wildcard",
+			input:  testXML,
+			path:   "/inventory/*[@name]/port[@proto=\"udp\"]",
+			result: "53",
+		},
+		{
+			name:   "Text",
+			input:  testXML,
+			path:   "//port/text()",
+			result: "80",
+		},
+		{
+			name:           "No Match",
+			input:          testXML,
+			path:           "//host[3]",
+			wantApplyError: true,
+		},
+		{
+			name:           "Invalid XML",
+			input:          "not xml",
+			path:           "//host",
+			wantApplyError: true,
+		},
+		{
+			name:          "Unterminated Predicate",
+			input:         testXML,
+			path:          "//host[1",
+			wantLoadError: true,
+		},
+		{
+			name:          "Unquoted Predicate Value",
+			input:         testXML,
+			path:          "//host[@os=linux]",
+			wantLoadError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var spec Spec
+			err := yaml.Unmarshal([]byte("filters:\n  - xpath: "+tc.path), &spec)
+			if tc.wantLoadError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			result, err := spec.Apply(tc.input)
+			if tc.wantApplyError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tc.result, result)
+		})
+	}
+}
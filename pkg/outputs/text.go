@@ -0,0 +1,209 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package outputs
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RegexFilter extracts the first match of a regular expression.
+// Group selects the capture group to extract, by number or by name.
+// By default the first capture group is extracted if the expression
+// has one - otherwise the whole match is extracted.
+type RegexFilter struct {
+	Pattern string `yaml:"regex"`
+	Group   string `yaml:"group,omitempty"`
+
+	re         *regexp.Regexp
+	groupIndex int
+}
+
+// UnmarshalYAML compiles the regular expression
+// and checks that the selected group exists
+func (f *RegexFilter) UnmarshalYAML(node *yaml.Node) error {
+	type rawRegexFilter RegexFilter
+	if err := node.Decode((*rawRegexFilter)(f)); err != nil {
+		return err
+	}
+	return f.compile()
+}
+
+// compile compiles the regular expression and
+// resolves the index of the selected group
+func (f *RegexFilter) compile() error {
+	re, err := regexp.Compile(f.Pattern)
+	if err != nil {
+		return fmt.Errorf("invalid regex %q: %w", f.Pattern, err)
+	}
+	f.re = re
+
+	switch {
+	case f.Group == "":
+		f.groupIndex = 0
+		if re.NumSubexp() > 0 {
+			f.groupIndex = 1
+		}
+	case re.SubexpIndex(f.Group) >= 0:
+		f.groupIndex = re.SubexpIndex(f.Group)
+	default:
+		idx, err := strconv.Atoi(f.Group)
+		if err != nil || idx < 0 || idx > re.NumSubexp() {
+			return fmt.Errorf("regex %q has no capture group %q", f.Pattern, f.Group)
+		}
+		f.groupIndex = idx
+	}
+	return nil
+}
+
+// Apply extracts the selected group of the first match
+func (f *RegexFilter) Apply(inStr string) (string, error) {
+	if f.re == nil {
+		if err := f.compile(); err != nil {
+			return "", err
+		}
+	}
+	match := f.re.FindStringSubmatch(inStr)
+	if match == nil {
+		return "", fmt.Errorf("regex %q did not match", f.Pattern)
+	}
+	return match[f.groupIndex], nil
+}
+
+// LineFilter selects a single line by its index, starting
+// from 0 - negative indexes count back from the last line
+type LineFilter struct {
+	Index int `yaml:"line"`
+}
+
+// Apply selects the line
+func (f *LineFilter) Apply(inStr string) (string, error) {
+	lines := strings.Split(strings.TrimSuffix(inStr, "\n"), "\n")
+	line, err := selectIndex(lines, f.Index)
+	if err != nil {
+		return "", fmt.Errorf("line %d not found: %w", f.Index, err)
+	}
+	return strings.TrimSuffix(line, "\r"), nil
+}
+
+// SplitFilter splits the string on a separator and selects one
+// of the resulting fields by its index, starting from 0 - negative
+// indexes count back from the last field. If the separator is empty,
+// the string is split on runs of whitespace.
+type SplitFilter struct {
+	Separator string `yaml:"split"`
+	Index     int    `yaml:"index,omitempty"`
+}
+
+// Apply splits the string and selects the field
+func (f *SplitFilter) Apply(inStr string) (string, error) {
+	var fields []string
+	if f.Separator == "" {
+		fields = strings.Fields(inStr)
+	} else {
+		fields = strings.Split(inStr, f.Separator)
+	}
+	field, err := selectIndex(fields, f.Index)
+	if err != nil {
+		return "", fmt.Errorf("field %d not found: %w", f.Index, err)
+	}
+	return field, nil
+}
+
+// TrimFilter removes leading and trailing characters. `trim: true`
+// removes whitespace, whereas a string value specifies the set
+// of characters to remove.
+type TrimFilter struct {
+	Cutset string
+}
+
+// UnmarshalYAML decodes the `trim` field, which
+// is either `true` or a set of characters
+func (f *TrimFilter) UnmarshalYAML(node *yaml.Node) error {
+	var tmp struct {
+		Trim yaml.Node `yaml:"trim"`
+	}
+	if err := node.Decode(&tmp); err != nil {
+		return err
+	}
+	if tmp.Trim.Tag == "!!bool" {
+		var trimSpace bool
+		if err := tmp.Trim.Decode(&trimSpace); err != nil {
+			return err
+		}
+		if !trimSpace {
+			return fmt.Errorf("line %d: trim must be true or a set of characters", tmp.Trim.Line)
+		}
+		return nil
+	}
+	return tmp.Trim.Decode(&f.Cutset)
+}
+
+// Apply trims the string
+func (f *TrimFilter) Apply(inStr string) (string, error) {
+	if f.Cutset == "" {
+		return strings.TrimSpace(inStr), nil
+	}
+	return strings.Trim(inStr, f.Cutset), nil
+}
+
+// KVFilter parses key/value pairs (such as `key=value` lines)
+// and extracts the value of the specified key. By default, each
+// line holds one pair and keys are separated from values by `=`.
+type KVFilter struct {
+	Key           string `yaml:"kv"`
+	Separator     string `yaml:"separator,omitempty"`
+	PairSeparator string `yaml:"pair_separator,omitempty"`
+}
+
+// Apply extracts the value of the key
+func (f *KVFilter) Apply(inStr string) (string, error) {
+	separator := f.Separator
+	if separator == "" {
+		separator = "="
+	}
+	pairSeparator := f.PairSeparator
+	if pairSeparator == "" {
+		pairSeparator = "\n"
+	}
+	for _, pair := range strings.Split(inStr, pairSeparator) {
+		key, value, found := strings.Cut(pair, separator)
+		if found && strings.TrimSpace(key) == f.Key {
+			return strings.TrimSpace(value), nil
+		}
+	}
+	return "", fmt.Errorf("key %q not found", f.Key)
+}
+
+// selectIndex returns the element at the provided index,
+// where negative indexes count back from the last element
+func selectIndex(elems []string, idx int) (string, error) {
+	if idx < 0 {
+		idx += len(elems)
+	}
+	if idx < 0 || idx >= len(elems) {
+		return "", fmt.Errorf("input has only %d entries", len(elems))
+	}
+	return elems[idx], nil
+}
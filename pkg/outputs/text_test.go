@@ -0,0 +1,216 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package outputs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestTextFilters(t *testing.T) {
+	testCases := []struct {
+		name           string
+		input          string
+		spec           string
+		result         string
+		wantLoadError  bool
+		wantApplyError bool
+	}{
+		{
+			name:  "Regex Defaults To First Group",
+			input: "pid is 1234\n",
+			spec: `filters:
+  - regex: 'pid is (\d+)'`,
+			result: "1234",
+		},
+		{
+			name:  "Regex Without Groups",
+			input: "token=abc123",
+			spec: `filters:
+  - regex: '[a-z]+\d+'`,
+			result: "abc123",
+		},
+		{
+			name:  "Regex Numbered Group",
+			input: "user root uid 0",
+			spec: `filters:
+  - regex: 'user (\w+) uid (\d+)'
+    group: 2`,
+			result: "0",
+		},
+		{
+			name:  "Regex Named Group",
+			input: "user root uid 0",
+			spec: `filters:
+  - regex: 'user (?P<user>\w+)'
+    group: user`,
+			result: "root",
+		},
+		{
+			name:  "Regex Missing Group",
+			input: "user root",
+			spec: `filters:
+  - regex: 'user (\w+)'
+    group: 3`,
+			wantLoadError: true,
+		},
+		{
+			name:  "Invalid Regex",
+			input: "user root",
+			spec: `filters:
+  - regex: 'user (\w+'`,
+			wantLoadError: true,
+		},
+		{
+			name:  "Regex No Match",
+			input: "nothing here",
+			spec: `filters:
+  - regex: '\d+'`,
+			wantApplyError: true,
+		},
+		{
+			name:  "Line Selection",
+			input: "first\r\nsecond\r\nthird\r\n",
+			spec: `filters:
+  - line: 1`,
+			result: "second",
+		},
+		{
+			name:  "Negative Line Selection",
+			input: "first\nsecond\nthird\n",
+			spec: `filters:
+  - line: -1`,
+			result: "third",
+		},
+		{
+			name:  "Line Out Of Range",
+			input: "first\nsecond\n",
+			spec: `filters:
+  - line: 2`,
+			wantApplyError: true,
+		},
+		{
+			name:  "Split On Separator",
+			input: "a,b,c",
+			spec: `filters:
+  - split: ","
+    index: 1`,
+			result: "b",
+		},
+		{
+			name:  "Split On Whitespace",
+			input: "  tcp   0.0.0.0:22   LISTEN ",
+			spec: `filters:
+  - split: ""
+    index: -1`,
+			result: "LISTEN",
+		},
+		{
+			name:  "Trim Whitespace",
+			input: "  value \n",
+			spec: `filters:
+  - trim: true`,
+			result: "value",
+		},
+		{
+			name:  "Trim Cutset",
+			input: `"value"`,
+			spec: `filters:
+  - trim: '"'`,
+			result: "value",
+		},
+		{
+			name:  "Trim False",
+			input: "value",
+			spec: `filters:
+  - trim: false`,
+			wantLoadError: true,
+		},
+		{
+			name:  "Key Value Lines",
+			input: "NAME=Ubuntu\nVERSION_ID = 22.04\n",
+			spec: `filters:
+  - kv: VERSION_ID`,
+			result: "22.04",
+		},
+		{
+			name:  "Key Value Custom Separators",
+			input: "uid:0;gid:1;groups:2",
+			spec: `filters:
+  - kv: gid
+    separator: ":"
+    pair_separator: ";"`,
+			result: "1",
+		},
+		{
+			name:  "Key Not Found",
+			input: "a=b",
+			spec: `filters:
+  - kv: c`,
+			wantApplyError: true,
+		},
+		{
+			name:  "Chained Filters",
+			input: "header\nid=42 \n",
+			spec: `filters:
+  - line: 1
+  - kv: id`,
+			result: "42",
+		},
+		{
+			name:  "Unknown Filter",
+			input: "a",
+			spec: `filters:
+  - nope: 1`,
+			wantLoadError: true,
+		},
+		{
+			name:  "Ambiguous Filter",
+			input: "a",
+			spec: `filters:
+  - line: 1
+    trim: true`,
+			wantLoadError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var spec Spec
+			err := yaml.Unmarshal([]byte(tc.spec), &spec)
+			if tc.wantLoadError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			result, err := spec.Apply(tc.input)
+			if tc.wantApplyError {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tc.result, result)
+		})
+	}
+}
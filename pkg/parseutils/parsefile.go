@@ -22,6 +22,8 @@ package parseutils
 import (
 	"bytes"
 	"fmt"
+	"regexp"
+	"strings"
 
 	"gopkg.in/yaml.v3"
 )
@@ -77,3 +79,35 @@ func ParseTTP(data []byte, filename string) (TTP, error) {
 	}
 	return ttp, nil
 }
+
+// templateLineRegexp matches lines that contain nothing
+// but a template action, such as `{{ if .Args.foo }}`
+var templateLineRegexp = regexp.MustCompile(`^\s*\{\{.*\}\}\s*$`)
+
+// templateActionRegexp matches inline template actions
+var templateActionRegexp = regexp.MustCompile(`\{\{.*?\}\}`)
+
+// StripTemplates makes the contents of a TTP file parseable as YAML
+// without rendering it. Template actions cannot be evaluated without
+// argument values, so lines holding only a template action are blanked
+// and inline actions are replaced with a placeholder - line numbers
+// are preserved.
+//
+// **Parameters:**
+//
+// data: the contents of the TTP file
+//
+// **Returns:**
+//
+// []byte: the contents without template actions
+func StripTemplates(data []byte) []byte {
+	lines := strings.Split(string(data), "\n")
+	for i, line := range lines {
+		if templateLineRegexp.MatchString(line) {
+			lines[i] = ""
+			continue
+		}
+		lines[i] = templateActionRegexp.ReplaceAllString(line, "TEMPLATE")
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
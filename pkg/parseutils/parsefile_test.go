@@ -77,3 +77,17 @@ args:
 		})
 	}
 }
+
+func TestStripTemplates(t *testing.T) {
+	contents := `name: templated
+{{ if .Args.flag }}
+description: "{{ .Args.description }} and {{ .Args.more }}"
+{{ end }}
+steps: []`
+	expected := `name: templated
+
+description: "TEMPLATE and TEMPLATE"
+
+steps: []`
+	require.Equal(t, expected, string(StripTemplates([]byte(contents))))
+}
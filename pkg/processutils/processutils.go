@@ -56,3 +56,18 @@ func VerifyPIDExists(pid int) error {
 	}
 	return fmt.Errorf("No process found with PID: %d", pid)
 }
+
+// ErrProcessNotRunning is returned by GetStartTime when
+// no process with the specified PID is running
+var ErrProcessNotRunning = process.ErrorProcessNotRunning
+
+// GetStartTime returns the time at which the process with the given PID
+// started, in milliseconds since the Unix epoch. Together with the PID,
+// it identifies a process even if its PID is later reused.
+func GetStartTime(pid int) (int64, error) {
+	proc, err := process.NewProcess(int32(pid))
+	if err != nil {
+		return 0, err
+	}
+	return proc.CreateTime()
+}
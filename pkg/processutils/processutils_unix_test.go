@@ -25,6 +25,7 @@ package processutils
 import (
 	"os"
 	"testing"
+	"time"
 
 	"github.com/facebookincubator/ttpforge/pkg/testutils"
 	"github.com/stretchr/testify/assert"
@@ -127,3 +128,18 @@ func TestVerifyPIDExists(t *testing.T) {
 		})
 	}
 }
+
+func TestGetStartTime(t *testing.T) {
+	startTime, err := GetStartTime(os.Getpid())
+	require.NoError(t, err)
+	assert.Positive(t, startTime)
+	assert.LessOrEqual(t, startTime, time.Now().UnixMilli())
+
+	// the start time identifies the process, so it does not change
+	again, err := GetStartTime(os.Getpid())
+	require.NoError(t, err)
+	assert.Equal(t, startTime, again)
+
+	_, err = GetStartTime(2147483647)
+	assert.ErrorIs(t, err, ErrProcessNotRunning)
+}
@@ -0,0 +1,147 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/facebookincubator/ttpforge/pkg/blocks"
+)
+
+// The JUnit XML format has no formal specification - these
+// types follow the de-facto schema understood by common CI systems
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name       string          `xml:"name,attr"`
+	Tests      int             `xml:"tests,attr"`
+	Failures   int             `xml:"failures,attr"`
+	Skipped    int             `xml:"skipped,attr"`
+	Time       string          `xml:"time,attr"`
+	Timestamp  string          `xml:"timestamp,attr"`
+	Properties []junitProperty `xml:"properties>property,omitempty"`
+	TestCases  []junitTestCase `xml:"testcase"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+	SystemErr string        `xml:"system-err,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+func junitSeconds(ms int64) string {
+	return fmt.Sprintf("%.3f", float64(ms)/1000)
+}
+
+func (r *Report) writeJUnit(w io.Writer) error {
+	suiteName := r.TTP.Name
+	if suiteName == "" {
+		suiteName = r.TTP.Ref
+	}
+	suite := junitTestSuite{
+		Name:      suiteName,
+		Tests:     len(r.Steps),
+		Time:      junitSeconds(r.DurationMs),
+		Timestamp: r.StartTime.Format(time.RFC3339),
+		Properties: []junitProperty{
+			{Name: "ref", Value: r.TTP.Ref},
+			{Name: "uuid", Value: r.TTP.UUID},
+			{Name: "schema_version", Value: r.SchemaVersion},
+		},
+	}
+	for _, step := range r.Steps {
+		tc := junitTestCase{
+			Name:      step.Name,
+			ClassName: suiteName + "." + step.ActionType,
+			Time:      junitSeconds(step.DurationMs),
+			SystemOut: step.Stdout,
+			SystemErr: step.Stderr,
+		}
+		switch blocks.StepStatus(step.Status) {
+		case blocks.StepStatusSucceeded:
+		case blocks.StepStatusSkipped:
+			tc.Skipped = &junitSkipped{Message: "condition evaluated to false"}
+			suite.Skipped++
+		default:
+			tc.Failure = &junitFailure{
+				Message: step.Error,
+				Type:    step.Status,
+				Body:    step.Stderr,
+			}
+			suite.Failures++
+		}
+		if step.Cleanup != nil && step.Cleanup.Error != "" && tc.Failure == nil {
+			tc.Failure = &junitFailure{
+				Message: "cleanup failed: " + step.Cleanup.Error,
+				Type:    "cleanup_failed",
+				Body:    step.Cleanup.Stderr,
+			}
+			suite.Failures++
+		}
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+
+	suites := junitTestSuites{
+		Name:     "ttpforge",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Time:     suite.Time,
+		Suites:   []junitTestSuite{suite},
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(suites); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
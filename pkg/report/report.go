@@ -0,0 +1,334 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/facebookincubator/ttpforge/pkg/blocks"
+	"github.com/facebookincubator/ttpforge/pkg/logging"
+)
+
+// SchemaVersion is the version of the report format produced
+// by this package. It must be incremented whenever a field
+// is removed or changes meaning so that consumers can detect
+// incompatible reports.
+const SchemaVersion = "1"
+
+// Supported report formats
+const (
+	FormatJSON  = "json"
+	FormatJUnit = "junit"
+	FormatSARIF = "sarif"
+)
+
+// Formats lists every value accepted by Write
+var Formats = []string{FormatJSON, FormatJUnit, FormatSARIF}
+
+// Run contains everything needed to generate
+// a report for a single `ttpforge run` invocation
+type Run struct {
+	TTPRef    string
+	TTP       *blocks.TTP
+	Results   *blocks.StepResultsRecord
+	StartTime time.Time
+	EndTime   time.Time
+	Err       error
+}
+
+// Report is the machine-readable description of a TTP execution.
+// The JSON field names form a stable schema - see SchemaVersion.
+type Report struct {
+	SchemaVersion string       `json:"schema_version"`
+	TTP           TTPInfo      `json:"ttp"`
+	Status        string       `json:"status"`
+	Error         string       `json:"error,omitempty"`
+	StartTime     time.Time    `json:"start_time"`
+	EndTime       time.Time    `json:"end_time"`
+	DurationMs    int64        `json:"duration_ms"`
+	Steps         []StepReport `json:"steps"`
+}
+
+// TTPInfo identifies the TTP that was executed
+type TTPInfo struct {
+	Ref         string `json:"ref"`
+	Name        string `json:"name"`
+	UUID        string `json:"uuid,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// StepReport describes the execution of a single step
+type StepReport struct {
+	Index      int                    `json:"index"`
+	Name       string                 `json:"name"`
+	ActionType string                 `json:"action_type"`
+	Status     string                 `json:"status"`
+	StartTime  time.Time              `json:"start_time"`
+	EndTime    time.Time              `json:"end_time"`
+	DurationMs int64                  `json:"duration_ms"`
+	ExitCode   int                    `json:"exit_code"`
+	Error      string                 `json:"error,omitempty"`
+	Stdout     string                 `json:"stdout"`
+	Stderr     string                 `json:"stderr"`
+	Outputs    map[string]interface{} `json:"outputs,omitempty"`
+	Checks     []CheckReport          `json:"checks,omitempty"`
+	Cleanup    *CleanupReport         `json:"cleanup,omitempty"`
+	Attempts   []AttemptReport        `json:"attempts,omitempty"`
+	Children   []StepReport           `json:"children,omitempty"`
+}
+
+// AttemptReport describes a single attempt at executing a step.
+// Steps with a retry policy may be attempted more than once.
+type AttemptReport struct {
+	Number    int       `json:"number"`
+	Status    string    `json:"status"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	ExitCode  int       `json:"exit_code"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// CheckReport describes the outcome of a single success check
+type CheckReport struct {
+	Msg    string `json:"msg"`
+	Passed bool   `json:"passed"`
+	Error  string `json:"error,omitempty"`
+}
+
+// CleanupReport describes the outcome of a step's cleanup action
+type CleanupReport struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	Stdout string `json:"stdout"`
+	Stderr string `json:"stderr"`
+}
+
+// New assembles a Report from the results of a TTP execution
+//
+// **Parameters:**
+//
+// run: the TTP that was executed and the results of that execution
+//
+// **Returns:**
+//
+// *Report: the assembled report
+func New(run Run) *Report {
+	r := &Report{
+		SchemaVersion: SchemaVersion,
+		TTP: TTPInfo{
+			Ref: run.TTPRef,
+		},
+		Status:     string(blocks.StepStatusSucceeded),
+		StartTime:  run.StartTime,
+		EndTime:    run.EndTime,
+		DurationMs: run.EndTime.Sub(run.StartTime).Milliseconds(),
+		Steps:      []StepReport{},
+	}
+	if run.TTP != nil {
+		r.TTP.Name = run.TTP.Name
+		r.TTP.UUID = run.TTP.UUID
+		r.TTP.Description = run.TTP.Description
+	}
+	if run.Err != nil {
+		r.Status = string(blocks.StepStatusFailed)
+		r.Error = run.Err.Error()
+	}
+	if run.Results == nil {
+		return r
+	}
+
+	for idx, result := range run.Results.ByIndex {
+		r.Steps = append(r.Steps, newStepReport(idx, result))
+	}
+	r.redact()
+	return r
+}
+
+// redact masks the values of secret arguments
+// wherever they appear in the report
+func (r *Report) redact() {
+	r.Error = logging.Redact(r.Error)
+	for idx := range r.Steps {
+		r.Steps[idx].redact()
+	}
+}
+
+// redact masks the values of secret arguments
+// wherever they appear in the step report
+func (s *StepReport) redact() {
+	s.Error = logging.Redact(s.Error)
+	s.Stdout = logging.Redact(s.Stdout)
+	s.Stderr = logging.Redact(s.Stderr)
+	if s.Outputs != nil {
+		s.Outputs = redactValue(s.Outputs).(map[string]interface{})
+	}
+	for idx := range s.Checks {
+		s.Checks[idx].Msg = logging.Redact(s.Checks[idx].Msg)
+		s.Checks[idx].Error = logging.Redact(s.Checks[idx].Error)
+	}
+	if s.Cleanup != nil {
+		s.Cleanup.Error = logging.Redact(s.Cleanup.Error)
+		s.Cleanup.Stdout = logging.Redact(s.Cleanup.Stdout)
+		s.Cleanup.Stderr = logging.Redact(s.Cleanup.Stderr)
+	}
+	for idx := range s.Attempts {
+		s.Attempts[idx].Error = logging.Redact(s.Attempts[idx].Error)
+	}
+	for idx := range s.Children {
+		s.Children[idx].redact()
+	}
+}
+
+// redactValue returns a copy of a (possibly nested)
+// output value with the values of secret arguments masked
+func redactValue(val interface{}) interface{} {
+	switch v := val.(type) {
+	case string:
+		return logging.Redact(v)
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+		for k, elem := range v {
+			redacted[k] = redactValue(elem)
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for idx, elem := range v {
+			redacted[idx] = redactValue(elem)
+		}
+		return redacted
+	default:
+		return v
+	}
+}
+
+func newStepReport(idx int, result *blocks.ExecutionResult) StepReport {
+	step := StepReport{
+		Index:      idx,
+		Name:       result.Name,
+		ActionType: result.ActionType,
+		Status:     string(result.Status),
+		StartTime:  result.StartTime,
+		EndTime:    result.EndTime,
+		DurationMs: result.EndTime.Sub(result.StartTime).Milliseconds(),
+		ExitCode:   result.ExitCode,
+		Error:      result.Error,
+		Stdout:     result.Stdout,
+		Stderr:     result.Stderr,
+		Outputs:    result.Outputs,
+	}
+	for _, check := range result.Checks {
+		step.Checks = append(step.Checks, CheckReport{
+			Msg:    check.Msg,
+			Passed: check.Passed,
+			Error:  check.Error,
+		})
+	}
+	if result.Cleanup != nil || result.CleanupError != "" {
+		cleanup := &CleanupReport{
+			Status: string(blocks.StepStatusSucceeded),
+			Error:  result.CleanupError,
+		}
+		if result.CleanupError != "" {
+			cleanup.Status = string(blocks.StepStatusFailed)
+		}
+		if result.Cleanup != nil {
+			cleanup.Stdout = result.Cleanup.Stdout
+			cleanup.Stderr = result.Cleanup.Stderr
+		}
+		step.Cleanup = cleanup
+	}
+	for _, attempt := range result.Attempts {
+		step.Attempts = append(step.Attempts, AttemptReport{
+			Number:    attempt.Number,
+			Status:    string(attempt.Status),
+			StartTime: attempt.StartTime,
+			EndTime:   attempt.EndTime,
+			ExitCode:  attempt.ExitCode,
+			Error:     attempt.Error,
+		})
+	}
+	for childIdx, child := range result.Children {
+		step.Children = append(step.Children, newStepReport(childIdx, child))
+	}
+	return step
+}
+
+// ValidateFormat returns an error if the
+// provided report format is not supported
+func ValidateFormat(format string) error {
+	for _, f := range Formats {
+		if f == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported report format %q - valid formats are %v", format, Formats)
+}
+
+// Write serializes the report in the requested format
+//
+// **Parameters:**
+//
+// w: the writer to which the report will be written
+// format: one of the values in Formats
+//
+// **Returns:**
+//
+// error: an error if the format is unknown or serialization fails
+func (r *Report) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatJSON, "":
+		return r.writeJSON(w)
+	case FormatJUnit:
+		return r.writeJUnit(w)
+	case FormatSARIF:
+		return r.writeSARIF(w)
+	default:
+		return ValidateFormat(format)
+	}
+}
+
+// WriteFile serializes the report in the requested
+// format to the file at the specified path
+func (r *Report) WriteFile(path string, format string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create report file %v: %w", path, err)
+	}
+	if err := r.Write(f, format); err != nil {
+		f.Close()
+		return err
+	}
+	// a failed close may mean that the report was not fully written
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write report file %v: %w", path, err)
+	}
+	return nil
+}
+
+func (r *Report) writeJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
@@ -0,0 +1,215 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/facebookincubator/ttpforge/pkg/blocks"
+	"github.com/facebookincubator/ttpforge/pkg/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRun(runErr error) Run {
+	start := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	results := blocks.NewStepResultsRecord()
+	first := &blocks.ExecutionResult{
+		ActResult: blocks.ActResult{
+			Stdout:  "hello\n",
+			Outputs: map[string]interface{}{"foo": "bar"},
+		},
+		Name:       "first",
+		ActionType: "inline",
+		Status:     blocks.StepStatusSucceeded,
+		StartTime:  start,
+		EndTime:    start.Add(1500 * time.Millisecond),
+		Checks: []blocks.CheckResult{
+			{Msg: "file exists", Passed: true},
+		},
+		Cleanup: &blocks.ActResult{Stdout: "cleaned\n"},
+	}
+	second := &blocks.ExecutionResult{
+		ActResult: blocks.ActResult{
+			Stderr: "boom\n",
+		},
+		Name:       "second",
+		ActionType: "file",
+		Status:     blocks.StepStatusFailed,
+		StartTime:  start.Add(1500 * time.Millisecond),
+		EndTime:    start.Add(2 * time.Second),
+		ExitCode:   3,
+		Error:      "exit status 3",
+	}
+	for _, r := range []*blocks.ExecutionResult{first, second} {
+		results.ByName[r.Name] = r
+		results.ByIndex = append(results.ByIndex, r)
+	}
+	return Run{
+		TTPRef:    "repo//test.yaml",
+		TTP:       &blocks.TTP{PreambleFields: blocks.PreambleFields{Name: "test ttp"}},
+		Results:   results,
+		StartTime: start,
+		EndTime:   start.Add(3 * time.Second),
+		Err:       runErr,
+	}
+}
+
+func TestNew(t *testing.T) {
+	r := New(testRun(errors.New("step failed")))
+
+	assert.Equal(t, SchemaVersion, r.SchemaVersion)
+	assert.Equal(t, "repo//test.yaml", r.TTP.Ref)
+	assert.Equal(t, "test ttp", r.TTP.Name)
+	assert.Equal(t, "failed", r.Status)
+	assert.Equal(t, "step failed", r.Error)
+	assert.Equal(t, int64(3000), r.DurationMs)
+	require.Len(t, r.Steps, 2)
+
+	first := r.Steps[0]
+	assert.Equal(t, 0, first.Index)
+	assert.Equal(t, "inline", first.ActionType)
+	assert.Equal(t, "succeeded", first.Status)
+	assert.Equal(t, int64(1500), first.DurationMs)
+	assert.Equal(t, map[string]interface{}{"foo": "bar"}, first.Outputs)
+	require.Len(t, first.Checks, 1)
+	assert.True(t, first.Checks[0].Passed)
+	require.NotNil(t, first.Cleanup)
+	assert.Equal(t, "succeeded", first.Cleanup.Status)
+	assert.Equal(t, "cleaned\n", first.Cleanup.Stdout)
+
+	second := r.Steps[1]
+	assert.Equal(t, "failed", second.Status)
+	assert.Equal(t, 3, second.ExitCode)
+	assert.Equal(t, "exit status 3", second.Error)
+	assert.Nil(t, second.Cleanup)
+}
+
+func TestNewRedactsSecrets(t *testing.T) {
+	logging.RegisterSecret("bar")
+	logging.RegisterSecret("boom")
+	t.Cleanup(logging.ClearSecrets)
+
+	run := testRun(errors.New("step failed: boom"))
+	r := New(run)
+
+	assert.Equal(t, "step failed: "+logging.RedactedPlaceholder, r.Error)
+	require.Len(t, r.Steps, 2)
+	assert.Equal(t, map[string]interface{}{"foo": logging.RedactedPlaceholder}, r.Steps[0].Outputs)
+	assert.Equal(t, logging.RedactedPlaceholder+"\n", r.Steps[1].Stderr)
+	// the results themselves must not be modified
+	assert.Equal(t, "bar", run.Results.ByIndex[0].Outputs["foo"])
+}
+
+func TestWrite(t *testing.T) {
+	testCases := []struct {
+		name      string
+		format    string
+		check     func(t *testing.T, out []byte)
+		wantError bool
+	}{
+		{
+			name:   "json",
+			format: FormatJSON,
+			check: func(t *testing.T, out []byte) {
+				var decoded map[string]interface{}
+				require.NoError(t, json.Unmarshal(out, &decoded))
+				assert.Equal(t, SchemaVersion, decoded["schema_version"])
+				steps, ok := decoded["steps"].([]interface{})
+				require.True(t, ok)
+				assert.Len(t, steps, 2)
+			},
+		},
+		{
+			name:   "junit",
+			format: FormatJUnit,
+			check: func(t *testing.T, out []byte) {
+				var decoded junitTestSuites
+				require.NoError(t, xml.Unmarshal(out, &decoded))
+				require.Len(t, decoded.Suites, 1)
+				suite := decoded.Suites[0]
+				assert.Equal(t, "test ttp", suite.Name)
+				assert.Equal(t, 2, suite.Tests)
+				assert.Equal(t, 1, suite.Failures)
+				require.Len(t, suite.TestCases, 2)
+				assert.Nil(t, suite.TestCases[0].Failure)
+				require.NotNil(t, suite.TestCases[1].Failure)
+				assert.Equal(t, "exit status 3", suite.TestCases[1].Failure.Message)
+			},
+		},
+		{
+			name:   "sarif",
+			format: FormatSARIF,
+			check: func(t *testing.T, out []byte) {
+				var decoded sarifLog
+				require.NoError(t, json.Unmarshal(out, &decoded))
+				assert.Equal(t, sarifVersion, decoded.Version)
+				require.Len(t, decoded.Runs, 1)
+				run := decoded.Runs[0]
+				assert.Len(t, run.Tool.Driver.Rules, 2)
+				require.Len(t, run.Results, 2)
+				assert.Equal(t, "note", run.Results[0].Level)
+				assert.Equal(t, "error", run.Results[1].Level)
+				assert.False(t, run.Invocations[0].ExecutionSuccessful)
+			},
+		},
+		{
+			name:      "invalid",
+			format:    "yaml",
+			wantError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := New(testRun(errors.New("step failed"))).Write(&buf, tc.format)
+			if tc.wantError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			tc.check(t, buf.Bytes())
+		})
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	r := New(testRun(nil))
+
+	path := filepath.Join(dir, "report.json")
+	require.NoError(t, r.WriteFile(path, FormatJSON))
+	out, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(out, &decoded))
+	assert.Equal(t, SchemaVersion, decoded["schema_version"])
+
+	assert.Error(t, r.WriteFile(filepath.Join(dir, "missing", "report.json"), FormatJSON))
+	assert.Error(t, r.WriteFile(filepath.Join(dir, "report.yaml"), "yaml"))
+}
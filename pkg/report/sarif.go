@@ -0,0 +1,185 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/facebookincubator/ttpforge/pkg/blocks"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// Minimal subset of the SARIF 2.1.0 object model - see
+// https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool        sarifTool              `json:"tool"`
+	Invocations []sarifInvocation      `json:"invocations"`
+	Results     []sarifResult          `json:"results"`
+	Properties  map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifInvocation struct {
+	ExecutionSuccessful bool   `json:"executionSuccessful"`
+	StartTimeUTC        string `json:"startTimeUtc"`
+	EndTimeUTC          string `json:"endTimeUtc"`
+}
+
+type sarifResult struct {
+	RuleID     string                 `json:"ruleId"`
+	Level      string                 `json:"level"`
+	Kind       string                 `json:"kind"`
+	Message    sarifMessage           `json:"message"`
+	Locations  []sarifLocation        `json:"locations,omitempty"`
+	Properties map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	Name               string `json:"name"`
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+func (r *Report) writeSARIF(w io.Writer) error {
+	const timeFormat = "2006-01-02T15:04:05.000Z"
+
+	// one rule per action type used in this TTP
+	ruleSet := make(map[string]bool)
+	for _, step := range r.Steps {
+		ruleSet[step.ActionType] = true
+	}
+	var ruleIDs []string
+	for id := range ruleSet {
+		ruleIDs = append(ruleIDs, id)
+	}
+	sort.Strings(ruleIDs)
+	rules := []sarifRule{}
+	for _, id := range ruleIDs {
+		rules = append(rules, sarifRule{
+			ID:               id,
+			ShortDescription: sarifMessage{Text: fmt.Sprintf("TTPForge %v action", id)},
+		})
+	}
+
+	results := []sarifResult{}
+	for _, step := range r.Steps {
+		res := sarifResult{
+			RuleID: step.ActionType,
+			Level:  "note",
+			Kind:   "pass",
+			Message: sarifMessage{
+				Text: fmt.Sprintf("step %q %v", step.Name, step.Status),
+			},
+			Locations: []sarifLocation{{
+				LogicalLocations: []sarifLogicalLocation{{
+					Name:               step.Name,
+					FullyQualifiedName: r.TTP.Ref + "#" + step.Name,
+					Kind:               "function",
+				}},
+			}},
+			Properties: map[string]interface{}{
+				"index":       step.Index,
+				"status":      step.Status,
+				"exit_code":   step.ExitCode,
+				"duration_ms": step.DurationMs,
+				"attempts":    len(step.Attempts),
+			},
+		}
+		switch blocks.StepStatus(step.Status) {
+		case blocks.StepStatusSucceeded:
+		case blocks.StepStatusSkipped:
+			res.Level = "none"
+			res.Kind = "notApplicable"
+		default:
+			res.Level = "error"
+			res.Kind = "fail"
+			if step.Error != "" {
+				res.Message.Text += ": " + step.Error
+			}
+		}
+		if step.Cleanup != nil {
+			res.Properties["cleanup_status"] = step.Cleanup.Status
+		}
+		results = append(results, res)
+	}
+
+	log := sarifLog{
+		Version: sarifVersion,
+		Schema:  sarifSchema,
+		Runs: []sarifRun{{
+			Tool: sarifTool{
+				Driver: sarifDriver{
+					Name:           "TTPForge",
+					InformationURI: "https://github.com/facebookincubator/TTPForge",
+					Rules:          rules,
+				},
+			},
+			Invocations: []sarifInvocation{{
+				ExecutionSuccessful: r.Status == string(blocks.StepStatusSucceeded),
+				StartTimeUTC:        r.StartTime.UTC().Format(timeFormat),
+				EndTimeUTC:          r.EndTime.UTC().Format(timeFormat),
+			}},
+			Results: results,
+			Properties: map[string]interface{}{
+				"ttp_ref":  r.TTP.Ref,
+				"ttp_name": r.TTP.Name,
+				"ttp_uuid": r.TTP.UUID,
+			},
+		}},
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(log)
+}
@@ -0,0 +1,195 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package schema
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Draft is the JSON Schema dialect of the generated schemas
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema. Only the keywords needed to
+// describe the TTP format are supported.
+type Schema struct {
+	Schema      string             `json:"$schema,omitempty"`
+	ID          string             `json:"$id,omitempty"`
+	Ref         string             `json:"$ref,omitempty"`
+	Title       string             `json:"title,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Type        string             `json:"type,omitempty"`
+	Enum        []interface{}      `json:"enum,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	// AdditionalProperties is either false (no other
+	// keys are allowed) or the *Schema of their values
+	AdditionalProperties interface{}        `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// Any returns a schema that accepts any value
+func Any() *Schema {
+	return &Schema{}
+}
+
+// Object returns a schema for a mapping with the specified
+// properties that does not allow any other keys
+//
+// **Parameters:**
+//
+// properties: the schema of each allowed key
+// required: the keys that must be present
+//
+// **Returns:**
+//
+// *Schema: the object schema
+func Object(properties map[string]*Schema, required ...string) *Schema {
+	return &Schema{
+		Type:                 "object",
+		Properties:           properties,
+		Required:             required,
+		AdditionalProperties: false,
+	}
+}
+
+// AnyOf returns a schema that accepts values
+// matching any of the specified schemas
+func AnyOf(alternatives ...*Schema) *Schema {
+	return &Schema{AnyOf: alternatives}
+}
+
+// Ref returns a reference to a definition in the `$defs` of the root schema
+func Ref(name string) *Schema {
+	return &Schema{Ref: "#/$defs/" + name}
+}
+
+// Merge returns an object schema with the properties
+// and required keys of all of the specified object schemas
+func Merge(objects ...*Schema) *Schema {
+	merged := Object(map[string]*Schema{})
+	for _, obj := range objects {
+		for key, prop := range obj.Properties {
+			merged.Properties[key] = prop
+		}
+		merged.Required = append(merged.Required, obj.Required...)
+	}
+	return merged
+}
+
+// Reflector generates schemas from Go types
+// using the same field names as gopkg.in/yaml.v3
+type Reflector struct {
+	// Overrides provides the schemas of types whose YAML
+	// representation does not follow from their fields,
+	// such as types with a custom UnmarshalYAML method
+	Overrides map[reflect.Type]*Schema
+
+	// inProgress holds the struct types being described,
+	// so that recursive types do not recurse forever
+	inProgress map[reflect.Type]bool
+}
+
+// Reflect generates the schema of the type of the provided value
+//
+// **Parameters:**
+//
+// v: a value (or nil pointer) of the type to describe
+//
+// **Returns:**
+//
+// *Schema: the schema of the type
+func (r *Reflector) Reflect(v interface{}) *Schema {
+	return r.reflectType(reflect.TypeOf(v))
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+func (r *Reflector) reflectType(t reflect.Type) *Schema {
+	if s, ok := r.Overrides[t]; ok {
+		return s
+	}
+	if t.Kind() == reflect.Ptr {
+		return r.reflectType(t.Elem())
+	}
+	if t == durationType {
+		return AnyOf(&Schema{Type: "string"}, &Schema{Type: "integer"})
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: r.reflectType(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.reflectType(t.Elem())}
+	case reflect.Struct:
+		if r.inProgress[t] {
+			return Any()
+		}
+		if r.inProgress == nil {
+			r.inProgress = make(map[reflect.Type]bool)
+		}
+		r.inProgress[t] = true
+		defer delete(r.inProgress, t)
+		obj := Object(map[string]*Schema{})
+		r.addFields(obj, t)
+		return obj
+	default:
+		return Any()
+	}
+}
+
+// addFields adds the properties for the fields of a struct
+// type to obj, flattening fields with the `inline` flag
+func (r *Reflector) addFields(obj *Schema, t reflect.Type) {
+	for idx := 0; idx < t.NumField(); idx++ {
+		field := t.Field(idx)
+		tag := field.Tag.Get("yaml")
+		name, flags, _ := strings.Cut(tag, ",")
+		if name == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+		if strings.Contains(flags, "inline") {
+			fieldType := field.Type
+			if fieldType.Kind() == reflect.Ptr {
+				fieldType = fieldType.Elem()
+			}
+			r.addFields(obj, fieldType)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		obj.Properties[name] = r.reflectType(field.Type)
+	}
+}
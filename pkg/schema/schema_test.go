@@ -0,0 +1,160 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package schema
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+type embedded struct {
+	Shared string `yaml:"shared"`
+}
+
+type custom struct{}
+
+type node struct {
+	embedded `yaml:",inline"`
+	Name     string            `yaml:"name"`
+	Count    int               `yaml:"count,omitempty"`
+	Ratio    float64           `yaml:"ratio"`
+	Enabled  bool              `yaml:"enabled"`
+	Tags     []string          `yaml:"tags"`
+	Labels   map[string]string `yaml:"labels"`
+	Custom   custom            `yaml:"custom"`
+	Child    *node             `yaml:"child"`
+	Untagged string
+	Ignored  string `yaml:"-"`
+	hidden   string
+}
+
+func TestReflect(t *testing.T) {
+	r := &Reflector{Overrides: map[reflect.Type]*Schema{
+		reflect.TypeOf(custom{}): {Type: "string"},
+	}}
+	s := r.Reflect(node{})
+
+	assert.Equal(t, "object", s.Type)
+	assert.Equal(t, false, s.AdditionalProperties)
+	var keys []string
+	for key := range s.Properties {
+		keys = append(keys, key)
+	}
+	assert.ElementsMatch(t, []string{"shared", "name", "count", "ratio", "enabled", "tags", "labels", "custom", "child", "untagged"}, keys)
+	assert.Equal(t, "integer", s.Properties["count"].Type)
+	assert.Equal(t, "number", s.Properties["ratio"].Type)
+	assert.Equal(t, "boolean", s.Properties["enabled"].Type)
+	assert.Equal(t, "string", s.Properties["tags"].Items.Type)
+	assert.Equal(t, &Schema{Type: "string"}, s.Properties["labels"].AdditionalProperties)
+	assert.Equal(t, "string", s.Properties["custom"].Type)
+	// recursive references are not expanded
+	assert.Equal(t, Any(), s.Properties["child"])
+}
+
+func TestUnknownKeys(t *testing.T) {
+	root := Object(map[string]*Schema{
+		"name":  {Type: "string"},
+		"items": {Type: "array", Items: Ref("item")},
+		"env":   {Type: "object", AdditionalProperties: &Schema{Type: "string"}},
+	})
+	root.Defs = map[string]*Schema{
+		"item": AnyOf(
+			Object(map[string]*Schema{"name": {Type: "string"}, "file": {Type: "string"}, "args": {Type: "array"}}, "file"),
+			Object(map[string]*Schema{"name": {Type: "string"}, "inline": {Type: "string"}, "outputs": Object(map[string]*Schema{"type": {Type: "string"}})}, "inline"),
+		),
+	}
+
+	testCases := []struct {
+		name     string
+		content  string
+		expected []string
+	}{
+		{
+			name: "valid",
+			content: `name: foo
+env:
+  ANYTHING: goes
+items:
+  - name: a
+    file: a.sh
+    args: [x]
+  - name: b
+    inline: echo b
+    outputs:
+      type: int
+`,
+		},
+		{
+			name: "typos",
+			content: `name: foo
+nmae: bar
+items:
+  - name: a
+    inline: echo a
+    outputs:
+      typ: int
+  - name: b
+    file: b.sh
+    inline_: echo
+`,
+			expected: []string{
+				`line 2, column 1: unknown key "nmae" (did you mean "name"?)`,
+				`line 7, column 7: unknown key "typ" (did you mean "type"?)`,
+				`line 10, column 5: unknown key "inline_" (allowed keys: args, file, name)`,
+			},
+		},
+		{
+			name: "key-of-other-alternative",
+			content: `items:
+  - name: a
+    file: a.sh
+    outputs: {}
+`,
+			expected: []string{
+				`line 4, column 5: unknown key "outputs" (allowed keys: args, file, name)`,
+			},
+		},
+		{
+			name: "no-discriminator",
+			content: `items:
+  - name: a
+    zzz: b
+`,
+			expected: []string{
+				`line 3, column 5: unknown key "zzz" (allowed keys: args, file, inline, name, outputs)`,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var doc yaml.Node
+			require.NoError(t, yaml.Unmarshal([]byte(tc.content), &doc))
+			var errs []string
+			for _, err := range UnknownKeys(&doc, root) {
+				errs = append(errs, err.Error())
+			}
+			assert.Equal(t, tc.expected, errs)
+		})
+	}
+}
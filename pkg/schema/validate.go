@@ -0,0 +1,274 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package schema
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// UnknownKeyError reports a key that the schema does not allow
+type UnknownKeyError struct {
+	Key    string
+	Line   int
+	Column int
+	// Allowed lists the keys that are allowed at this position
+	Allowed []string
+}
+
+// Error implements the error interface
+func (e *UnknownKeyError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %v", e.Line, e.Column, e.Message())
+}
+
+// Message describes the error without its position, suggesting
+// the allowed key closest to the unknown key if it is likely a typo
+func (e *UnknownKeyError) Message() string {
+	if suggestion := closest(e.Key, e.Allowed); suggestion != "" {
+		return fmt.Sprintf("unknown key %q (did you mean %q?)", e.Key, suggestion)
+	}
+	return fmt.Sprintf("unknown key %q (allowed keys: %v)", e.Key, strings.Join(e.Allowed, ", "))
+}
+
+// closest returns the candidate with the smallest edit distance
+// to key, or "" if no candidate is close enough to be a typo
+func closest(key string, candidates []string) string {
+	best, bestDist := "", len(key)/3+1
+	for _, candidate := range candidates {
+		if dist := editDistance(key, candidate); dist <= bestDist && (best == "" || dist < editDistance(key, best)) {
+			best, bestDist = candidate, dist
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+// UnknownKeys walks a YAML document and returns an error for every
+// mapping key that the schema does not allow. Keys are checked against
+// the alternatives of `anyOf` schemas that could match the node, so
+// that (for example) the keys of every type of step action are only
+// allowed in steps of that type. Values are not otherwise validated.
+//
+// **Parameters:**
+//
+// node: the YAML document (or node) to check
+// root: the schema of the node - `$ref`s are resolved against its `$defs`
+//
+// **Returns:**
+//
+// []*UnknownKeyError: the unknown keys, in document order
+func UnknownKeys(node *yaml.Node, root *Schema) []*UnknownKeyError {
+	w := walker{root: root}
+	w.walk(node, root)
+	return w.errs
+}
+
+type walker struct {
+	root *Schema
+	errs []*UnknownKeyError
+}
+
+// resolve follows `$ref`s to definitions of the root schema
+func (w *walker) resolve(s *Schema) *Schema {
+	for s != nil && s.Ref != "" {
+		s = w.root.Defs[strings.TrimPrefix(s.Ref, "#/$defs/")]
+	}
+	return s
+}
+
+// candidates returns the alternatives of s
+// that could describe the specified node
+func (w *walker) candidates(node *yaml.Node, s *Schema) []*Schema {
+	s = w.resolve(s)
+	if s == nil {
+		return nil
+	}
+	if len(s.AnyOf) == 0 {
+		return []*Schema{s}
+	}
+
+	var matching []*Schema
+	for _, alt := range s.AnyOf {
+		for _, c := range w.candidates(node, alt) {
+			if kindMatches(node, c) {
+				matching = append(matching, c)
+			}
+		}
+	}
+	if node.Kind != yaml.MappingNode {
+		return matching
+	}
+
+	// the required keys of object alternatives
+	// identify which ones a mapping could match
+	var withRequired []*Schema
+	for _, c := range matching {
+		if len(c.Required) > 0 && hasKeys(node, c.Required) {
+			withRequired = append(withRequired, c)
+		}
+	}
+	if len(withRequired) > 0 {
+		return withRequired
+	}
+	return matching
+}
+
+// kindMatches returns true if the type of the
+// schema allows nodes of the kind of node
+func kindMatches(node *yaml.Node, s *Schema) bool {
+	switch node.Kind {
+	case yaml.MappingNode:
+		return s.Type == "object" || s.Type == ""
+	case yaml.SequenceNode:
+		return s.Type == "array" || s.Type == ""
+	default:
+		return s.Type != "object" && s.Type != "array"
+	}
+}
+
+// hasKeys returns true if the mapping node contains all of the keys
+func hasKeys(node *yaml.Node, keys []string) bool {
+	for _, key := range keys {
+		found := false
+		for idx := 0; idx+1 < len(node.Content); idx += 2 {
+			if node.Content[idx].Value == key {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func (w *walker) walk(node *yaml.Node, s *Schema) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		for _, child := range node.Content {
+			w.walk(child, s)
+		}
+	case yaml.AliasNode:
+		w.walk(node.Alias, s)
+	case yaml.SequenceNode:
+		for _, c := range w.candidates(node, s) {
+			if c.Items != nil {
+				for _, child := range node.Content {
+					w.walk(child, c.Items)
+				}
+				return
+			}
+		}
+	case yaml.MappingNode:
+		w.walkMapping(node, w.candidates(node, s))
+	}
+}
+
+func (w *walker) walkMapping(node *yaml.Node, candidates []*Schema) {
+	if len(candidates) == 0 {
+		return
+	}
+	for idx := 0; idx+1 < len(node.Content); idx += 2 {
+		keyNode, valNode := node.Content[idx], node.Content[idx+1]
+		// merge keys (<<) are resolved by the YAML decoder
+		if keyNode.Tag == "!!merge" {
+			continue
+		}
+		valSchema, known := lookupKey(keyNode.Value, candidates)
+		if !known {
+			w.errs = append(w.errs, &UnknownKeyError{
+				Key:     keyNode.Value,
+				Line:    keyNode.Line,
+				Column:  keyNode.Column,
+				Allowed: allowedKeys(candidates),
+			})
+			continue
+		}
+		if valSchema != nil {
+			w.walk(valNode, valSchema)
+		}
+	}
+}
+
+// lookupKey returns the schema of the value of a key
+// and whether any of the candidates allows the key
+func lookupKey(key string, candidates []*Schema) (*Schema, bool) {
+	for _, c := range candidates {
+		if prop, ok := c.Properties[key]; ok {
+			return prop, true
+		}
+	}
+	for _, c := range candidates {
+		switch additional := c.AdditionalProperties.(type) {
+		case bool:
+			if additional {
+				return nil, true
+			}
+		case *Schema:
+			return additional, true
+		default:
+			// additional properties are allowed unless
+			// an object schema explicitly forbids them
+			if len(c.Properties) == 0 {
+				return nil, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// allowedKeys lists the keys allowed by any of the candidates
+func allowedKeys(candidates []*Schema) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, c := range candidates {
+		for key := range c.Properties {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,315 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+// Package search indexes the TTPs of installed repositories
+// so that they can be searched with a simple query language.
+package search
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/facebookincubator/ttpforge/pkg/blocks"
+	"github.com/facebookincubator/ttpforge/pkg/mitre"
+	"github.com/facebookincubator/ttpforge/pkg/parseutils"
+	"github.com/spf13/afero"
+	"gopkg.in/yaml.v3"
+)
+
+// indexVersion must be incremented whenever the information
+// recorded in the index changes, so that indexes written by
+// previous versions of TTPForge are rebuilt
+const indexVersion = 1
+
+// Entry holds the searchable information about a TTP
+type Entry struct {
+	Ref         string   `json:"ref"`
+	Repo        string   `json:"repo"`
+	Path        string   `json:"path"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	UUID        string   `json:"uuid,omitempty"`
+	Args        []string `json:"args,omitempty"`
+	// Actions lists the types of the actions used by the
+	// steps of the TTP (including their cleanup actions)
+	Actions []string `json:"actions,omitempty"`
+	// Executors lists the executors of the
+	// inline, file and expect steps of the TTP
+	Executors []string `json:"executors,omitempty"`
+	Superuser bool     `json:"superuser"`
+	Platforms []string `json:"platforms,omitempty"`
+	// Mitre lists the MITRE ATT&CK IDs the TTP is mapped to
+	Mitre []string `json:"mitre,omitempty"`
+	// Error is set if the TTP could not be parsed - only
+	// its reference can be searched in that case
+	Error string `json:"error,omitempty"`
+}
+
+// File identifies a TTP file to index
+type File struct {
+	Ref  string
+	Repo string
+	Path string
+}
+
+// record is an entry of the index along with the state of the
+// file it was built from, which determines whether it is stale
+type record struct {
+	Entry   *Entry    `json:"entry"`
+	ModTime time.Time `json:"mod_time"`
+	Size    int64     `json:"size"`
+}
+
+// Index caches the entries of TTP files, so that only the
+// files that have changed since the index was last
+// updated need to be parsed again
+type Index struct {
+	Version int                `json:"version"`
+	Records map[string]*record `json:"records"`
+}
+
+// NewIndex creates an empty index
+func NewIndex() *Index {
+	return &Index{
+		Version: indexVersion,
+		Records: make(map[string]*record),
+	}
+}
+
+// LoadIndex reads an index from a file. An empty index is
+// returned if the file does not exist, cannot be parsed or
+// was written by an incompatible version of TTPForge.
+//
+// **Parameters:**
+//
+// fsys: the filesystem to read the index from
+// path: the path of the index file
+//
+// **Returns:**
+//
+// *Index: the index
+// error: an error if the file exists but cannot be read
+func LoadIndex(fsys afero.Fs, path string) (*Index, error) {
+	contents, err := afero.ReadFile(fsys, path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return NewIndex(), nil
+		}
+		return nil, fmt.Errorf("failed to read search index %v: %w", path, err)
+	}
+	var idx Index
+	if err := json.Unmarshal(contents, &idx); err != nil || idx.Version != indexVersion || idx.Records == nil {
+		return NewIndex(), nil
+	}
+	return &idx, nil
+}
+
+// Save writes the index to a file
+//
+// **Parameters:**
+//
+// fsys: the filesystem to write the index to
+// path: the path of the index file
+//
+// **Returns:**
+//
+// error: an error if the index cannot be written
+func (idx *Index) Save(fsys afero.Fs, path string) error {
+	contents, err := json.Marshal(idx)
+	if err != nil {
+		return fmt.Errorf("failed to marshal search index: %w", err)
+	}
+	if err := fsys.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for search index %v: %w", path, err)
+	}
+	if err := afero.WriteFile(fsys, path, contents, 0644); err != nil {
+		return fmt.Errorf("failed to write search index %v: %w", path, err)
+	}
+	return nil
+}
+
+// Update brings the index in line with the provided TTP files:
+// files that are new or have been modified since they were
+// indexed are parsed, and files that are no longer
+// provided are removed from the index.
+//
+// **Parameters:**
+//
+// fsys: the filesystem containing the TTP files
+// files: all of the TTP files to index
+//
+// **Returns:**
+//
+// bool: whether the index changed
+// error: an error if a TTP file cannot be read
+func (idx *Index) Update(fsys afero.Fs, files []File) (bool, error) {
+	changed := false
+	current := make(map[string]bool, len(files))
+	for _, f := range files {
+		current[f.Path] = true
+		info, err := fsys.Stat(f.Path)
+		if err != nil {
+			return false, fmt.Errorf("failed to stat TTP %v: %w", f.Ref, err)
+		}
+		if r, ok := idx.Records[f.Path]; ok && r.ModTime.Equal(info.ModTime()) && r.Size == info.Size() {
+			// the repository may have been renamed
+			if r.Entry.Ref != f.Ref || r.Entry.Repo != f.Repo {
+				r.Entry.Ref = f.Ref
+				r.Entry.Repo = f.Repo
+				changed = true
+			}
+			continue
+		}
+		contents, err := afero.ReadFile(fsys, f.Path)
+		if err != nil {
+			return false, fmt.Errorf("failed to read TTP %v: %w", f.Ref, err)
+		}
+		idx.Records[f.Path] = &record{
+			Entry:   newEntry(f, contents),
+			ModTime: info.ModTime(),
+			Size:    info.Size(),
+		}
+		changed = true
+	}
+	for path := range idx.Records {
+		if !current[path] {
+			delete(idx.Records, path)
+			changed = true
+		}
+	}
+	return changed, nil
+}
+
+// Entries returns the entries of the index, sorted by reference
+func (idx *Index) Entries() []*Entry {
+	entries := make([]*Entry, 0, len(idx.Records))
+	for _, r := range idx.Records {
+		entries = append(entries, r.Entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Ref < entries[j].Ref
+	})
+	return entries
+}
+
+// newEntry extracts the searchable information from a TTP file
+func newEntry(f File, contents []byte) *Entry {
+	entry := &Entry{
+		Ref:  f.Ref,
+		Repo: f.Repo,
+		Path: f.Path,
+	}
+	ttp, err := parseutils.ParseTTP(parseutils.StripTemplates(contents), f.Path)
+	if err != nil {
+		entry.Error = err.Error()
+		return entry
+	}
+	entry.Name = ttp.Name
+	entry.Description = ttp.Description
+	entry.UUID = ttp.UUID
+	entry.Superuser = ttp.Requirements.Superuser
+	for _, arg := range ttp.Args {
+		entry.Args = append(entry.Args, arg.Name)
+	}
+	for _, platform := range ttp.Requirements.Platforms {
+		if platform.OS != "" && !slices.Contains(entry.Platforms, platform.OS) {
+			entry.Platforms = append(entry.Platforms, platform.OS)
+		}
+	}
+	for _, entries := range [][]string{ttp.Mitre.Tactics, ttp.Mitre.Techniques, ttp.Mitre.Subtechniques} {
+		for _, mitreEntry := range entries {
+			if id, _ := mitre.ParseEntry(mitreEntry); id != "" {
+				entry.Mitre = append(entry.Mitre, id)
+			}
+		}
+	}
+
+	var steps struct {
+		Steps []yaml.Node `yaml:"steps"`
+	}
+	if err := yaml.Unmarshal(parseutils.StripTemplates(contents), &steps); err != nil {
+		entry.Error = err.Error()
+		return entry
+	}
+	for i := range steps.Steps {
+		entry.addStep(&steps.Steps[i])
+	}
+	sort.Strings(entry.Actions)
+	sort.Strings(entry.Executors)
+	return entry
+}
+
+// addStep records the action types and executors used by a
+// step, its cleanup action and the steps nested within it
+func (e *Entry) addStep(step *yaml.Node) {
+	if step.Kind != yaml.MappingNode {
+		return
+	}
+	fields := make(map[string]*yaml.Node)
+	for i := 0; i+1 < len(step.Content); i += 2 {
+		fields[step.Content[i].Value] = step.Content[i+1]
+	}
+	actionType := ""
+	for _, key := range blocks.ActionTypes() {
+		if _, ok := fields[key]; ok {
+			actionType = key
+			break
+		}
+	}
+	if actionType != "" && !slices.Contains(e.Actions, actionType) {
+		e.Actions = append(e.Actions, actionType)
+	}
+
+	var executor string
+	if node, ok := fields["executor"]; ok {
+		executor = node.Value
+	}
+	switch actionType {
+	case "inline", "expect":
+		if executor == "" {
+			executor = blocks.ExecutorBash
+		}
+		e.addExecutor(executor)
+	case "file":
+		if executor == "" {
+			executor = blocks.InferExecutor(fields["file"].Value)
+		}
+		e.addExecutor(executor)
+	}
+
+	if cleanup, ok := fields["cleanup"]; ok {
+		e.addStep(cleanup)
+	}
+	if parallel, ok := fields["parallel"]; ok && parallel.Kind == yaml.SequenceNode {
+		for _, child := range parallel.Content {
+			e.addStep(child)
+		}
+	}
+}
+
+func (e *Entry) addExecutor(executor string) {
+	if !slices.Contains(e.Executors, executor) {
+		e.Executors = append(e.Executors, executor)
+	}
+}
@@ -0,0 +1,157 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package search
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const indexedTTP = `---
+api_version: 2.0
+uuid: 2f1c7e4a-6d3b-4e8f-9a0c-1b2d3e4f5a6b
+name: Indexed TTP
+description: Fetches and runs a payload
+requirements:
+  superuser: true
+  platforms:
+    - os: linux
+    - os: darwin
+      arch: arm64
+mitre:
+  tactics:
+    - TA0002 Execution
+  techniques:
+    - T1059 Command and Scripting Interpreter
+args:
+  - name: url
+  - name: target
+{{ if .Args.target }}
+steps:
+  - name: fetch
+    fetch_uri: "{{ .Args.url }}"
+    location: /tmp/payload.py
+    cleanup:
+      remove_path: /tmp/payload.py
+  - name: run
+    file: /tmp/payload.py
+  - name: in-parallel
+    parallel:
+      - name: ruby
+        inline: puts 1
+        executor: ruby
+      - name: shell
+        inline: echo {{ .Args.target }}
+{{ end }}
+`
+
+func TestNewEntry(t *testing.T) {
+	entry := newEntry(File{Ref: "repo//indexed.yaml", Repo: "repo", Path: "/repo/ttps/indexed.yaml"}, []byte(indexedTTP))
+	assert.Equal(t, &Entry{
+		Ref:         "repo//indexed.yaml",
+		Repo:        "repo",
+		Path:        "/repo/ttps/indexed.yaml",
+		Name:        "Indexed TTP",
+		Description: "Fetches and runs a payload",
+		UUID:        "2f1c7e4a-6d3b-4e8f-9a0c-1b2d3e4f5a6b",
+		Args:        []string{"url", "target"},
+		Actions:     []string{"fetch_uri", "file", "inline", "parallel", "remove_path"},
+		Executors:   []string{"bash", "python3", "ruby"},
+		Superuser:   true,
+		Platforms:   []string{"linux", "darwin"},
+		Mitre:       []string{"TA0002", "T1059"},
+	}, entry)
+}
+
+func TestNewEntryInvalid(t *testing.T) {
+	entry := newEntry(File{Ref: "repo//invalid.yaml", Repo: "repo", Path: "/repo/ttps/invalid.yaml"}, []byte("name: [unterminated"))
+	assert.Equal(t, "repo//invalid.yaml", entry.Ref)
+	assert.NotEmpty(t, entry.Error)
+}
+
+func TestIndexUpdate(t *testing.T) {
+	fsys := afero.NewMemMapFs()
+	const indexPath = "/home/user/.ttpforge/search-index.json"
+	files := []File{
+		{Ref: "repo//a.yaml", Repo: "repo", Path: "/repo/ttps/a.yaml"},
+		{Ref: "repo//b.yaml", Repo: "repo", Path: "/repo/ttps/b.yaml"},
+	}
+	require.NoError(t, afero.WriteFile(fsys, files[0].Path, []byte("name: first\n"), 0644))
+	require.NoError(t, afero.WriteFile(fsys, files[1].Path, []byte("name: second\n"), 0644))
+
+	// a missing index file yields an empty index
+	idx, err := LoadIndex(fsys, indexPath)
+	require.NoError(t, err)
+	changed, err := idx.Update(fsys, files)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	require.NoError(t, idx.Save(fsys, indexPath))
+
+	// nothing has changed since the index was saved
+	idx, err = LoadIndex(fsys, indexPath)
+	require.NoError(t, err)
+	changed, err = idx.Update(fsys, files)
+	require.NoError(t, err)
+	assert.False(t, changed)
+	require.Len(t, idx.Entries(), 2)
+	assert.Equal(t, "first", idx.Entries()[0].Name)
+
+	// modified files are indexed again
+	require.NoError(t, afero.WriteFile(fsys, files[0].Path, []byte("name: modified\n"), 0644))
+	require.NoError(t, fsys.Chtimes(files[0].Path, time.Now(), time.Now().Add(time.Minute)))
+	changed, err = idx.Update(fsys, files)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "modified", idx.Entries()[0].Name)
+
+	// removed files are removed from the index
+	changed, err = idx.Update(fsys, files[1:])
+	require.NoError(t, err)
+	assert.True(t, changed)
+	require.Len(t, idx.Entries(), 1)
+	assert.Equal(t, "second", idx.Entries()[0].Name)
+
+	// files that cannot be read are reported
+	_, err = idx.Update(fsys, []File{{Ref: "repo//missing.yaml", Path: "/repo/ttps/missing.yaml"}})
+	require.Error(t, err)
+}
+
+func TestLoadIndexIncompatible(t *testing.T) {
+	fsys := afero.NewMemMapFs()
+	testCases := []struct {
+		name     string
+		contents string
+	}{
+		{name: "Corrupt", contents: "{"},
+		{name: "Old Version", contents: `{"version": 0, "records": {"/a.yaml": {"entry": {"ref": "repo//a.yaml"}}}}`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.NoError(t, afero.WriteFile(fsys, "/index.json", []byte(tc.contents), 0644))
+			idx, err := LoadIndex(fsys, "/index.json")
+			require.NoError(t, err)
+			assert.Empty(t, idx.Entries())
+		})
+	}
+}
@@ -0,0 +1,261 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package search
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/facebookincubator/ttpforge/pkg/blocks"
+)
+
+// fields maps the fields that can be used in queries (as in
+// `uses:http_request`) to functions that return true if an
+// entry matches the value of a term for that field
+var fields = map[string]func(e *Entry, value string) bool{
+	// the name contains the value
+	"name": func(e *Entry, value string) bool {
+		return containsFold(e.Name, value)
+	},
+	// the description contains the value
+	"description": func(e *Entry, value string) bool {
+		return containsFold(e.Description, value)
+	},
+	// the UUID starts with the value
+	"uuid": func(e *Entry, value string) bool {
+		return strings.HasPrefix(strings.ToLower(e.UUID), strings.ToLower(value))
+	},
+	// the TTP has an argument with this name
+	"arg": func(e *Entry, value string) bool {
+		return containsEqualFold(e.Args, value)
+	},
+	// a step or cleanup action has this type
+	"uses": func(e *Entry, value string) bool {
+		return slices.Contains(e.Actions, value)
+	},
+	// an inline, file or expect step uses this executor
+	"executor": func(e *Entry, value string) bool {
+		return containsEqualFold(e.Executors, value)
+	},
+	// the TTP does (true) or does not (false) require superuser privileges
+	"superuser": func(e *Entry, value string) bool {
+		superuser, _ := strconv.ParseBool(value)
+		return e.Superuser == superuser
+	},
+	// the TTP is in this repository
+	"repo": func(e *Entry, value string) bool {
+		return e.Repo == value
+	},
+	// the TTP supports this platform - any matches
+	// the TTPs that do not restrict platforms
+	"platform": func(e *Entry, value string) bool {
+		if strings.EqualFold(value, "any") {
+			return len(e.Platforms) == 0
+		}
+		return containsEqualFold(e.Platforms, value)
+	},
+	// the TTP is mapped to this MITRE ATT&CK
+	// tactic, technique or sub-technique ID
+	"mitre": func(e *Entry, value string) bool {
+		return containsEqualFold(e.Mitre, value)
+	},
+}
+
+// fieldRegexp matches the field name at the start of a term
+var fieldRegexp = regexp.MustCompile(`(?s)^([a-z_]+):(.*)$`)
+
+// term is a single condition of a query
+type term struct {
+	// field is empty for full-text terms
+	field  string
+	value  string
+	negate bool
+}
+
+// Query is a parsed search query - an entry matches
+// the query if it matches all of its terms
+type Query struct {
+	terms []term
+}
+
+// ParseQuery parses a search query. A query is a list of terms
+// separated by whitespace, all of which must match:
+//
+// * `field:value` matches the entries whose field matches the value - the
+// fields are name, description (or desc), uuid, arg, uses, executor,
+// superuser, repo, platform and mitre
+// * any other term matches the entries whose name, description, reference,
+// UUID or argument names contain it (ignoring case)
+// * a term prefixed with `-` matches the entries that the term does not match
+//
+// Double quotes group words into a single term, as in `name:"dump credentials"`.
+//
+// **Parameters:**
+//
+// query: the query to parse
+//
+// **Returns:**
+//
+// *Query: the parsed query
+// error: an error if the query is invalid
+func ParseQuery(query string) (*Query, error) {
+	tokens, err := tokenize(query)
+	if err != nil {
+		return nil, err
+	}
+	q := &Query{}
+	for _, token := range tokens {
+		t := term{value: token}
+		if len(t.value) > 1 && strings.HasPrefix(t.value, "-") {
+			t.negate = true
+			t.value = t.value[1:]
+		}
+		if match := fieldRegexp.FindStringSubmatch(t.value); match != nil {
+			t.field, t.value = match[1], match[2]
+			if t.field == "desc" {
+				t.field = "description"
+			}
+			if err := validateFieldTerm(t); err != nil {
+				return nil, err
+			}
+		}
+		q.terms = append(q.terms, t)
+	}
+	return q, nil
+}
+
+// validateFieldTerm checks that the field of a term
+// exists and that its value is valid for that field
+func validateFieldTerm(t term) error {
+	if _, ok := fields[t.field]; !ok {
+		names := make([]string, 0, len(fields))
+		for name := range fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("unknown search field %q - valid fields are: %v", t.field, strings.Join(names, ", "))
+	}
+	if t.value == "" {
+		return fmt.Errorf("missing value for search field %q", t.field)
+	}
+	switch t.field {
+	case "uses":
+		if !slices.Contains(blocks.ActionTypes(), t.value) {
+			return fmt.Errorf("unknown action type %q - valid action types are: %v", t.value, strings.Join(blocks.ActionTypes(), ", "))
+		}
+	case "superuser":
+		if _, err := strconv.ParseBool(t.value); err != nil {
+			return fmt.Errorf("invalid value %q for search field superuser - must be true or false", t.value)
+		}
+	}
+	return nil
+}
+
+// tokenize splits a query into terms at whitespace
+// that is not enclosed in double quotes
+func tokenize(query string) ([]string, error) {
+	var tokens []string
+	var current strings.Builder
+	inToken := false
+	inQuotes := false
+	for _, r := range query {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			inToken = true
+		case unicode.IsSpace(r) && !inQuotes:
+			if inToken {
+				tokens = append(tokens, current.String())
+				current.Reset()
+				inToken = false
+			}
+		default:
+			current.WriteRune(r)
+			inToken = true
+		}
+	}
+	if inQuotes {
+		return nil, fmt.Errorf("unterminated quote in search query %q", query)
+	}
+	if inToken {
+		tokens = append(tokens, current.String())
+	}
+	return tokens, nil
+}
+
+// Matches returns true if the entry matches every term of the query
+func (q *Query) Matches(e *Entry) bool {
+	for _, t := range q.terms {
+		var matched bool
+		if t.field == "" {
+			matched = matchesText(e, t.value)
+		} else {
+			matched = fields[t.field](e, t.value)
+		}
+		if matched == t.negate {
+			return false
+		}
+	}
+	return true
+}
+
+// Search returns the entries that match the query, sorted by reference
+//
+// **Parameters:**
+//
+// q: the query to match entries against
+//
+// **Returns:**
+//
+// []*Entry: the matching entries
+func (idx *Index) Search(q *Query) []*Entry {
+	var results []*Entry
+	for _, e := range idx.Entries() {
+		if q.Matches(e) {
+			results = append(results, e)
+		}
+	}
+	return results
+}
+
+// matchesText implements full-text terms
+func matchesText(e *Entry, text string) bool {
+	for _, s := range append([]string{e.Name, e.Description, e.Ref, e.UUID}, e.Args...) {
+		if containsFold(s, text) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(s string, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+func containsEqualFold(values []string, value string) bool {
+	return slices.ContainsFunc(values, func(v string) bool {
+		return strings.EqualFold(v, value)
+	})
+}
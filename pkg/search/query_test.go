@@ -0,0 +1,199 @@
+/*
+Copyright © 2025-present, Meta Platforms, Inc. and affiliates
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestIndex() *Index {
+	idx := NewIndex()
+	for _, e := range []*Entry{
+		{
+			Ref:         "examples//credentials/dump.yaml",
+			Repo:        "examples",
+			Name:        "Dump Credentials",
+			Description: "Dumps credentials from memory",
+			UUID:        "aaaa1111-0000-0000-0000-000000000000",
+			Args:        []string{"pid"},
+			Actions:     []string{"inline"},
+			Executors:   []string{"bash"},
+			Superuser:   true,
+			Platforms:   []string{"linux"},
+			Mitre:       []string{"TA0006", "T1003"},
+		},
+		{
+			Ref:       "examples//network/exfil.yaml",
+			Repo:      "examples",
+			Name:      "Exfiltrate Over HTTP",
+			UUID:      "bbbb2222-0000-0000-0000-000000000000",
+			Args:      []string{"url"},
+			Actions:   []string{"http_request", "inline"},
+			Executors: []string{"python3"},
+		},
+		{
+			Ref:       "private//windows/dump.yaml",
+			Repo:      "private",
+			Name:      "Dump LSASS",
+			Actions:   []string{"file"},
+			Executors: []string{"powershell"},
+			Platforms: []string{"windows"},
+		},
+	} {
+		idx.Records[e.Ref] = &record{Entry: e}
+	}
+	return idx
+}
+
+func TestSearch(t *testing.T) {
+	testCases := []struct {
+		name         string
+		query        string
+		expectedRefs []string
+	}{
+		{
+			name:         "Empty Query",
+			query:        "",
+			expectedRefs: []string{"examples//credentials/dump.yaml", "examples//network/exfil.yaml", "private//windows/dump.yaml"},
+		},
+		{
+			name:         "Full Text",
+			query:        "DUMP",
+			expectedRefs: []string{"examples//credentials/dump.yaml", "private//windows/dump.yaml"},
+		},
+		{
+			name:         "Full Text Matches Args",
+			query:        "url",
+			expectedRefs: []string{"examples//network/exfil.yaml"},
+		},
+		{
+			name:         "Quoted Phrase",
+			query:        `name:"dump credentials"`,
+			expectedRefs: []string{"examples//credentials/dump.yaml"},
+		},
+		{
+			name:         "Description Alias",
+			query:        "desc:memory",
+			expectedRefs: []string{"examples//credentials/dump.yaml"},
+		},
+		{
+			name:         "UUID Prefix",
+			query:        "uuid:BBBB",
+			expectedRefs: []string{"examples//network/exfil.yaml"},
+		},
+		{
+			name:         "Uses",
+			query:        "uses:inline",
+			expectedRefs: []string{"examples//credentials/dump.yaml", "examples//network/exfil.yaml"},
+		},
+		{
+			name:         "Several Terms",
+			query:        "uses:inline executor:python3 arg:URL",
+			expectedRefs: []string{"examples//network/exfil.yaml"},
+		},
+		{
+			name:         "Superuser",
+			query:        "superuser:false",
+			expectedRefs: []string{"examples//network/exfil.yaml", "private//windows/dump.yaml"},
+		},
+		{
+			name:         "Repo",
+			query:        "repo:private",
+			expectedRefs: []string{"private//windows/dump.yaml"},
+		},
+		{
+			name:         "Any Platform",
+			query:        "platform:any",
+			expectedRefs: []string{"examples//network/exfil.yaml"},
+		},
+		{
+			name:         "Negated Term",
+			query:        "dump -platform:windows",
+			expectedRefs: []string{"examples//credentials/dump.yaml"},
+		},
+		{
+			name:         "MITRE ID",
+			query:        "mitre:t1003",
+			expectedRefs: []string{"examples//credentials/dump.yaml"},
+		},
+		{
+			name:  "No Match",
+			query: "kerberos",
+		},
+	}
+
+	idx := newTestIndex()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q, err := ParseQuery(tc.query)
+			require.NoError(t, err)
+			var refs []string
+			for _, e := range idx.Search(q) {
+				refs = append(refs, e.Ref)
+			}
+			assert.Equal(t, tc.expectedRefs, refs)
+		})
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	testCases := []struct {
+		name          string
+		query         string
+		expectedError string
+	}{
+		{
+			name:          "Unknown Field",
+			query:         "owner:me",
+			expectedError: `unknown search field "owner"`,
+		},
+		{
+			name:          "Missing Value",
+			query:         "name:",
+			expectedError: `missing value for search field "name"`,
+		},
+		{
+			name:          "Unknown Action Type",
+			query:         "uses:http",
+			expectedError: `unknown action type "http"`,
+		},
+		{
+			name:          "Invalid Boolean",
+			query:         "superuser:maybe",
+			expectedError: "must be true or false",
+		},
+		{
+			name:          "Unterminated Quote",
+			query:         `name:"dump`,
+			expectedError: "unterminated quote",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseQuery(tc.query)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedError)
+		})
+	}
+}